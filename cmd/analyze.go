@@ -2,73 +2,183 @@ package cmd
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/spf13/cobra"
 
 	"github.com/Glider2355/ddl-lock-analyzer/internal/fkresolver"
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/osc"
 	"github.com/Glider2355/ddl-lock-analyzer/internal/parser"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/policy"
 	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
 	"github.com/Glider2355/ddl-lock-analyzer/internal/reporter"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/rollback"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/simulator"
 )
 
 var (
-	flagSQL      string
-	flagHost     string
-	flagPort     int
-	flagUser     string
-	flagPassword string
-	flagDatabase string
-	flagFormat   string
+	flagSQL            string
+	flagFiles          []string
+	flagHost           string
+	flagPort           int
+	flagUser           string
+	flagPassword       string
+	flagDatabase       string
+	flagFormat         string
+	flagOffline        bool
+	flagMetaFile       string
+	flagSchemaFile     string
+	flagMySQLVersion   string
+	flagFailOn         string
+	flagProfile        string
+	flagRiskThresholds string
+	flagPolicy         string
+	flagRulesFile      string
+	flagExplain        bool
+	flagGuardedSQL     string
+	flagRollback       bool
+
+	flagRebuildThroughput   float64
+	flagCopyThroughput      float64
+	flagIndexScanThroughput float64
+	flagIndexRowsPerSec     float64
 )
 
 var analyzeCmd = &cobra.Command{
-	Use:   "analyze",
+	Use:   "analyze [file|dir|-]...",
 	Short: "Analyze ALTER TABLE statements and predict lock impact",
-	RunE:  runAnalyze,
+	Long: "analyze predicts the lock impact of ALTER TABLE statements given by --sql, --file or file arguments. " +
+		"A directory analyzes every *.sql file in it in lexical order, and \"-\" reads from stdin.",
+	RunE: runAnalyze,
 }
 
 func init() {
 	f := analyzeCmd.Flags()
 	f.StringVar(&flagSQL, "sql", "", "ALTER TABLE statement to analyze")
-	f.StringVar(&flagHost, "host", "localhost", "MySQL host")
-	f.IntVar(&flagPort, "port", 3306, "MySQL port")
-	f.StringVar(&flagUser, "user", "", "MySQL user")
-	f.StringVar(&flagPassword, "password", "", "MySQL password")
-	f.StringVar(&flagDatabase, "database", "", "Database name")
+	f.StringArrayVar(&flagFiles, "file", nil, "SQL file or directory of *.sql files to analyze (\"-\" for stdin, repeatable)")
+	addConnectionFlags(analyzeCmd)
 	f.StringVar(&flagFormat, "format", "text", "Output format: text|json")
+	f.BoolVar(&flagOffline, "offline", false, "Offline mode (no MySQL connection)")
+	f.StringVar(&flagMetaFile, "meta-file", "", "Table metadata JSON file for offline mode (implies --offline)")
+	f.StringVar(&flagSchemaFile, "schema-file", "", "Schema dump with CREATE TABLE statements for offline mode (implies --offline)")
+	f.StringVar(&flagMySQLVersion, "mysql-version", "", "MySQL server version to assume in offline mode (default \"8.0\")")
+	defaults := predictor.DefaultDurationSettings()
+	f.Float64Var(&flagRebuildThroughput, "rebuild-throughput", defaults.RebuildBytesPerSec/float64(predictor.MB), "Table rebuild throughput in MB/s used for duration estimates (INPLACE)")
+	f.Float64Var(&flagCopyThroughput, "copy-throughput", defaults.CopyBytesPerSec/float64(predictor.MB), "Table copy throughput in MB/s used for duration estimates (ALGORITHM=COPY)")
+	f.Float64Var(&flagIndexScanThroughput, "index-scan-throughput", defaults.IndexScanBytesPerSec/float64(predictor.MB), "Table scan throughput in MB/s used for index build estimates")
+	f.Float64Var(&flagIndexRowsPerSec, "index-rows-per-sec", defaults.IndexRowsPerSec, "Rows per second inserted into each secondary index during builds")
+	f.StringVar(&flagProfile, "profile", "", "Use duration coefficients fitted by calibrate --name <profile> (throughput flags override it)")
+	f.StringVar(&flagRiskThresholds, "risk-thresholds", "", "Risk score thresholds for MEDIUM,HIGH,CRITICAL (default \"20,45,70\")")
+	f.StringVar(&flagPolicy, "policy", "", "Policy file (default: "+policy.DefaultFileName+" in the current directory if present)")
+	f.StringVar(&flagRulesFile, "rules-file", "", "YAML/JSON file with custom prediction rules evaluated before the built-in rules")
+	f.BoolVar(&flagExplain, "explain", false, "Show how each prediction rule was evaluated and why the selected rule matched")
+	f.StringVar(&flagGuardedSQL, "emit-guarded-sql", "", "Write the input SQL with ALGORITHM/LOCK clauses appended to each ALTER TABLE to this file (\"-\" for stdout; the report then goes to stderr)")
+	f.BoolVar(&flagRollback, "rollback", false, "Generate rollback DDL for each ALTER TABLE and predict its lock impact")
+	f.StringVar(&flagFailOn, "fail-on", "", "Exit with code 4 if any statement has this risk level or higher: LOW|MEDIUM|HIGH|CRITICAL")
+}
+
+// sourceOperation はALTER操作と読み込み元ファイルの組。
+type sourceOperation struct {
+	file string
+	// src は sqlSource の添字
+	src int
+	op  meta.AlterOperation
 }
 
-func runAnalyze(_ *cobra.Command, _ []string) error {
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	var failOn meta.RiskLevel
+	if flagFailOn != "" {
+		level, err := parseRiskLevel(flagFailOn)
+		if err != nil {
+			return fmt.Errorf("invalid --fail-on: %w", err)
+		}
+		failOn = level
+	}
+	thresholds := predictor.DefaultRiskThresholds()
+	if flagRiskThresholds != "" {
+		t, err := predictor.ParseRiskThresholds(flagRiskThresholds)
+		if err != nil {
+			return fmt.Errorf("invalid --risk-thresholds: %w", err)
+		}
+		thresholds = t
+	}
+	// 以降のエラーは使い方の誤りではないため usage を表示しない
+	cmd.SilenceUsage = true
+
+	// ポリシーの閾値・fail_on はフラグで明示指定されていない場合のみ使う
+	pol, err := loadPolicy(flagPolicy)
+	if err != nil {
+		return err
+	}
+	if pol != nil {
+		if pol.RiskThresholds != nil && flagRiskThresholds == "" {
+			thresholds = *pol.RiskThresholds
+		}
+		if pol.FailOn != "" && flagFailOn == "" {
+			failOn = pol.FailOn
+		}
+	}
+
+	durationSettings, err := durationSettingsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	var customRules *predictor.CustomRuleFile
+	if flagRulesFile != "" {
+		customRules, err = predictor.LoadCustomRulesFile(flagRulesFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	// SQL入力を取得
-	sqlText, err := getSQLInput()
+	sources, err := collectSQLSources(flagSQL, append(append([]string{}, flagFiles...), args...), cmd.InOrStdin())
 	if err != nil {
 		return err
 	}
 
 	// SQLをパース
-	ops, err := parser.Parse(sqlText)
+	ops, err := parseSources(sources)
 	if err != nil {
-		return fmt.Errorf("parse error: %w", err)
+		return withExitCode(ExitParseError, err)
 	}
 
 	// コレクターを初期化
 	collector, db, err := initCollector()
 	if err != nil {
-		return err
+		return withExitCode(ExitMetadataError, err)
 	}
 	if db != nil {
 		defer func() { _ = db.Close() }()
 	}
 
-	// レポートを構築
-	pred := predictor.New()
+	// 前の文を適用した後のスキーマで後続の文を予測する
+	sim := simulator.New(collector)
+
+	// レポートを構築（接続先または --mysql-version のバージョンに応じたルールで予測する）
+	opts := []predictor.Option{
+		predictor.WithMySQLVersion(collector.GetMySQLVersion()),
+		predictor.WithDurationSettings(durationSettings),
+		predictor.WithRiskThresholds(thresholds),
+		predictor.WithCustomRules(customRules),
+	}
+	if flagExplain {
+		opts = append(opts, predictor.WithExplain())
+	}
+	pred := predictor.New(opts...)
 	report := &reporter.Report{}
+	guards := make(map[int][]parser.GuardClause)
+	vars := serverVariables(collector)
+	conn := osc.Connection{Host: flagHost, Port: flagPort, User: flagUser}
 
-	for _, op := range ops {
+	for _, so := range ops {
+		op := so.op
 		tableName := op.Table
 		if op.Schema != "" {
 			tableName = op.Schema + "." + op.Table
@@ -79,30 +189,95 @@ func runAnalyze(_ *cobra.Command, _ []string) error {
 		if schema == "" {
 			schema = flagDatabase
 		}
-		tableMeta, metaErr := collector.GetTableMeta(schema, op.Table)
+		tableMeta, metaErr := sim.GetTableMeta(schema, op.Table)
 		if metaErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to get table metadata for %s.%s: %v\n", schema, op.Table, metaErr)
 		}
+		printCollectorWarnings(collector)
+
+		// MySQLが即座にエラーにする変更をテーブル定義と照合して検出
+		findings := predictor.Validate(op, tableMeta)
 
 		// ロック動作を予測
 		predictions := pred.PredictAll(op, tableMeta)
 
-		// FK依存関係を解決
-		fkProvider := &collectorAdapter{collector: collector}
+		// FK依存関係を解決し、MDLが伝播するテーブル数をリスクスコアに反映
+		fkProvider := &collectorAdapter{collector: sim}
 		resolver := fkresolver.NewResolver(fkProvider, 5, true)
 		fkGraph, fkErr := resolver.Resolve(schema, op.Table, op.Actions)
 		if fkErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to resolve FK dependencies for %s.%s: %v\n", schema, op.Table, fkErr)
 		}
+		if fkGraph != nil {
+			predictor.ApplyFKPropagation(predictions, fkGraph.TotalAffectedTables())
+		}
+
+		// 文全体として実行されるアルゴリズム・ロックを判定
+		verdict := predictor.CombinePredictions(predictions)
+
+		// INSTANT の操作が再構築に巻き込まれる場合は分割案を作成
+		split := predictor.RecommendSplit(op, predictions)
+
+		// 明示指定された ALGORITHM/LOCK 句を検証
+		findings = append(findings, predictor.CheckClauses(op, predictions)...)
+
+		// プロジェクトのポリシーを評価
+		findings = append(findings, pol.Evaluate(policy.Statement{
+			Schema:    schema,
+			Operation: op,
+			Verdict:   verdict,
+			TableMeta: tableMeta,
+		})...)
+
+		// COPY またはブロッキングな文はオンラインスキーマ変更ツールの実行計画を作成し、
+		// テーブル再構築を含めてネイティブのオンラインDDLとコストを比較する
+		target := osc.Target{
+			Schema:       schema,
+			Operation:    op,
+			TableMeta:    tableMeta,
+			Variables:    vars,
+			MySQLVersion: collector.GetMySQLVersion(),
+			FKGraph:      fkGraph,
+		}
+		var plans []osc.Plan
+		if osc.NeedsOnlineTool(verdict) {
+			plans = append(plans, osc.Ghost(target, conn), osc.PTOSC(target, conn))
+		}
+		var comparison *osc.Comparison
+		if osc.NeedsComparison(verdict) {
+			comparison = osc.Compare(target, predictions, verdict, durationSettings)
+		}
+
+		// 元に戻す DDL は変更前のテーブル定義から作成する
+		var rb *rollback.Plan
+		if flagRollback {
+			rb = rollback.Build(op, tableMeta)
+		}
 
 		analysis := reporter.AnalysisResult{
-			Table:       tableName,
-			SQL:         op.RawSQL,
-			Predictions: predictions,
-			FKGraph:     fkGraph,
-			TableMeta:   tableMeta,
+			File:               so.file,
+			Table:              tableName,
+			SQL:                op.RawSQL,
+			Predictions:        predictions,
+			Verdict:            &verdict,
+			Split:              split,
+			FKGraph:            fkGraph,
+			Findings:           findings,
+			OnlineSchemaChange: plans,
+			Comparison:         comparison,
+			Rollback:           rb,
+			TableMeta:          tableMeta,
 		}
 		report.Analyses = append(report.Analyses, analysis)
+		guards[so.src] = append(guards[so.src], parser.GuardClause{Algorithm: verdict.Algorithm, Lock: verdict.Lock})
+
+		sim.Apply(tableMeta, op, verdict.Algorithm, verdict.TableRebuild)
+
+		// 元に戻す文のロック動作は変更後のテーブル定義で予測する（取得できない場合は上で警告済み）
+		if rb != nil {
+			after, _ := sim.GetTableMeta(schema, rb.Operation.Table)
+			rb.Predict(pred, after)
+		}
 	}
 
 	// 出力をレンダリング
@@ -119,40 +294,258 @@ func runAnalyze(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("render error: %w", err)
 	}
 
-	fmt.Println(output)
+	reportOut := os.Stdout
+	if flagGuardedSQL != "" {
+		if err := writeGuardedSQL(flagGuardedSQL, sources, guards); err != nil {
+			return err
+		}
+		if flagGuardedSQL == stdinPath {
+			reportOut = os.Stderr
+		}
+	}
+	fmt.Fprintln(reportOut, output)
+
+	if n := countRejectedStatements(report); n > 0 {
+		return withExitCode(ExitStatementRejected, fmt.Errorf("%d statement(s) would be rejected by MySQL", n))
+	}
+	if n := countPolicyViolations(report); n > 0 {
+		return withExitCode(ExitPolicyViolation, fmt.Errorf("%d statement(s) violate the policy", n))
+	}
+	if failOn != "" {
+		if worst := report.WorstRiskLevel(); reporter.RiskAtLeast(worst, failOn) {
+			return withExitCode(ExitRiskThreshold, fmt.Errorf("risk level %s meets --fail-on=%s", worst, failOn))
+		}
+	}
 	return nil
 }
 
-func getSQLInput() (string, error) {
-	if flagSQL != "" {
-		return flagSQL, nil
+// loadPolicy は --policy で指定された、またはカレントディレクトリのポリシーファイルを読み込む。
+// 指定がなくデフォルトのファイルもない場合は nil を返す。
+func loadPolicy(path string) (*policy.Policy, error) {
+	if path == "" {
+		found, err := policy.Find(".")
+		if err != nil || found == "" {
+			return nil, err
+		}
+		path = found
+	}
+	return policy.LoadFile(path)
+}
+
+// countRejectedStatements は MySQL が拒否する ERROR の検出事項を含む文の数を返す。
+func countRejectedStatements(report *reporter.Report) int {
+	n := 0
+	for _, a := range report.Analyses {
+		if predictor.HasRejection(a.Findings) {
+			n++
+		}
+	}
+	return n
+}
+
+// countPolicyViolations は ERROR のポリシー違反を含む文の数を返す。
+func countPolicyViolations(report *reporter.Report) int {
+	n := 0
+	for _, a := range report.Analyses {
+		if policy.HasViolation(a.Findings) {
+			n++
+		}
 	}
-	return "", fmt.Errorf("--sql must be specified")
+	return n
+}
+
+// durationSettingsFromFlags は推定実行時間の係数を作成する。
+// --profile の係数をベースに、明示指定されたスループットフラグで上書きする。
+func durationSettingsFromFlags(cmd *cobra.Command) (predictor.DurationSettings, error) {
+	s := predictor.DefaultDurationSettings()
+	if flagProfile != "" {
+		loaded, err := loadProfileSettings(flagProfile)
+		if err != nil {
+			return s, err
+		}
+		s = loaded
+	}
+	f := cmd.Flags()
+	if flagProfile == "" || f.Changed("rebuild-throughput") {
+		s.RebuildBytesPerSec = flagRebuildThroughput * float64(predictor.MB)
+	}
+	if flagProfile == "" || f.Changed("copy-throughput") {
+		s.CopyBytesPerSec = flagCopyThroughput * float64(predictor.MB)
+	}
+	if flagProfile == "" || f.Changed("index-scan-throughput") {
+		s.IndexScanBytesPerSec = flagIndexScanThroughput * float64(predictor.MB)
+	}
+	if flagProfile == "" || f.Changed("index-rows-per-sec") {
+		s.IndexRowsPerSec = flagIndexRowsPerSec
+	}
+	return s, nil
+}
+
+// parseSources は各入力のALTER文をパースする。
+// ファイル入力のうちALTER文を含まないもの（CREATE TABLE のみのマイグレーション等）はスキップする。
+func parseSources(sources []sqlSource) ([]sourceOperation, error) {
+	var result []sourceOperation
+	for i, src := range sources {
+		ops, err := parser.Parse(src.SQL)
+		if err != nil {
+			if errors.Is(err, parser.ErrNoAlterStatements) && src.Path != "" {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", src.Path, err)
+				continue
+			}
+			if src.Path != "" {
+				return nil, fmt.Errorf("parse error in %s: %w", src.Path, err)
+			}
+			return nil, fmt.Errorf("parse error: %w", err)
+		}
+		for _, op := range ops {
+			result = append(result, sourceOperation{file: src.Path, src: i, op: op})
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("parse error: %w", parser.ErrNoAlterStatements)
+	}
+	return result, nil
+}
+
+// printCollectorWarnings はメタデータの取得中にコレクターが記録した警告を標準エラー出力に出す。
+func printCollectorWarnings(collector meta.Collector) {
+	provider, ok := collector.(meta.WarningsProvider)
+	if !ok {
+		return
+	}
+	for _, w := range provider.TakeWarnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+}
+
+// serverVariables はコレクターからサーバー変数を取得する。取得できない場合は nil を返す。
+func serverVariables(collector meta.Collector) *meta.ServerVariables {
+	provider, ok := collector.(meta.VariablesProvider)
+	if !ok {
+		return nil
+	}
+	vars, err := provider.GetServerVariables()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to get server variables: %v\n", err)
+		return nil
+	}
+	return vars
 }
 
 // initCollector はメタデータコレクターとDB接続を返す。
 // 呼び出し元はdb.Close()を担当する。
 func initCollector() (meta.Collector, *sql.DB, error) {
+	if flagOffline || flagMetaFile != "" || flagSchemaFile != "" {
+		collector, err := initOfflineCollector()
+		return collector, nil, err
+	}
+	db, err := openDB()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	collector, err := meta.NewDBCollector(db, flagDatabase)
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, err
+	}
+	return collector, db, nil
+}
+
+// addConnectionFlags はMySQL接続用のフラグをコマンドに登録する。
+func addConnectionFlags(cmd *cobra.Command) {
+	f := cmd.Flags()
+	f.StringVar(&flagHost, "host", "localhost", "MySQL host")
+	f.IntVar(&flagPort, "port", 3306, "MySQL port")
+	f.StringVar(&flagUser, "user", "", "MySQL user")
+	f.StringVar(&flagPassword, "password", "", "MySQL password")
+	f.StringVar(&flagDatabase, "database", "", "Database name")
+}
+
+// openDB は接続フラグからMySQLに接続する。
+// 呼び出し元はdb.Close()を担当する。
+func openDB() (*sql.DB, error) {
 	if flagUser == "" || flagDatabase == "" {
-		return nil, nil, fmt.Errorf("--user and --database must be specified")
+		return nil, fmt.Errorf("--user and --database must be specified")
 	}
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", flagUser, flagPassword, flagHost, flagPort, flagDatabase)
 
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to connect to MySQL: %w", err)
+		return nil, fmt.Errorf("failed to connect to MySQL: %w", err)
 	}
 	if pingErr := db.Ping(); pingErr != nil {
 		_ = db.Close()
-		return nil, nil, fmt.Errorf("failed to ping MySQL: %w", pingErr)
+		return nil, fmt.Errorf("failed to ping MySQL: %w", pingErr)
 	}
+	return db, nil
+}
 
-	collector, err := meta.NewDBCollector(db, flagDatabase)
+// defaultOfflineMySQLVersion はオフライン時に --mysql-version 未指定かつ
+// メタデータファイルにも記録がない場合に想定するバージョン。
+const defaultOfflineMySQLVersion = "8.0"
+
+// initOfflineCollector は --meta-file / --schema-file のメタデータを返すコレクターを作成する。
+// 両方指定した場合、同名テーブルは統計情報を持つ --meta-file の内容を優先する。
+// どちらも未指定の場合はテーブル情報なしで判定のみ行う。
+func initOfflineCollector() (meta.Collector, error) {
+	mf := &meta.MetaFile{Version: meta.MetaFileVersion}
+	if flagMetaFile != "" {
+		loaded, err := meta.LoadMetaFile(flagMetaFile)
+		if err != nil {
+			return nil, err
+		}
+		mf = loaded
+	}
+	if flagSchemaFile != "" {
+		tables, err := loadSchemaFile(flagSchemaFile)
+		if err != nil {
+			return nil, err
+		}
+		mf.Tables = mergeTables(mf.Tables, tables)
+	}
+
+	version := flagMySQLVersion
+	if version != "" {
+		if _, err := predictor.ParseVersion(version); err != nil {
+			return nil, fmt.Errorf("invalid --mysql-version: %w", err)
+		}
+	}
+	if version == "" {
+		version = mf.MySQLVersion
+	}
+	if version == "" {
+		version = defaultOfflineMySQLVersion
+	}
+	return meta.NewFileCollectorFromMetaFile(mf, version), nil
+}
+
+// loadSchemaFile はスキーマダンプを読み込み、CREATE TABLE 文からテーブルメタデータを構築する。
+func loadSchemaFile(path string) ([]meta.TableMeta, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- ユーザー指定のスキーマダンプを読む
 	if err != nil {
-		_ = db.Close()
-		return nil, nil, err
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
 	}
-	return collector, db, nil
+	tables, err := parser.ParseCreateTables(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema file: %w", err)
+	}
+	return tables, nil
+}
+
+// mergeTables は primary に存在しないテーブルだけを secondary から追加する。
+func mergeTables(primary, secondary []meta.TableMeta) []meta.TableMeta {
+	seen := make(map[string]bool, len(primary))
+	for _, tm := range primary {
+		seen[strings.ToLower(tm.Schema+"."+tm.Table)] = true
+	}
+	merged := append([]meta.TableMeta(nil), primary...)
+	for _, tm := range secondary {
+		if !seen[strings.ToLower(tm.Schema+"."+tm.Table)] {
+			merged = append(merged, tm)
+		}
+	}
+	return merged
 }
 
 // collectorAdapter は meta.Collector を fkresolver.MetaProvider に適合させるアダプター。
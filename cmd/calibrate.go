@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/calibrate"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+var (
+	flagCalibrateName string
+	flagCalibrateCSV  string
+)
+
+var calibrateCmd = &cobra.Command{
+	Use:   "calibrate",
+	Short: "Fit duration estimate coefficients from recorded ALTER runs",
+	Long: "calibrate imports observed ALTER runs (table size, algorithm and wall-clock time) from a CSV file given by --csv, " +
+		"or from performance_schema.events_stages_history_long of the connected server, fits per-algorithm throughput " +
+		"coefficients and saves them as a profile that can be used with analyze --profile.",
+	RunE: runCalibrate,
+}
+
+func init() {
+	addConnectionFlags(calibrateCmd)
+	f := calibrateCmd.Flags()
+	f.StringVar(&flagCalibrateName, "name", "", "Profile name to save the fitted coefficients as (required)")
+	f.StringVar(&flagCalibrateCSV, "csv", "", "CSV file of observed ALTER runs (\"-\" for stdin); reads performance_schema when omitted")
+	_ = calibrateCmd.MarkFlagRequired("name")
+}
+
+func runCalibrate(cmd *cobra.Command, _ []string) error {
+	dir, err := calibrate.DefaultProfileDir()
+	if err != nil {
+		return err
+	}
+	if _, err := calibrate.ProfilePath(dir, flagCalibrateName); err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	var observations []calibrate.Observation
+	if flagCalibrateCSV != "" {
+		observations, err = readObservationsCSV(flagCalibrateCSV, cmd.InOrStdin())
+	} else {
+		observations, err = readObservationsPerformanceSchema()
+	}
+	if err != nil {
+		return err
+	}
+
+	result := calibrate.Fit(predictor.DefaultDurationSettings(), observations)
+	used := 0
+	for _, n := range result.Samples {
+		used += n
+	}
+	if used == 0 {
+		return fmt.Errorf("no usable observations (need COPY or INPLACE runs with data_length and duration_sec)")
+	}
+
+	profile := &calibrate.Profile{
+		Name:      flagCalibrateName,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Settings:  result.Settings,
+		Samples:   result.Samples,
+	}
+	path, err := calibrate.SaveProfile(dir, profile)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	s := result.Settings
+	fmt.Fprintf(out, "Fitted %d of %d observations\n", used, len(observations))
+	fmt.Fprintf(out, "  COPY            : %.1f MB/s (%d samples)\n", s.CopyBytesPerSec/float64(predictor.MB), result.Samples[calibrate.KindCopy])
+	fmt.Fprintf(out, "  INPLACE rebuild : %.1f MB/s (%d samples)\n", s.RebuildBytesPerSec/float64(predictor.MB), result.Samples[calibrate.KindRebuild])
+	fmt.Fprintf(out, "  Index build     : %.1f MB/s (%d samples)\n", s.IndexScanBytesPerSec/float64(predictor.MB), result.Samples[calibrate.KindIndexBuild])
+	fmt.Fprintf(out, "  Range factor    : x%.2f - x%.2f\n", s.MinFactor, s.MaxFactor)
+	fmt.Fprintf(out, "Saved profile %q to %s\n", profile.Name, path)
+	return nil
+}
+
+func readObservationsCSV(path string, stdin io.Reader) ([]calibrate.Observation, error) {
+	if path == stdinPath {
+		return calibrate.ReadCSV(stdin)
+	}
+	f, err := os.Open(path) // #nosec G304 -- ユーザー指定の計測結果CSVを読む
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return calibrate.ReadCSV(f)
+}
+
+func readObservationsPerformanceSchema() ([]calibrate.Observation, error) {
+	db, err := openDB()
+	if err != nil {
+		return nil, withExitCode(ExitMetadataError, err)
+	}
+	defer func() { _ = db.Close() }()
+
+	collector, err := meta.NewDBCollector(db, flagDatabase)
+	if err != nil {
+		return nil, withExitCode(ExitMetadataError, err)
+	}
+	observations, err := calibrate.LoadPerformanceSchema(db, collector)
+	if err != nil {
+		return nil, withExitCode(ExitMetadataError, err)
+	}
+	return observations, nil
+}
+
+// loadProfileSettings は --profile で指定されたプロファイルの係数を返す。
+func loadProfileSettings(name string) (predictor.DurationSettings, error) {
+	dir, err := calibrate.DefaultProfileDir()
+	if err != nil {
+		return predictor.DurationSettings{}, err
+	}
+	p, err := calibrate.LoadProfile(dir, name)
+	if err != nil {
+		return predictor.DurationSettings{}, err
+	}
+	return p.Settings, nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// プロセスの終了コード。CIでの判定に使えるよう原因ごとに分けている。
+const (
+	// ExitOK は正常終了。
+	ExitOK = 0
+	// ExitGeneralError は引数の誤りなど、以下に分類されないエラー。
+	ExitGeneralError = 1
+	// ExitParseError はSQLのパースエラー。
+	ExitParseError = 2
+	// ExitMetadataError はMySQLへの接続やメタデータファイルの読み込みの失敗。
+	ExitMetadataError = 3
+	// ExitRiskThreshold は --fail-on で指定したリスクレベル以上の操作が見つかった場合。
+	ExitRiskThreshold = 4
+	// ExitPolicyViolation はポリシー違反が見つかった場合。
+	ExitPolicyViolation = 5
+	// ExitStatementRejected は MySQL が実行を拒否する文（存在しないカラムの参照など）が見つかった場合。
+	ExitStatementRejected = 6
+)
+
+// ExitError は終了コードを伴うエラー。
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// withExitCode は err を終了コード付きのエラーで包む。err が nil の場合は nil を返す。
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: code, Err: err}
+}
+
+// ExitCode は Execute が返したエラーに対応する終了コードを返す。
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitGeneralError
+}
+
+// parseRiskLevel は --fail-on の値をリスクレベルに変換する。
+func parseRiskLevel(s string) (meta.RiskLevel, error) {
+	level := meta.RiskLevel(strings.ToUpper(s))
+	switch level {
+	case meta.RiskLow, meta.RiskMedium, meta.RiskHigh, meta.RiskCritical:
+		return level, nil
+	default:
+		return "", fmt.Errorf("invalid risk level %q (must be LOW, MEDIUM, HIGH or CRITICAL)", s)
+	}
+}
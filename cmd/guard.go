@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/parser"
+)
+
+// writeGuardedSQL は各入力の ALTER TABLE 文に予測した ALGORITHM/LOCK 句を付けたSQLを書き出す。
+// path が "-" の場合は標準出力に書く。入力が複数ある場合は入力ごとに読み込み元のコメントを付けて順に連結する。
+// ALTER 文のない入力もそのまま出力する。
+func writeGuardedSQL(path string, sources []sqlSource, guards map[int][]parser.GuardClause) error {
+	var w io.Writer = os.Stdout
+	if path != stdinPath {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create guarded SQL file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	for i, src := range sources {
+		guarded, err := parser.Guard(src.SQL, guards[i])
+		if err != nil {
+			return fmt.Errorf("failed to rewrite %s: %w", sourceLabel(src), err)
+		}
+		if len(sources) > 1 {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "-- %s\n", sourceLabel(src))
+		}
+		// 元のスクリプトが改行で終わらない場合も、次の入力や出力と連結されないよう改行を補う
+		if !strings.HasSuffix(guarded, "\n") {
+			guarded += "\n"
+		}
+		if _, err := io.WriteString(w, guarded); err != nil {
+			return fmt.Errorf("failed to write guarded SQL: %w", err)
+		}
+	}
+	return nil
+}
+
+// sourceLabel は入力の表示名を返す。--sql で直接指定された場合は "--sql"。
+func sourceLabel(src sqlSource) string {
+	if src.Path == "" {
+		return "--sql"
+	}
+	return src.Path
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// stdinPath は標準入力から読み込む場合のファイル指定。
+	stdinPath = "-"
+	// stdinLabel はレポートに表示する標準入力の名前。
+	stdinLabel = "<stdin>"
+)
+
+// sqlSource は解析対象SQLとその読み込み元を保持する。
+type sqlSource struct {
+	// Path は読み込み元ファイルのパス。--sql で直接指定された場合は空。
+	Path string
+	SQL  string
+}
+
+// collectSQLSources は --sql / --file / 位置引数から解析対象SQLを集める。
+// ディレクトリを指定した場合は直下の *.sql ファイルを名前順に読み込む。
+func collectSQLSources(sqlText string, paths []string, stdin io.Reader) ([]sqlSource, error) {
+	var sources []sqlSource
+	if sqlText != "" {
+		sources = append(sources, sqlSource{SQL: sqlText})
+	}
+
+	readStdin := false
+	for _, path := range paths {
+		if path == stdinPath {
+			if readStdin {
+				continue
+			}
+			readStdin = true
+			data, err := io.ReadAll(stdin)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read SQL from stdin: %w", err)
+			}
+			sources = append(sources, sqlSource{Path: stdinLabel, SQL: string(data)})
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SQL file: %w", err)
+		}
+		if !info.IsDir() {
+			src, err := readSQLFile(path)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, src)
+			continue
+		}
+
+		files, err := listSQLFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			src, err := readSQLFile(file)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, src)
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("--sql, --file or a file argument must be specified")
+	}
+	return sources, nil
+}
+
+// listSQLFiles はディレクトリ直下の *.sql ファイルを名前の辞書順で返す。
+func listSQLFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SQL directory: %w", err)
+	}
+	// os.ReadDir はファイル名でソート済みのエントリを返す
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".sql") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, e.Name()))
+	}
+	return files, nil
+}
+
+func readSQLFile(path string) (sqlSource, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- ユーザー指定のマイグレーションファイルを読む
+	if err != nil {
+		return sqlSource{}, fmt.Errorf("failed to read SQL file: %w", err)
+	}
+	return sqlSource{Path: path, SQL: string(data)}, nil
+}
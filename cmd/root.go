@@ -17,5 +17,8 @@ func Execute() error {
 
 func init() {
 	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(calibrateCmd)
+	rootCmd.AddCommand(rulesCmd)
+	rootCmd.AddCommand(snapshotCmd)
 	rootCmd.AddCommand(versionCmd)
 }
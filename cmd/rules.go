@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+var (
+	flagRulesFormat string
+	flagRulesAction string
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "List the prediction rule table",
+	Long: "rules lists every prediction rule in evaluation order with its stable ID, condition, predicted ALGORITHM/LOCK, " +
+		"applicable MySQL versions and the MySQL reference manual section it is based on. " +
+		"Custom rules given by --rules-file are listed first.",
+	RunE: runRules,
+}
+
+func init() {
+	f := rulesCmd.Flags()
+	f.StringVar(&flagRulesFormat, "format", "text", "Output format: text|json")
+	f.StringVar(&flagRulesAction, "action", "", "Only list rules for this operation (e.g. MODIFY_COLUMN)")
+	f.StringVar(&flagRulesFile, "rules-file", "", "YAML/JSON file with custom prediction rules to list before the built-in rules")
+}
+
+func runRules(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+	var customRules *predictor.CustomRuleFile
+	if flagRulesFile != "" {
+		loaded, err := predictor.LoadCustomRulesFile(flagRulesFile)
+		if err != nil {
+			return err
+		}
+		customRules = loaded
+	}
+
+	action := meta.AlterActionType(strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(flagRulesAction)), " ", "_"))
+	var infos []predictor.RuleInfo
+	for _, info := range predictor.New(predictor.WithCustomRules(customRules)).Rules() {
+		if action == "" || info.ActionType == action || info.ActionType == predictor.AnyAction {
+			infos = append(infos, info)
+		}
+	}
+
+	if flagRulesFormat == "json" {
+		data, err := json.MarshalIndent(infos, "", "  ")
+		if err != nil {
+			return fmt.Errorf("render error: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+	return writeRuleTable(cmd.OutOrStdout(), infos)
+}
+
+// writeRuleTable はルールテーブルを列を揃えたテキストで出力する。
+func writeRuleTable(w io.Writer, infos []predictor.RuleInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tACTION\tWHEN\tALGORITHM\tLOCK\tREBUILD\tVERSION\tREFERENCE")
+	for _, info := range infos {
+		ref := info.Source
+		if i := strings.Index(info.DocURL, "#"); ref == "" && i >= 0 {
+			ref = info.DocURL[i:]
+		}
+		rebuild := "No"
+		if info.TableRebuild {
+			rebuild = "Yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			info.ID, info.ActionType, info.When, info.Algorithm, info.Lock,
+			rebuild, predictor.VersionRange(info.MinVersion, info.MaxVersion), ref)
+	}
+	return tw.Flush()
+}
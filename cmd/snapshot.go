@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+var (
+	flagSnapshotSchemas []string
+	flagSnapshotOutput  string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Export table metadata of whole schemas for offline analysis",
+	Long: "snapshot collects metadata (columns, indexes, foreign keys, partitions) of every table in the target schemas " +
+		"and writes it as a metadata file that can be passed to analyze --meta-file.",
+	RunE: runSnapshot,
+}
+
+func init() {
+	addConnectionFlags(snapshotCmd)
+	f := snapshotCmd.Flags()
+	f.StringSliceVar(&flagSnapshotSchemas, "schemas", nil, "Schemas to export (comma separated, default: --database)")
+	f.StringVarP(&flagSnapshotOutput, "output", "o", "", "Output file path (default: stdout)")
+}
+
+func runSnapshot(_ *cobra.Command, _ []string) error {
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	collector, err := meta.NewDBCollector(db, flagDatabase)
+	if err != nil {
+		return err
+	}
+
+	schemas := make([]string, 0, len(flagSnapshotSchemas))
+	for _, s := range flagSnapshotSchemas {
+		if s = strings.TrimSpace(s); s != "" {
+			schemas = append(schemas, s)
+		}
+	}
+
+	mf, err := collector.Snapshot(schemas)
+	if err != nil {
+		return fmt.Errorf("snapshot error: %w", err)
+	}
+	printCollectorWarnings(collector)
+
+	if err := writeSnapshot(flagSnapshotOutput, mf); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d tables\n", len(mf.Tables))
+	return nil
+}
+
+// writeSnapshot はメタデータファイルを path（空の場合は標準出力）に書き出す。
+// ファイルのクローズに失敗した場合も、書き込みが完了していない可能性があるためエラーとする。
+func writeSnapshot(path string, mf *meta.MetaFile) (err error) {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, createErr := os.Create(path)
+		if createErr != nil {
+			return fmt.Errorf("failed to create output file: %w", createErr)
+		}
+		defer func() {
+			if cerr := f.Close(); cerr != nil && err == nil {
+				err = fmt.Errorf("failed to close output file: %w", cerr)
+			}
+		}()
+		w = f
+	}
+	if err = meta.WriteMetaFile(w, mf); err != nil {
+		return fmt.Errorf("failed to write metadata file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package calibrate
+
+import (
+	"math"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+// minSamplesForRange はレンジの係数を観測結果から求めるのに必要な最小の観測数。
+// これより少ない場合は外れ値の影響が大きいため base の係数を維持する。
+const minSamplesForRange = 5
+
+// Kind は推定式の種類（どのスループット係数に対応するか）を表す。
+type Kind string
+
+const (
+	// KindCopy は ALGORITHM=COPY によるテーブルコピー。
+	KindCopy Kind = "COPY"
+	// KindRebuild は INPLACE によるテーブル再構築。
+	KindRebuild Kind = "REBUILD"
+	// KindIndexBuild は再構築を伴わない INPLACE のインデックス作成。
+	KindIndexBuild Kind = "INDEX_BUILD"
+)
+
+// Observation は実際に実行されたALTER文の計測結果を表す。
+type Observation struct {
+	Table            string         `json:"table,omitempty"`
+	Algorithm        meta.Algorithm `json:"algorithm"`
+	TableRebuild     bool           `json:"table_rebuild"`
+	DataLength       int64          `json:"data_length"`
+	RowCount         int64          `json:"row_count"`
+	SecondaryIndexes int            `json:"secondary_indexes"`
+	DurationSec      float64        `json:"duration_sec"`
+}
+
+// Kind は観測結果がどの推定式に対応するかを返す。INSTANT は対象外で空文字を返す。
+func (o Observation) Kind() Kind {
+	switch {
+	case o.Algorithm == meta.AlgorithmCopy:
+		return KindCopy
+	case o.Algorithm == meta.AlgorithmInplace && o.TableRebuild:
+		return KindRebuild
+	case o.Algorithm == meta.AlgorithmInplace:
+		return KindIndexBuild
+	default:
+		return ""
+	}
+}
+
+// Result はフィッティング結果を表す。
+type Result struct {
+	Settings predictor.DurationSettings `json:"settings"`
+	// Samples は種類ごとのフィッティングに使った観測数
+	Samples map[Kind]int `json:"samples"`
+}
+
+// Fit は観測結果から種類ごとのスループット係数を求める。
+// 推定式 duration = DataLength / throughput + インデックス構築時間 のうち、
+// インデックス構築速度（IndexRowsPerSec）は base の値で固定し、throughput を最小二乗法で求める。
+// 観測がない種類の係数と、データ量0や実行時間0の観測は base の値を維持・無視する。
+// レンジの係数（MinFactor/MaxFactor）は、観測が minSamplesForRange 件以上ある場合に
+// フィット後の推定値に対する実測値の比の最小・最大から求める。
+func Fit(base predictor.DurationSettings, observations []Observation) Result {
+	settings := base
+	if settings.IndexRowsPerSec <= 0 {
+		settings.IndexRowsPerSec = predictor.DefaultDurationSettings().IndexRowsPerSec
+	}
+	result := Result{Samples: make(map[Kind]int)}
+
+	// duration ≒ data × k（k = 1/throughput）の原点を通る回帰
+	type sums struct{ xy, xx float64 }
+	acc := make(map[Kind]*sums)
+	var used []Observation
+	for _, o := range observations {
+		kind := o.Kind()
+		if kind == "" || o.DataLength <= 0 || o.DurationSec <= 0 {
+			continue
+		}
+		x := float64(o.DataLength)
+		y := o.DurationSec - indexSeconds(settings, o)
+		if y <= 0 {
+			continue
+		}
+		s := acc[kind]
+		if s == nil {
+			s = &sums{}
+			acc[kind] = s
+		}
+		s.xy += x * y
+		s.xx += x * x
+		result.Samples[kind]++
+		used = append(used, o)
+	}
+	for kind, s := range acc {
+		if s.xy <= 0 {
+			continue
+		}
+		throughput := s.xx / s.xy
+		switch kind {
+		case KindCopy:
+			settings.CopyBytesPerSec = throughput
+		case KindRebuild:
+			settings.RebuildBytesPerSec = throughput
+		case KindIndexBuild:
+			settings.IndexScanBytesPerSec = throughput
+		}
+	}
+
+	if len(used) >= minSamplesForRange {
+		minRatio, maxRatio := math.Inf(1), math.Inf(-1)
+		for _, o := range used {
+			est := estimateSeconds(settings, o)
+			if est <= 0 {
+				continue
+			}
+			ratio := o.DurationSec / est
+			minRatio = math.Min(minRatio, ratio)
+			maxRatio = math.Max(maxRatio, ratio)
+		}
+		// レンジは常に推定値を含むようにする
+		if !math.IsInf(minRatio, 0) {
+			settings.MinFactor = math.Min(minRatio, 1)
+			settings.MaxFactor = math.Max(maxRatio, 1)
+		}
+	}
+
+	result.Settings = settings
+	return result
+}
+
+// indexSeconds はセカンダリインデックスの構築にかかる時間を返す。
+func indexSeconds(s predictor.DurationSettings, o Observation) float64 {
+	rows := float64(o.RowCount)
+	if o.Kind() == KindIndexBuild {
+		return rows / s.IndexRowsPerSec
+	}
+	return rows * float64(o.SecondaryIndexes) / s.IndexRowsPerSec
+}
+
+// estimateSeconds は設定に基づく観測条件での推定時間を返す。
+func estimateSeconds(s predictor.DurationSettings, o Observation) float64 {
+	data := float64(o.DataLength)
+	var throughput float64
+	switch o.Kind() {
+	case KindCopy:
+		throughput = s.CopyBytesPerSec
+	case KindRebuild:
+		throughput = s.RebuildBytesPerSec
+	case KindIndexBuild:
+		throughput = s.IndexScanBytesPerSec
+	}
+	if throughput <= 0 {
+		return 0
+	}
+	return data/throughput + indexSeconds(s, o)
+}
@@ -0,0 +1,155 @@
+package calibrate
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+const mb = float64(predictor.MB)
+
+func TestFit(t *testing.T) {
+	// COPY は 20MB/s、INPLACE 再構築は 80MB/s で実行された観測から係数が求まることを検証
+	base := predictor.DefaultDurationSettings()
+	base.IndexRowsPerSec = 1_000_000
+	observations := []Observation{
+		// 1000MB / 20MB/s + 1,000,000行 × 1 / 1,000,000 = 51s
+		{Algorithm: meta.AlgorithmCopy, TableRebuild: true, DataLength: 1000 * predictor.MB, RowCount: 1_000_000, SecondaryIndexes: 1, DurationSec: 51},
+		{Algorithm: meta.AlgorithmCopy, TableRebuild: true, DataLength: 200 * predictor.MB, DurationSec: 10},
+		{Algorithm: meta.AlgorithmInplace, TableRebuild: true, DataLength: 800 * predictor.MB, DurationSec: 10},
+		// INSTANT とデータ量0は無視される
+		{Algorithm: meta.AlgorithmInstant, DataLength: 100 * predictor.MB, DurationSec: 1},
+		{Algorithm: meta.AlgorithmCopy, DurationSec: 5},
+	}
+	result := Fit(base, observations)
+
+	if got := result.Settings.CopyBytesPerSec / mb; math.Abs(got-20) > 0.01 {
+		t.Errorf("COPYのスループットが20MB/sであること: got %.2f", got)
+	}
+	if got := result.Settings.RebuildBytesPerSec / mb; math.Abs(got-80) > 0.01 {
+		t.Errorf("INPLACE再構築のスループットが80MB/sであること: got %.2f", got)
+	}
+	if result.Settings.IndexScanBytesPerSec != base.IndexScanBytesPerSec {
+		t.Errorf("観測のない係数は維持されること: got %v", result.Settings.IndexScanBytesPerSec)
+	}
+	if result.Samples[KindCopy] != 2 || result.Samples[KindRebuild] != 1 || result.Samples[KindIndexBuild] != 0 {
+		t.Errorf("種類ごとの観測数が集計されること: got %v", result.Samples)
+	}
+	if result.Settings.MinFactor != base.MinFactor || result.Settings.MaxFactor != base.MaxFactor {
+		t.Errorf("観測が少ない場合はレンジの係数を維持すること: got %v - %v", result.Settings.MinFactor, result.Settings.MaxFactor)
+	}
+}
+
+func TestFitRangeFactors(t *testing.T) {
+	// 観測が十分ある場合はばらつきからレンジの係数が求まることを検証
+	var observations []Observation
+	for _, sec := range []float64{8, 9, 10, 11, 12} {
+		observations = append(observations, Observation{Algorithm: meta.AlgorithmInplace, TableRebuild: true, DataLength: 500 * predictor.MB, DurationSec: sec})
+	}
+	s := Fit(predictor.DefaultDurationSettings(), observations).Settings
+	if math.Abs(s.RebuildBytesPerSec/mb-50) > 1 {
+		t.Errorf("平均的なスループットが求まること: got %.2f", s.RebuildBytesPerSec/mb)
+	}
+	if s.MinFactor >= 1 || s.MaxFactor <= 1 || s.MinFactor < 0.75 || s.MaxFactor > 1.25 {
+		t.Errorf("レンジの係数が実測のばらつきに合うこと: got %v - %v", s.MinFactor, s.MaxFactor)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	input := `table,algorithm,table_rebuild,data_length,row_count,secondary_indexes,duration_sec
+mydb.users,copy,,1048576000,2000000,2,60.5
+mydb.items,INPLACE,false,209715200,500000,,3
+`
+	observations, err := ReadCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(observations) != 2 {
+		t.Fatalf("2件読み込まれること: got %d", len(observations))
+	}
+	o := observations[0]
+	if o.Table != "mydb.users" || o.Algorithm != meta.AlgorithmCopy || !o.TableRebuild || o.RowCount != 2_000_000 || o.SecondaryIndexes != 2 || o.DurationSec != 60.5 {
+		t.Errorf("1行目の値が読み込まれること: got %+v", o)
+	}
+	if observations[1].TableRebuild || observations[1].Kind() != KindIndexBuild {
+		t.Errorf("table_rebuild=falseのINPLACEはインデックス作成として扱うこと: got %+v", observations[1])
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"空", "", "empty CSV"},
+		{"必須列なし", "algorithm,data_length\nCOPY,1\n", `"duration_sec"`},
+		{"不正なアルゴリズム", "algorithm,data_length,duration_sec\nFAST,1,1\n", "line 2: invalid algorithm"},
+		{"不正な数値", "algorithm,data_length,duration_sec\nCOPY,1GB,1\n", "invalid data_length"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("エラーに%qが含まれること: got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestObservationFromStages(t *testing.T) {
+	tests := []struct {
+		name        string
+		stages      string
+		wantOK      bool
+		wantAlg     meta.Algorithm
+		wantRebuild bool
+	}{
+		{"COPY", "stage/sql/copy to tmp table\nstage/sql/rename result table", true, meta.AlgorithmCopy, true},
+		{"INPLACE再構築", "stage/innodb/alter table (read PK and internal sort)\nstage/innodb/alter table (log apply table)", true, meta.AlgorithmInplace, true},
+		{"インデックス作成", "stage/innodb/alter table (read PK and internal sort)\nstage/innodb/alter table (log apply index)", true, meta.AlgorithmInplace, false},
+		{"INSTANT", "stage/sql/altering table", false, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, ok := observationFromStages(tt.stages)
+			if ok != tt.wantOK {
+				t.Fatalf("判定可否が%vであること: got %v", tt.wantOK, ok)
+			}
+			if o.Algorithm != tt.wantAlg || o.TableRebuild != tt.wantRebuild {
+				t.Errorf("%s (rebuild=%v) であること: got %+v", tt.wantAlg, tt.wantRebuild, o)
+			}
+		})
+	}
+}
+
+func TestProfileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	settings := predictor.DefaultDurationSettings()
+	settings.CopyBytesPerSec = 12 * mb
+	path, err := SaveProfile(dir, &Profile{Name: "prod-db", Settings: settings, Samples: map[Kind]int{KindCopy: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, "prod-db.json") {
+		t.Errorf("プロファイル名のファイルに保存されること: got %s", path)
+	}
+
+	p, err := LoadProfile(dir, "prod-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Settings.CopyBytesPerSec != 12*mb || p.Samples[KindCopy] != 3 {
+		t.Errorf("保存した係数が読み込まれること: got %+v", p)
+	}
+
+	if _, err := LoadProfile(dir, "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("存在しないプロファイルはエラーになること: got %v", err)
+	}
+	if _, err := ProfilePath(dir, "../etc"); err == nil {
+		t.Error("パスを含むプロファイル名はエラーになること")
+	}
+}
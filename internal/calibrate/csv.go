@@ -0,0 +1,121 @@
+package calibrate
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// CSVの列名。algorithm, data_length, duration_sec は必須。
+const (
+	colTable            = "table"
+	colAlgorithm        = "algorithm"
+	colTableRebuild     = "table_rebuild"
+	colDataLength       = "data_length"
+	colRowCount         = "row_count"
+	colSecondaryIndexes = "secondary_indexes"
+	colDurationSec      = "duration_sec"
+)
+
+// ReadCSV はヘッダー付きCSVから観測結果を読み込む。
+// table_rebuild を省略した場合、INPLACE は再構築ありとして扱う。
+func ReadCSV(r io.Reader) ([]Observation, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("empty CSV")
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, required := range []string{colAlgorithm, colDataLength, colDurationSec} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("CSV header must contain %q column", required)
+		}
+	}
+
+	var observations []Observation
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		o, err := parseRecord(cols, record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		observations = append(observations, o)
+	}
+	return observations, nil
+}
+
+func parseRecord(cols map[string]int, record []string) (Observation, error) {
+	field := func(name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	o := Observation{
+		Table:     field(colTable),
+		Algorithm: meta.Algorithm(strings.ToUpper(field(colAlgorithm))),
+	}
+	switch o.Algorithm {
+	case meta.AlgorithmInstant, meta.AlgorithmInplace, meta.AlgorithmCopy:
+	default:
+		return o, fmt.Errorf("invalid algorithm %q", field(colAlgorithm))
+	}
+
+	o.TableRebuild = o.Algorithm != meta.AlgorithmInstant
+	if v := field(colTableRebuild); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return o, fmt.Errorf("invalid %s %q: %w", colTableRebuild, v, err)
+		}
+		o.TableRebuild = b
+	}
+
+	var err error
+	if o.DataLength, err = parseInt(field(colDataLength), colDataLength); err != nil {
+		return o, err
+	}
+	if o.RowCount, err = parseInt(field(colRowCount), colRowCount); err != nil {
+		return o, err
+	}
+	idx, err := parseInt(field(colSecondaryIndexes), colSecondaryIndexes)
+	if err != nil {
+		return o, err
+	}
+	o.SecondaryIndexes = int(idx)
+	if o.DurationSec, err = strconv.ParseFloat(field(colDurationSec), 64); err != nil {
+		return o, fmt.Errorf("invalid %s %q: %w", colDurationSec, field(colDurationSec), err)
+	}
+	return o, nil
+}
+
+// parseInt は空文字を0として整数を解析する。
+func parseInt(s, name string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, s, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,109 @@
+package calibrate
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/parser"
+)
+
+// picosecondsPerSecond は performance_schema のタイマー単位（ピコ秒）の換算値。
+const picosecondsPerSecond = 1e12
+
+// performance_schema のステージ名。
+const (
+	stageCopyToTmpTable = "stage/sql/copy to tmp table"
+	stageInnoDBAlter    = "stage/innodb/alter table"
+	stageLogApplyTable  = "stage/innodb/alter table (log apply table)"
+)
+
+// alterStagesQuery は実行済みのALTER文と、その実行中に記録されたステージを取得する。
+// events_statements_history_long / events_stages_history_long のコンシューマーと
+// stage/innodb/alter% および stage/sql/copy to tmp table のインストゥルメントが有効である必要がある。
+const alterStagesQuery = `SELECT s.SQL_TEXT, COALESCE(s.CURRENT_SCHEMA, ''), s.TIMER_WAIT,
+		GROUP_CONCAT(DISTINCT st.EVENT_NAME SEPARATOR '\n')
+	FROM performance_schema.events_statements_history_long s
+	JOIN performance_schema.events_stages_history_long st
+		ON st.THREAD_ID = s.THREAD_ID AND st.NESTING_EVENT_ID = s.EVENT_ID
+	WHERE s.SQL_TEXT LIKE 'ALTER TABLE%' AND s.ERRORS = 0
+	GROUP BY s.THREAD_ID, s.EVENT_ID, s.SQL_TEXT, s.CURRENT_SCHEMA, s.TIMER_WAIT`
+
+// LoadPerformanceSchema は performance_schema の履歴から実行済みALTER文の観測結果を作成する。
+// アルゴリズムと再構築の有無は記録されたステージから判定し、テーブルサイズは collector から取得した現在値を使う。
+// INSTANT で実行された文や、テーブル情報を取得できない文は含めない。
+func LoadPerformanceSchema(db *sql.DB, collector meta.Collector) ([]Observation, error) {
+	rows, err := db.Query(alterStagesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query performance_schema: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var observations []Observation
+	for rows.Next() {
+		var sqlText, schema, stages string
+		var timerWait int64
+		if err := rows.Scan(&sqlText, &schema, &timerWait, &stages); err != nil {
+			return nil, fmt.Errorf("failed to scan performance_schema row: %w", err)
+		}
+		o, ok := observationFromStages(stages)
+		if !ok {
+			continue
+		}
+		o.DurationSec = float64(timerWait) / picosecondsPerSecond
+
+		ops, err := parser.Parse(sqlText)
+		if err != nil || len(ops) != 1 {
+			// 切り詰められたSQLなどは対象外
+			continue
+		}
+		op := ops[0]
+		if op.Schema != "" {
+			schema = op.Schema
+		}
+		tm, err := collector.GetTableMeta(schema, op.Table)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping ALTER on %s.%s: %v\n", schema, op.Table, err)
+			continue
+		}
+		o.Table = schema + "." + op.Table
+		o.DataLength = tm.DataLength
+		o.RowCount = tm.RowCount
+		for _, idx := range tm.Indexes {
+			if !idx.IsPrimary {
+				o.SecondaryIndexes++
+			}
+		}
+		observations = append(observations, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read performance_schema rows: %w", err)
+	}
+	return observations, nil
+}
+
+// observationFromStages は記録されたステージ名からアルゴリズムと再構築の有無を判定する。
+// COPY/INPLACE のどちらのステージもない場合（INSTANT 等）は false を返す。
+func observationFromStages(stages string) (Observation, bool) {
+	var copyStage, inplaceStage, rebuildStage bool
+	for _, name := range strings.Split(stages, "\n") {
+		switch {
+		case name == stageCopyToTmpTable:
+			copyStage = true
+		case name == stageLogApplyTable:
+			inplaceStage, rebuildStage = true, true
+		case strings.HasPrefix(name, stageInnoDBAlter):
+			inplaceStage = true
+		}
+	}
+	switch {
+	case copyStage:
+		return Observation{Algorithm: meta.AlgorithmCopy, TableRebuild: true}, true
+	case inplaceStage:
+		return Observation{Algorithm: meta.AlgorithmInplace, TableRebuild: rebuildStage}, true
+	default:
+		return Observation{}, false
+	}
+}
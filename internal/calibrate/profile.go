@@ -0,0 +1,80 @@
+package calibrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+// Profile はキャリブレーション結果として保存される係数のセット。
+type Profile struct {
+	Name      string                     `json:"name"`
+	CreatedAt time.Time                  `json:"created_at"`
+	Settings  predictor.DurationSettings `json:"settings"`
+	Samples   map[Kind]int               `json:"samples"`
+}
+
+// profileNameRegex はプロファイル名として使える文字列（ファイル名として安全なもの）に一致する。
+var profileNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// DefaultProfileDir はプロファイルの保存先ディレクトリを返す。
+// OSのユーザー設定ディレクトリ配下（Linux では ~/.config/ddl-lock-analyzer/profiles）を使う。
+func DefaultProfileDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "ddl-lock-analyzer", "profiles"), nil
+}
+
+// ProfilePath は dir 配下のプロファイルファイルのパスを返す。
+func ProfilePath(dir, name string) (string, error) {
+	if !profileNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid profile name %q (use letters, digits, '.', '_' or '-')", name)
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// SaveProfile はプロファイルを dir に保存し、保存先のパスを返す。
+func SaveProfile(dir string, p *Profile) (string, error) {
+	path, err := ProfilePath(dir, p.Name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode profile: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write profile: %w", err)
+	}
+	return path, nil
+}
+
+// LoadProfile は dir から name のプロファイルを読み込む。
+func LoadProfile(dir, name string) (*Profile, error) {
+	path, err := ProfilePath(dir, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- プロファイル名は profileNameRegex で検証済み
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("profile %q not found (run calibrate --name %s first)", name, name)
+		}
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %s: %w", path, err)
+	}
+	return &p, nil
+}
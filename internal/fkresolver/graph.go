@@ -24,6 +24,9 @@ type FKRelation struct {
 	Direction  FKDirection         `json:"direction"`
 	Depth      int                 `json:"depth"`
 	LockImpact FKLockImpact        `json:"lock_impact"`
+	// RowCount と DataLength は関連テーブルの推定行数とデータサイズ（メタデータがない場合は 0）
+	RowCount   int64 `json:"row_count,omitempty"`
+	DataLength int64 `json:"data_length,omitempty"`
 }
 
 // FKGraph はALTER対象テーブルの外部キー依存関係グラフを表す。
@@ -94,24 +94,29 @@ func (r *Resolver) resolveDirection(graph *FKGraph, fk meta.ForeignKeyMeta, acti
 	visited[key] = true
 
 	impact := DetermineLockImpact(cfg.direction, actions, fk)
-	cfg.appendTo(graph, FKRelation{
+	rel := FKRelation{
 		Table:      key,
 		Constraint: fk,
 		Direction:  cfg.direction,
 		Depth:      depth,
 		LockImpact: impact,
-	})
+	}
 
-	if r.provider == nil {
+	var nextMeta *meta.TableMeta
+	if r.provider != nil {
+		parts := splitQualifiedName(key)
+		if tm, err := r.provider.GetTableMeta(parts[0], parts[1]); err == nil && tm != nil {
+			nextMeta = tm
+			rel.RowCount = tm.RowCount
+			rel.DataLength = tm.DataLength
+		}
+	}
+	cfg.appendTo(graph, rel)
+	if nextMeta == nil {
 		return
 	}
 
 	// 再帰: 関連テーブルの次のFK関係を検索
-	parts := splitQualifiedName(key)
-	nextMeta, err := r.provider.GetTableMeta(parts[0], parts[1])
-	if err != nil {
-		return
-	}
 	for _, nextFK := range cfg.nextFKs(nextMeta) {
 		r.resolveDirection(graph, nextFK, actions, depth+1, visited, cfg)
 	}
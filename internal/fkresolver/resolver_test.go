@@ -96,7 +96,7 @@ func TestResolveChildFK(t *testing.T) {
 					},
 				},
 			},
-			"mydb.orders": {Schema: "mydb", Table: "orders", Engine: "InnoDB"},
+			"mydb.orders": {Schema: "mydb", Table: "orders", Engine: "InnoDB", RowCount: 5000, DataLength: 1 << 20},
 		},
 	}
 	resolver := NewResolver(provider, 5, true)
@@ -111,6 +111,9 @@ func TestResolveChildFK(t *testing.T) {
 	if graph.Children[0].Table != "mydb.orders" {
 		t.Errorf("子テーブルがmydb.ordersであること: got %s", graph.Children[0].Table)
 	}
+	if graph.Children[0].RowCount != 5000 || graph.Children[0].DataLength != 1<<20 {
+		t.Errorf("子テーブルの行数とサイズが設定されること: got %d rows, %d bytes", graph.Children[0].RowCount, graph.Children[0].DataLength)
+	}
 }
 
 func TestResolveCircularReference(t *testing.T) {
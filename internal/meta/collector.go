@@ -12,11 +12,18 @@ type Collector interface {
 	GetMySQLVersion() string
 }
 
+// WarningsProvider は取得を中断しない問題（権限不足など）を警告として返せるコレクター。
+type WarningsProvider interface {
+	// TakeWarnings は前回の呼び出し以降に発生した警告を返す。
+	TakeWarnings() []string
+}
+
 // DBCollector はMySQL接続からメタデータを取得する。
 type DBCollector struct {
 	db           *sql.DB
 	database     string
 	mysqlVersion string
+	warnings     []string
 }
 
 // NewDBCollector は新しい DBCollector を作成する。
@@ -35,6 +42,17 @@ func (c *DBCollector) GetMySQLVersion() string {
 	return c.mysqlVersion
 }
 
+// TakeWarnings は前回の呼び出し以降に発生した警告を返す。
+func (c *DBCollector) TakeWarnings() []string {
+	w := c.warnings
+	c.warnings = nil
+	return w
+}
+
+func (c *DBCollector) warnf(format string, args ...any) {
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
 // GetTableMeta は指定テーブルのメタデータを取得する。
 func (c *DBCollector) GetTableMeta(schema, table string) (*TableMeta, error) {
 	if schema == "" {
@@ -65,6 +83,12 @@ func (c *DBCollector) GetTableMeta(schema, table string) (*TableMeta, error) {
 	if err := c.fetchPartitionInfo(tm); err != nil {
 		return nil, err
 	}
+	if err := c.fetchRowVersions(tm); err != nil {
+		return nil, err
+	}
+	if err := c.fetchTriggers(tm); err != nil {
+		return nil, err
+	}
 
 	return tm, nil
 }
@@ -109,6 +133,7 @@ func (c *DBCollector) fetchColumns(tm *TableMeta) error {
 		}
 		col.IsNullable = strings.EqualFold(isNullable, "YES")
 		col.DefaultValue = defaultVal.String
+		col.HasDefault = defaultVal.Valid
 		col.CharacterSet = charset.String
 		col.Collation = collation.String
 		tm.Columns = append(tm.Columns, col)
@@ -130,7 +155,8 @@ func (c *DBCollector) fetchIndexes(tm *TableMeta) error {
 	indexMap := make(map[string]*IndexMeta)
 	var indexOrder []string
 	for rows.Next() {
-		var indexName, colName, indexType string
+		var indexName, indexType string
+		var colName sql.NullString
 		var nonUnique int
 		if err := rows.Scan(&indexName, &colName, &nonUnique, &indexType); err != nil {
 			return fmt.Errorf("failed to scan index: %w", err)
@@ -146,7 +172,12 @@ func (c *DBCollector) fetchIndexes(tm *TableMeta) error {
 			indexMap[indexName] = idx
 			indexOrder = append(indexOrder, indexName)
 		}
-		idx.Columns = append(idx.Columns, colName)
+		// 関数インデックスの式のキー部は COLUMN_NAME が NULL になる
+		if colName.Valid {
+			idx.Columns = append(idx.Columns, colName.String)
+		} else {
+			idx.HasExpression = true
+		}
 	}
 	if err := rows.Err(); err != nil {
 		return err
@@ -259,3 +290,24 @@ func (c *DBCollector) fetchPartitionInfo(tm *TableMeta) error {
 	}
 	return nil
 }
+
+func (c *DBCollector) fetchTriggers(tm *TableMeta) error {
+	query := `SELECT TRIGGER_NAME
+		FROM information_schema.TRIGGERS
+		WHERE EVENT_OBJECT_SCHEMA = ? AND EVENT_OBJECT_TABLE = ?
+		ORDER BY TRIGGER_NAME`
+	rows, err := c.db.Query(query, tm.Schema, tm.Table)
+	if err != nil {
+		return fmt.Errorf("failed to query triggers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("failed to scan trigger: %w", err)
+		}
+		tm.Triggers = append(tm.Triggers, name)
+	}
+	return rows.Err()
+}
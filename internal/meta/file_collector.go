@@ -0,0 +1,186 @@
+package meta
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// MetaFileVersion はメタデータファイルの現行フォーマットバージョン。
+const MetaFileVersion = 1
+
+// MetaFile はオフライン解析用のメタデータファイルを表す。
+type MetaFile struct {
+	Version      int    `json:"version"`
+	MySQLVersion string `json:"mysql_version,omitempty"`
+	// Variables はオンラインスキーマ変更ツールの互換性確認に使うサーバー変数
+	Variables *ServerVariables `json:"variables,omitempty"`
+	Tables    []TableMeta      `json:"tables"`
+}
+
+// ReadMetaFile はJSONからメタデータファイルを読み込む。
+// トップレベルが配列の場合は TableMeta のリストとして扱う。
+func ReadMetaFile(r io.Reader) (*MetaFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata file: %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var tables []TableMeta
+		if err := json.Unmarshal(trimmed, &tables); err != nil {
+			return nil, fmt.Errorf("failed to decode metadata file: %w", err)
+		}
+		return &MetaFile{Version: MetaFileVersion, Tables: tables}, nil
+	}
+
+	var mf MetaFile
+	if err := json.Unmarshal(trimmed, &mf); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata file: %w", err)
+	}
+	if mf.Version == 0 {
+		mf.Version = MetaFileVersion
+	}
+	if mf.Version > MetaFileVersion {
+		return nil, fmt.Errorf("unsupported metadata file version %d (supported: %d)", mf.Version, MetaFileVersion)
+	}
+	return &mf, nil
+}
+
+// LoadMetaFile は指定パスのメタデータファイルを読み込む。
+func LoadMetaFile(path string) (*MetaFile, error) {
+	f, err := os.Open(path) // #nosec G304 -- ユーザー指定のメタデータファイルを読む
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metadata file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return ReadMetaFile(f)
+}
+
+// FileCollector はメモリ上のテーブルメタデータから情報を返す。
+// DB接続なしのオフライン解析で使用する。
+type FileCollector struct {
+	tables       map[string]*TableMeta
+	byTable      map[string][]string
+	mysqlVersion string
+	variables    *ServerVariables
+}
+
+// NewFileCollector は指定テーブル群から FileCollector を作成する。
+// ReferencedBy が空のテーブルは、他テーブルの ForeignKeys から逆引きして補完する。
+func NewFileCollector(tables []TableMeta, mysqlVersion string) *FileCollector {
+	linked := make([]TableMeta, len(tables))
+	copy(linked, tables)
+	LinkReferencedBy(linked)
+
+	c := &FileCollector{
+		tables:       make(map[string]*TableMeta, len(linked)),
+		byTable:      make(map[string][]string),
+		mysqlVersion: mysqlVersion,
+	}
+	for i := range linked {
+		tm := &linked[i]
+		key := tableKey(tm.Schema, tm.Table)
+		if _, dup := c.tables[key]; !dup {
+			c.byTable[strings.ToLower(tm.Table)] = append(c.byTable[strings.ToLower(tm.Table)], key)
+		}
+		c.tables[key] = tm
+	}
+	return c
+}
+
+// NewFileCollectorFromMetaFile はメタデータファイルから FileCollector を作成する。
+// mysqlVersion が空の場合はファイルに記録されたバージョンを使用する。
+func NewFileCollectorFromMetaFile(mf *MetaFile, mysqlVersion string) *FileCollector {
+	if mysqlVersion == "" {
+		mysqlVersion = mf.MySQLVersion
+	}
+	c := NewFileCollector(mf.Tables, mysqlVersion)
+	c.variables = mf.Variables
+	return c
+}
+
+// GetMySQLVersion はMySQLバージョンを返す。
+func (c *FileCollector) GetMySQLVersion() string {
+	return c.mysqlVersion
+}
+
+// GetTableMeta は指定テーブルのメタデータを返す。
+// schema が空の場合はテーブル名のみで検索し、一意に特定できる場合に返す。
+func (c *FileCollector) GetTableMeta(schema, table string) (*TableMeta, error) {
+	var tm *TableMeta
+	if schema != "" {
+		tm = c.tables[tableKey(schema, table)]
+		if tm == nil {
+			// スキーマ未記載のメタデータファイルにも対応する
+			tm = c.tables[tableKey("", table)]
+		}
+	} else {
+		keys := c.byTable[strings.ToLower(table)]
+		if len(keys) > 1 {
+			return nil, fmt.Errorf("table %s is ambiguous in metadata file (found in %d schemas)", table, len(keys))
+		}
+		if len(keys) == 1 {
+			tm = c.tables[keys[0]]
+		}
+	}
+	if tm == nil {
+		return nil, fmt.Errorf("table %s not found in metadata file", qualify(schema, table))
+	}
+
+	result := *tm
+	if c.mysqlVersion != "" {
+		result.MySQLVersion = c.mysqlVersion
+	}
+	return &result, nil
+}
+
+// LinkReferencedBy は各テーブルの ForeignKeys から逆方向の ReferencedBy を構築する。
+// 既に ReferencedBy を持つテーブルは変更しない。
+func LinkReferencedBy(tables []TableMeta) {
+	index := make(map[string]int, len(tables))
+	for i := range tables {
+		index[tableKey(tables[i].Schema, tables[i].Table)] = i
+	}
+
+	hasReferencedBy := make(map[int]bool, len(tables))
+	for i := range tables {
+		if len(tables[i].ReferencedBy) > 0 {
+			hasReferencedBy[i] = true
+		}
+	}
+
+	for i := range tables {
+		for _, fk := range tables[i].ForeignKeys {
+			if fk.SourceSchema == "" {
+				fk.SourceSchema = tables[i].Schema
+			}
+			if fk.SourceTable == "" {
+				fk.SourceTable = tables[i].Table
+			}
+			if fk.ReferencedSchema == "" {
+				fk.ReferencedSchema = fk.SourceSchema
+			}
+			j, ok := index[tableKey(fk.ReferencedSchema, fk.ReferencedTable)]
+			if !ok || hasReferencedBy[j] {
+				continue
+			}
+			tables[j].ReferencedBy = append(tables[j].ReferencedBy, fk)
+		}
+	}
+}
+
+func tableKey(schema, table string) string {
+	return strings.ToLower(schema) + "." + strings.ToLower(table)
+}
+
+func qualify(schema, table string) string {
+	if schema == "" {
+		return table
+	}
+	return schema + "." + table
+}
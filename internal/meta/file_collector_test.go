@@ -0,0 +1,187 @@
+package meta
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleMetaFile = `{
+  "version": 1,
+  "mysql_version": "8.0.32",
+  "tables": [
+    {
+      "schema": "mydb",
+      "table": "users",
+      "engine": "InnoDB",
+      "row_count": 1000,
+      "columns": [{"name": "id", "ordinal_position": 1, "data_type": "int", "column_type": "int"}],
+      "indexes": [{"name": "PRIMARY", "columns": ["id"], "is_unique": true, "is_primary": true, "index_type": "BTREE"}]
+    },
+    {
+      "schema": "mydb",
+      "table": "orders",
+      "engine": "InnoDB",
+      "foreign_keys": [{
+        "constraint_name": "fk_orders_user_id",
+        "source_columns": ["user_id"],
+        "referenced_table": "users",
+        "referenced_columns": ["id"]
+      }],
+      "is_partitioned": true,
+      "partition_type": "RANGE"
+    }
+  ]
+}`
+
+func TestReadMetaFile(t *testing.T) {
+	mf, err := ReadMetaFile(strings.NewReader(sampleMetaFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mf.Version != MetaFileVersion {
+		t.Errorf("バージョンが%dであること: got %d", MetaFileVersion, mf.Version)
+	}
+	if mf.MySQLVersion != "8.0.32" {
+		t.Errorf("MySQLバージョンが8.0.32であること: got %s", mf.MySQLVersion)
+	}
+	if len(mf.Tables) != 2 {
+		t.Fatalf("テーブル数が2であること: got %d", len(mf.Tables))
+	}
+}
+
+func TestReadMetaFileArray(t *testing.T) {
+	// トップレベル配列形式も受け付けること
+	mf, err := ReadMetaFile(strings.NewReader(`[{"schema": "mydb", "table": "users"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mf.Tables) != 1 || mf.Tables[0].Table != "users" {
+		t.Errorf("usersテーブルが読み込まれること: got %+v", mf.Tables)
+	}
+}
+
+func TestReadMetaFileUnsupportedVersion(t *testing.T) {
+	_, err := ReadMetaFile(strings.NewReader(`{"version": 99, "tables": []}`))
+	if err == nil {
+		t.Error("未対応バージョンでエラーになること")
+	}
+}
+
+func TestFileCollectorGetTableMeta(t *testing.T) {
+	mf, err := ReadMetaFile(strings.NewReader(sampleMetaFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewFileCollectorFromMetaFile(mf, "")
+
+	tm, err := c.GetTableMeta("mydb", "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tm.IsPartitioned || tm.PartitionType != "RANGE" {
+		t.Errorf("パーティション情報が保持されること: got %v %s", tm.IsPartitioned, tm.PartitionType)
+	}
+	if tm.MySQLVersion != "8.0.32" {
+		t.Errorf("ファイルのMySQLバージョンが設定されること: got %s", tm.MySQLVersion)
+	}
+
+	// スキーマ省略時はテーブル名で検索
+	if _, err := c.GetTableMeta("", "users"); err != nil {
+		t.Errorf("スキーマ省略で検索できること: %v", err)
+	}
+
+	if _, err := c.GetTableMeta("mydb", "missing"); err == nil {
+		t.Error("存在しないテーブルでエラーになること")
+	}
+}
+
+func TestFileCollectorVersionOverride(t *testing.T) {
+	mf, err := ReadMetaFile(strings.NewReader(sampleMetaFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewFileCollectorFromMetaFile(mf, "8.0.25")
+	if c.GetMySQLVersion() != "8.0.25" {
+		t.Errorf("指定バージョンが優先されること: got %s", c.GetMySQLVersion())
+	}
+	tm, err := c.GetTableMeta("mydb", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tm.MySQLVersion != "8.0.25" {
+		t.Errorf("TableMetaにも指定バージョンが設定されること: got %s", tm.MySQLVersion)
+	}
+}
+
+func TestFileCollectorAmbiguousTable(t *testing.T) {
+	c := NewFileCollector([]TableMeta{
+		{Schema: "a", Table: "users"},
+		{Schema: "b", Table: "users"},
+	}, "8.0")
+	if _, err := c.GetTableMeta("", "users"); err == nil {
+		t.Error("複数スキーマに同名テーブルがある場合はエラーになること")
+	}
+	if _, err := c.GetTableMeta("b", "users"); err != nil {
+		t.Errorf("スキーマ指定で検索できること: %v", err)
+	}
+}
+
+func TestLinkReferencedBy(t *testing.T) {
+	mf, err := ReadMetaFile(strings.NewReader(sampleMetaFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewFileCollectorFromMetaFile(mf, "")
+	users, err := c.GetTableMeta("mydb", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users.ReferencedBy) != 1 {
+		t.Fatalf("usersがordersから参照されること: got %d", len(users.ReferencedBy))
+	}
+	fk := users.ReferencedBy[0]
+	if fk.SourceSchema != "mydb" || fk.SourceTable != "orders" {
+		t.Errorf("参照元がmydb.ordersであること: got %s.%s", fk.SourceSchema, fk.SourceTable)
+	}
+	if fk.ReferencedSchema != "mydb" {
+		t.Errorf("参照先スキーマが補完されること: got %s", fk.ReferencedSchema)
+	}
+}
+
+func TestLinkReferencedByKeepsExisting(t *testing.T) {
+	existing := ForeignKeyMeta{ConstraintName: "fk_existing", SourceTable: "x"}
+	tables := []TableMeta{
+		{Schema: "mydb", Table: "users", ReferencedBy: []ForeignKeyMeta{existing}},
+		{Schema: "mydb", Table: "orders", ForeignKeys: []ForeignKeyMeta{{ConstraintName: "fk_new", ReferencedTable: "users"}}},
+	}
+	LinkReferencedBy(tables)
+	if len(tables[0].ReferencedBy) != 1 || tables[0].ReferencedBy[0].ConstraintName != "fk_existing" {
+		t.Errorf("既存のReferencedByは変更されないこと: got %+v", tables[0].ReferencedBy)
+	}
+}
+
+func TestFileCollectorServerVariables(t *testing.T) {
+	mf, err := ReadMetaFile(strings.NewReader(`{
+  "version": 1,
+  "variables": {"binlog_format": "ROW", "binlog_row_image": "FULL"},
+  "tables": [{"schema": "mydb", "table": "users", "triggers": ["trg_users_audit"]}]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewFileCollectorFromMetaFile(mf, "8.0.32")
+	vars, err := c.GetServerVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars == nil || vars.BinlogFormat != "ROW" || vars.BinlogRowImage != "FULL" {
+		t.Errorf("メタデータファイルのサーバー変数を返すこと: got %+v", vars)
+	}
+	tm, err := c.GetTableMeta("mydb", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tm.Triggers) != 1 || tm.Triggers[0] != "trg_users_audit" {
+		t.Errorf("トリガー名が読み込まれること: got %v", tm.Triggers)
+	}
+}
@@ -0,0 +1,123 @@
+package meta
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MaxRowVersions は INSTANT ADD/DROP COLUMN で作成できる行バージョン数の上限（MySQL 8.0.29+）。
+// 上限に達したテーブルでは INSTANT が使えず、テーブル再構築が必要になる。
+const MaxRowVersions = 64
+
+// innodbPartitionSeparator は information_schema.INNODB_TABLES のパーティション名の区切り（db/t#p#p0）。
+const innodbPartitionSeparator = "#p#"
+
+// rowVersionColumns は行バージョン数の取得カラム。TOTAL_ROW_VERSIONS は 8.0.29+、INSTANT_COLS は 8.0.12+ に存在する。
+// 古いバージョンではカラムが存在しないエラーの場合だけ、存在するカラムのみで再試行する。
+var rowVersionColumns = []string{
+	"TOTAL_ROW_VERSIONS, INSTANT_COLS",
+	"0, INSTANT_COLS",
+}
+
+// fetchRowVersions は INNODB_TABLES から行バージョン数を取得する。
+// INNODB_TABLES は PROCESS 権限が必要で、MySQL 5.7 には存在しないため、取得できない場合は 0 のままにして警告する。
+func (c *DBCollector) fetchRowVersions(tm *TableMeta) error {
+	name := tm.Schema + "/" + tm.Table
+	b := newSnapshotBuilder()
+	b.addTable(TableMeta{Schema: tm.Schema, Table: tm.Table})
+	if err := c.queryRowVersions(b, "LOWER(NAME) = LOWER(?) OR LOWER(NAME) LIKE LOWER(?)",
+		name, escapeLike(name)+innodbPartitionSeparator+"%"); err != nil {
+		c.warnf("%v — row versions of %s.%s are unknown, so the INSTANT row version limit is not checked", err, tm.Schema, tm.Table)
+		return nil
+	}
+	found := b.table(tm.Schema, tm.Table)
+	tm.TotalRowVersions = found.TotalRowVersions
+	tm.InstantCols = found.InstantCols
+	return nil
+}
+
+func (c *DBCollector) snapshotRowVersions(schemas []string, b *snapshotBuilder) error {
+	if err := c.queryRowVersions(b, "SUBSTRING_INDEX(NAME, '/', 1) IN ("+placeholders(len(schemas))+")", stringArgs(schemas)...); err != nil {
+		c.warnf("%v — row versions are not recorded, so the INSTANT row version limit is not checked", err)
+	}
+	return nil
+}
+
+// queryRowVersions は条件に一致する INNODB_TABLES の行バージョン数を b のテーブルに反映する。
+func (c *DBCollector) queryRowVersions(b *snapshotBuilder, where string, args ...interface{}) error {
+	var err error
+	for _, cols := range rowVersionColumns {
+		// #nosec G202 -- 連結するのは固定のカラム名と条件式のみ
+		query := `SELECT NAME, ` + cols + `
+			FROM information_schema.INNODB_TABLES
+			WHERE ` + where
+		var rows *sql.Rows
+		rows, err = c.db.Query(query, args...)
+		if isUnknownColumnError(err) {
+			continue
+		}
+		if err != nil {
+			break
+		}
+		return scanRowVersions(rows, b)
+	}
+	return fmt.Errorf("failed to query row versions: %w", err)
+}
+
+func scanRowVersions(rows *sql.Rows, b *snapshotBuilder) error {
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var name string
+		var versions, instantCols sql.NullInt64
+		if err := rows.Scan(&name, &versions, &instantCols); err != nil {
+			return fmt.Errorf("failed to scan row versions: %w", err)
+		}
+		b.addRowVersions(name, int(versions.Int64), int(instantCols.Int64))
+	}
+	return rows.Err()
+}
+
+// erBadField は存在しないカラムを参照した場合の MySQL のエラー番号（ER_BAD_FIELD_ERROR）。
+const erBadField = 1054
+
+// isUnknownColumnError は err が存在しないカラムの参照によるエラーかを返す。
+func isUnknownColumnError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == erBadField
+}
+
+// addRowVersions は INNODB_TABLES の1行の行バージョン数をテーブルに反映する。
+// パーティションテーブルはパーティションごとの行になるため、最大値を採用する。
+func (b *snapshotBuilder) addRowVersions(innodbName string, versions, instantCols int) {
+	schema, table, ok := splitInnoDBTableName(innodbName)
+	if !ok {
+		return
+	}
+	tm := b.table(schema, table)
+	if tm == nil {
+		return
+	}
+	tm.TotalRowVersions = max(tm.TotalRowVersions, versions)
+	tm.InstantCols = max(tm.InstantCols, instantCols)
+}
+
+// splitInnoDBTableName は INNODB_TABLES の NAME（schema/table または schema/table#p#partition）を分解する。
+func splitInnoDBTableName(name string) (schema, table string, ok bool) {
+	schema, table, ok = strings.Cut(name, "/")
+	if !ok || schema == "" || table == "" {
+		return "", "", false
+	}
+	if i := strings.Index(strings.ToLower(table), innodbPartitionSeparator); i >= 0 {
+		table = table[:i]
+	}
+	return schema, table, true
+}
+
+// escapeLike は LIKE パターンのワイルドカード文字をエスケープする。
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,340 @@
+package meta
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Snapshot は指定スキーマ内の全テーブルのメタデータを一括取得する。
+// schemas が空の場合はコレクターのデフォルトデータベースを対象とする。
+// information_schema へのクエリはテーブル単位ではなくスキーマ単位でまとめて実行する。
+func (c *DBCollector) Snapshot(schemas []string) (*MetaFile, error) {
+	if len(schemas) == 0 {
+		schemas = []string{c.database}
+	}
+
+	b := newSnapshotBuilder()
+	steps := []func([]string, *snapshotBuilder) error{
+		c.snapshotTables,
+		c.snapshotColumns,
+		c.snapshotIndexes,
+		c.snapshotForeignKeys,
+		c.snapshotPartitions,
+		c.snapshotRowVersions,
+		c.snapshotTriggers,
+	}
+	for _, step := range steps {
+		if err := step(schemas, b); err != nil {
+			return nil, err
+		}
+	}
+
+	// サーバー変数は @@global を参照できない場合があるため、取得できなければ記録せず（不明として）警告する
+	vars, err := c.GetServerVariables()
+	if err != nil {
+		c.warnf("%v — server variables are not recorded", err)
+	}
+
+	return &MetaFile{
+		Version:      MetaFileVersion,
+		MySQLVersion: c.mysqlVersion,
+		Variables:    vars,
+		Tables:       b.tables(c.mysqlVersion),
+	}, nil
+}
+
+// WriteMetaFile はメタデータファイルをJSONとして書き出す。
+func WriteMetaFile(w io.Writer, mf *MetaFile) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(mf)
+}
+
+func (c *DBCollector) snapshotTables(schemas []string, b *snapshotBuilder) error {
+	// #nosec G202 -- IN句にはプレースホルダのみを連結する
+	query := `SELECT TABLE_SCHEMA, TABLE_NAME, ENGINE, TABLE_ROWS, DATA_LENGTH, INDEX_LENGTH
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA IN (` + placeholders(len(schemas)) + `)
+			AND TABLE_TYPE = 'BASE TABLE'
+		ORDER BY TABLE_SCHEMA, TABLE_NAME`
+	rows, err := c.db.Query(query, stringArgs(schemas)...)
+	if err != nil {
+		return fmt.Errorf("failed to query tables: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var schema, table string
+		var engine sql.NullString
+		var rowCount, dataLen, idxLen sql.NullInt64
+		if err := rows.Scan(&schema, &table, &engine, &rowCount, &dataLen, &idxLen); err != nil {
+			return fmt.Errorf("failed to scan table: %w", err)
+		}
+		b.addTable(TableMeta{
+			Schema:      schema,
+			Table:       table,
+			Engine:      engine.String,
+			RowCount:    rowCount.Int64,
+			DataLength:  dataLen.Int64,
+			IndexLength: idxLen.Int64,
+		})
+	}
+	return rows.Err()
+}
+
+func (c *DBCollector) snapshotColumns(schemas []string, b *snapshotBuilder) error {
+	// #nosec G202 -- IN句にはプレースホルダのみを連結する
+	query := `SELECT TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION, DATA_TYPE, COLUMN_TYPE,
+		IS_NULLABLE, COLUMN_KEY, COLUMN_DEFAULT, EXTRA,
+		CHARACTER_SET_NAME, COLLATION_NAME
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA IN (` + placeholders(len(schemas)) + `)
+		ORDER BY TABLE_SCHEMA, TABLE_NAME, ORDINAL_POSITION`
+	rows, err := c.db.Query(query, stringArgs(schemas)...)
+	if err != nil {
+		return fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var schema, table, isNullable string
+		var col ColumnMeta
+		var defaultVal, charset, collation sql.NullString
+		if err := rows.Scan(&schema, &table, &col.Name, &col.OrdinalPos, &col.DataType, &col.ColumnType,
+			&isNullable, &col.ColumnKey, &defaultVal, &col.Extra,
+			&charset, &collation); err != nil {
+			return fmt.Errorf("failed to scan column: %w", err)
+		}
+		col.IsNullable = strings.EqualFold(isNullable, "YES")
+		col.DefaultValue = defaultVal.String
+		col.HasDefault = defaultVal.Valid
+		col.CharacterSet = charset.String
+		col.Collation = collation.String
+		if tm := b.table(schema, table); tm != nil {
+			tm.Columns = append(tm.Columns, col)
+		}
+	}
+	return rows.Err()
+}
+
+func (c *DBCollector) snapshotIndexes(schemas []string, b *snapshotBuilder) error {
+	// #nosec G202 -- IN句にはプレースホルダのみを連結する
+	query := `SELECT TABLE_SCHEMA, TABLE_NAME, INDEX_NAME, COLUMN_NAME, NON_UNIQUE, INDEX_TYPE
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA IN (` + placeholders(len(schemas)) + `)
+		ORDER BY TABLE_SCHEMA, TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX`
+	rows, err := c.db.Query(query, stringArgs(schemas)...)
+	if err != nil {
+		return fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var schema, table, indexName, indexType string
+		var colName sql.NullString
+		var nonUnique int
+		if err := rows.Scan(&schema, &table, &indexName, &colName, &nonUnique, &indexType); err != nil {
+			return fmt.Errorf("failed to scan index: %w", err)
+		}
+		b.addIndexColumn(schema, table, IndexMeta{
+			Name:      indexName,
+			IsUnique:  nonUnique == 0,
+			IsPrimary: indexName == "PRIMARY",
+			IndexType: indexType,
+		}, colName.String)
+	}
+	return rows.Err()
+}
+
+func (c *DBCollector) snapshotForeignKeys(schemas []string, b *snapshotBuilder) error {
+	in := placeholders(len(schemas))
+	// #nosec G202 -- IN句にはプレースホルダのみを連結する
+	query := `SELECT kcu.CONSTRAINT_NAME, kcu.TABLE_SCHEMA, kcu.TABLE_NAME,
+		kcu.COLUMN_NAME, kcu.REFERENCED_TABLE_SCHEMA, kcu.REFERENCED_TABLE_NAME,
+		kcu.REFERENCED_COLUMN_NAME, rc.DELETE_RULE, rc.UPDATE_RULE
+		FROM information_schema.KEY_COLUMN_USAGE kcu
+		JOIN information_schema.REFERENTIAL_CONSTRAINTS rc
+			ON kcu.CONSTRAINT_NAME = rc.CONSTRAINT_NAME
+			AND kcu.CONSTRAINT_SCHEMA = rc.CONSTRAINT_SCHEMA
+		WHERE (kcu.TABLE_SCHEMA IN (` + in + `) OR kcu.REFERENCED_TABLE_SCHEMA IN (` + in + `))
+			AND kcu.REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY kcu.TABLE_SCHEMA, kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`
+	args := append(stringArgs(schemas), stringArgs(schemas)...)
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var name, srcSchema, srcTable, srcCol, refSchema, refTable, refCol, onDel, onUpd string
+		if err := rows.Scan(&name, &srcSchema, &srcTable, &srcCol,
+			&refSchema, &refTable, &refCol, &onDel, &onUpd); err != nil {
+			return fmt.Errorf("failed to scan foreign key: %w", err)
+		}
+		b.addForeignKeyColumn(ForeignKeyMeta{
+			ConstraintName:   name,
+			SourceSchema:     srcSchema,
+			SourceTable:      srcTable,
+			ReferencedSchema: refSchema,
+			ReferencedTable:  refTable,
+			OnDelete:         onDel,
+			OnUpdate:         onUpd,
+		}, srcCol, refCol)
+	}
+	return rows.Err()
+}
+
+func (c *DBCollector) snapshotPartitions(schemas []string, b *snapshotBuilder) error {
+	// #nosec G202 -- IN句にはプレースホルダのみを連結する
+	query := `SELECT TABLE_SCHEMA, TABLE_NAME, MAX(PARTITION_METHOD)
+		FROM information_schema.PARTITIONS
+		WHERE TABLE_SCHEMA IN (` + placeholders(len(schemas)) + `)
+			AND PARTITION_NAME IS NOT NULL
+		GROUP BY TABLE_SCHEMA, TABLE_NAME`
+	rows, err := c.db.Query(query, stringArgs(schemas)...)
+	if err != nil {
+		return fmt.Errorf("failed to query partition info: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var schema, table string
+		var method sql.NullString
+		if err := rows.Scan(&schema, &table, &method); err != nil {
+			return fmt.Errorf("failed to scan partition info: %w", err)
+		}
+		if tm := b.table(schema, table); tm != nil && method.Valid {
+			tm.IsPartitioned = true
+			tm.PartitionType = method.String
+		}
+	}
+	return rows.Err()
+}
+
+func (c *DBCollector) snapshotTriggers(schemas []string, b *snapshotBuilder) error {
+	// #nosec G202 -- IN句にはプレースホルダのみを連結する
+	query := `SELECT EVENT_OBJECT_SCHEMA, EVENT_OBJECT_TABLE, TRIGGER_NAME
+		FROM information_schema.TRIGGERS
+		WHERE EVENT_OBJECT_SCHEMA IN (` + placeholders(len(schemas)) + `)
+		ORDER BY EVENT_OBJECT_SCHEMA, EVENT_OBJECT_TABLE, TRIGGER_NAME`
+	rows, err := c.db.Query(query, stringArgs(schemas)...)
+	if err != nil {
+		return fmt.Errorf("failed to query triggers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var schema, table, name string
+		if err := rows.Scan(&schema, &table, &name); err != nil {
+			return fmt.Errorf("failed to scan trigger: %w", err)
+		}
+		if tm := b.table(schema, table); tm != nil {
+			tm.Triggers = append(tm.Triggers, name)
+		}
+	}
+	return rows.Err()
+}
+
+// snapshotBuilder は一括クエリの結果をテーブル単位の TableMeta に組み立てる。
+type snapshotBuilder struct {
+	byKey map[string]*TableMeta
+	order []string
+	// インデックス・FKは複数行（カラム単位）で返るため、名前単位で集約する
+	indexes map[string]int
+	fks     map[string]*ForeignKeyMeta
+	fkOrder []string
+}
+
+func newSnapshotBuilder() *snapshotBuilder {
+	return &snapshotBuilder{
+		byKey:   make(map[string]*TableMeta),
+		indexes: make(map[string]int),
+		fks:     make(map[string]*ForeignKeyMeta),
+	}
+}
+
+func (b *snapshotBuilder) addTable(tm TableMeta) {
+	key := tableKey(tm.Schema, tm.Table)
+	if _, ok := b.byKey[key]; ok {
+		return
+	}
+	b.byKey[key] = &tm
+	b.order = append(b.order, key)
+}
+
+func (b *snapshotBuilder) table(schema, table string) *TableMeta {
+	return b.byKey[tableKey(schema, table)]
+}
+
+func (b *snapshotBuilder) addIndexColumn(schema, table string, idx IndexMeta, column string) {
+	tm := b.table(schema, table)
+	if tm == nil {
+		return
+	}
+	key := tableKey(schema, table) + "\x00" + idx.Name
+	pos, ok := b.indexes[key]
+	if !ok {
+		tm.Indexes = append(tm.Indexes, idx)
+		pos = len(tm.Indexes) - 1
+		b.indexes[key] = pos
+	}
+	if column != "" {
+		tm.Indexes[pos].Columns = append(tm.Indexes[pos].Columns, column)
+	} else {
+		// 関数インデックスの式のキー部は COLUMN_NAME が NULL になる
+		tm.Indexes[pos].HasExpression = true
+	}
+}
+
+func (b *snapshotBuilder) addForeignKeyColumn(fk ForeignKeyMeta, srcCol, refCol string) {
+	key := tableKey(fk.SourceSchema, fk.SourceTable) + "\x00" + fk.ConstraintName
+	existing, ok := b.fks[key]
+	if !ok {
+		existing = &fk
+		b.fks[key] = existing
+		b.fkOrder = append(b.fkOrder, key)
+	}
+	existing.SourceColumns = append(existing.SourceColumns, srcCol)
+	existing.ReferencedColumns = append(existing.ReferencedColumns, refCol)
+}
+
+// tables は組み立て済みの TableMeta を取得順に返す。
+// FK はスナップショット対象テーブルの ForeignKeys / ReferencedBy に振り分ける。
+func (b *snapshotBuilder) tables(mysqlVersion string) []TableMeta {
+	for _, key := range b.fkOrder {
+		fk := *b.fks[key]
+		if src := b.table(fk.SourceSchema, fk.SourceTable); src != nil {
+			src.ForeignKeys = append(src.ForeignKeys, fk)
+		}
+		if ref := b.table(fk.ReferencedSchema, fk.ReferencedTable); ref != nil {
+			ref.ReferencedBy = append(ref.ReferencedBy, fk)
+		}
+	}
+
+	result := make([]TableMeta, 0, len(b.order))
+	for _, key := range b.order {
+		tm := *b.byKey[key]
+		tm.MySQLVersion = mysqlVersion
+		result = append(result, tm)
+	}
+	return result
+}
+
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func stringArgs(values []string) []interface{} {
+	args := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return args
+}
@@ -0,0 +1,132 @@
+package meta
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestSnapshotBuilder(t *testing.T) {
+	// 一括クエリの行データがテーブル単位に集約されることを検証
+	b := newSnapshotBuilder()
+	b.addTable(TableMeta{Schema: "mydb", Table: "users", Engine: "InnoDB"})
+	b.addTable(TableMeta{Schema: "mydb", Table: "orders", Engine: "InnoDB"})
+
+	b.addIndexColumn("mydb", "users", IndexMeta{Name: "PRIMARY", IsPrimary: true, IsUnique: true}, "id")
+	b.addIndexColumn("mydb", "orders", IndexMeta{Name: "idx_user_created"}, "user_id")
+	b.addIndexColumn("mydb", "orders", IndexMeta{Name: "idx_user_created"}, "created_at")
+	b.addIndexColumn("otherdb", "ignored", IndexMeta{Name: "PRIMARY"}, "id")
+
+	fk := ForeignKeyMeta{
+		ConstraintName:   "fk_orders_user",
+		SourceSchema:     "mydb",
+		SourceTable:      "orders",
+		ReferencedSchema: "mydb",
+		ReferencedTable:  "users",
+	}
+	b.addForeignKeyColumn(fk, "user_id", "id")
+	// 対象外スキーマからの参照はReferencedByのみに反映される
+	b.addForeignKeyColumn(ForeignKeyMeta{
+		ConstraintName:   "fk_audit_user",
+		SourceSchema:     "audit",
+		SourceTable:      "logs",
+		ReferencedSchema: "mydb",
+		ReferencedTable:  "users",
+	}, "user_id", "id")
+
+	tables := b.tables("8.0.32")
+	if len(tables) != 2 {
+		t.Fatalf("テーブル数が2であること: got %d", len(tables))
+	}
+	users, orders := tables[0], tables[1]
+
+	if len(orders.Indexes) != 1 || len(orders.Indexes[0].Columns) != 2 {
+		t.Errorf("複合インデックスが1つに集約されること: got %+v", orders.Indexes)
+	}
+	if len(orders.ForeignKeys) != 1 || orders.ForeignKeys[0].SourceColumns[0] != "user_id" {
+		t.Errorf("ordersにFKが設定されること: got %+v", orders.ForeignKeys)
+	}
+	if len(users.ReferencedBy) != 2 {
+		t.Errorf("usersのReferencedByが2件であること: got %d", len(users.ReferencedBy))
+	}
+	if users.MySQLVersion != "8.0.32" {
+		t.Errorf("MySQLバージョンが設定されること: got %s", users.MySQLVersion)
+	}
+}
+
+func TestWriteMetaFileRoundTrip(t *testing.T) {
+	// スナップショットの出力がオフラインコレクターで読み戻せることを検証
+	mf := &MetaFile{
+		Version:      MetaFileVersion,
+		MySQLVersion: "8.0.32",
+		Tables:       []TableMeta{{Schema: "mydb", Table: "users", IsPartitioned: true, PartitionType: "HASH"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteMetaFile(&buf, mf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadMetaFile(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewFileCollectorFromMetaFile(got, "")
+	tm, err := c.GetTableMeta("mydb", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tm.PartitionType != "HASH" || tm.MySQLVersion != "8.0.32" {
+		t.Errorf("パーティション情報とバージョンが保持されること: got %s %s", tm.PartitionType, tm.MySQLVersion)
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, ""},
+		{1, "?"},
+		{3, "?, ?, ?"},
+	}
+	for _, tt := range tests {
+		if got := placeholders(tt.n); got != tt.want {
+			t.Errorf("placeholders(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestSnapshotBuilderRowVersions(t *testing.T) {
+	// INNODB_TABLES の行がテーブルに反映され、パーティションは最大値になることを検証
+	b := newSnapshotBuilder()
+	b.addTable(TableMeta{Schema: "mydb", Table: "users"})
+	b.addTable(TableMeta{Schema: "mydb", Table: "events"})
+
+	b.addRowVersions("mydb/users", 5, 0)
+	b.addRowVersions("mydb/events#p#p0", 3, 0)
+	b.addRowVersions("mydb/events#P#p1", 7, 2)
+	b.addRowVersions("mydb/unknown", 9, 0)
+	b.addRowVersions("invalid", 9, 0)
+
+	tables := b.tables("8.0.32")
+	if tables[0].TotalRowVersions != 5 {
+		t.Errorf("usersの行バージョン数が5であること: got %d", tables[0].TotalRowVersions)
+	}
+	if tables[1].TotalRowVersions != 7 || tables[1].InstantCols != 2 {
+		t.Errorf("eventsはパーティションの最大値になること: got %d, %d", tables[1].TotalRowVersions, tables[1].InstantCols)
+	}
+}
+
+func TestIsUnknownColumnError(t *testing.T) {
+	// 古いバージョンで存在しないカラムのエラーだけを再試行の対象にする
+	if !isUnknownColumnError(fmt.Errorf("query: %w", &mysql.MySQLError{Number: 1054, Message: "Unknown column 'TOTAL_ROW_VERSIONS'"})) {
+		t.Error("ER_BAD_FIELD_ERROR は再試行の対象とすること")
+	}
+	for _, err := range []error{nil, errors.New("connection refused"), &mysql.MySQLError{Number: 1227, Message: "Access denied; you need the PROCESS privilege"}} {
+		if isUnknownColumnError(err) {
+			t.Errorf("%v は再試行の対象としないこと", err)
+		}
+	}
+}
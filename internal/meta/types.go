@@ -7,6 +7,8 @@ const (
 	AlgorithmInstant Algorithm = "INSTANT"
 	AlgorithmInplace Algorithm = "INPLACE"
 	AlgorithmCopy    Algorithm = "COPY"
+	// AlgorithmDefault は ALGORITHM=DEFAULT の明示指定を表す。
+	AlgorithmDefault Algorithm = "DEFAULT"
 )
 
 // LockLevel はDDL実行中のロックレベルを表す。
@@ -16,6 +18,8 @@ const (
 	LockNone      LockLevel = "NONE"
 	LockShared    LockLevel = "SHARED"
 	LockExclusive LockLevel = "EXCLUSIVE"
+	// LockDefault は LOCK=DEFAULT の明示指定を表す。
+	LockDefault LockLevel = "DEFAULT"
 )
 
 // RiskLevel はDDL操作のリスクレベルを表す。
@@ -43,6 +47,12 @@ type TableMeta struct {
 	MySQLVersion  string           `json:"mysql_version"`
 	IsPartitioned bool             `json:"is_partitioned"`
 	PartitionType string           `json:"partition_type,omitempty"` // RANGE, LIST, HASH, KEY, etc.
+	// TotalRowVersions は INSTANT ADD/DROP COLUMN で作成された行バージョン数（MySQL 8.0.29+）
+	TotalRowVersions int `json:"total_row_versions,omitempty"`
+	// InstantCols は最初の INSTANT ADD COLUMN 前のカラム数（MySQL 8.0.12〜8.0.28、参考値）
+	InstantCols int `json:"instant_cols,omitempty"`
+	// Triggers はテーブルに定義されたトリガー名
+	Triggers []string `json:"triggers,omitempty"`
 }
 
 // ColumnMeta はテーブルカラムのメタデータを保持する。
@@ -54,6 +64,8 @@ type ColumnMeta struct {
 	IsNullable   bool   `json:"is_nullable"`
 	ColumnKey    string `json:"column_key"`
 	DefaultValue string `json:"default_value"`
+	// HasDefault は DEFAULT が定義されていること（DEFAULT '' と DEFAULT なしを区別する）
+	HasDefault   bool   `json:"has_default,omitempty"`
 	Extra        string `json:"extra"`
 	CharacterSet string `json:"character_set"`
 	Collation    string `json:"collation"`
@@ -66,6 +78,8 @@ type IndexMeta struct {
 	IsUnique  bool     `json:"is_unique"`
 	IsPrimary bool     `json:"is_primary"`
 	IndexType string   `json:"index_type"`
+	// HasExpression は関数インデックスの式（カラム名を持たないキー部）を含むこと。式は Columns に含まない
+	HasExpression bool `json:"has_expression,omitempty"`
 }
 
 // ForeignKeyMeta は外部キー制約のメタデータを保持する。
@@ -128,6 +142,53 @@ const (
 	ActionImportPartitionTablespace  AlterActionType = "IMPORT_PARTITION_TABLESPACE"
 )
 
+// AllActionTypes はサポートしている全ての AlterActionType を返す。
+func AllActionTypes() []AlterActionType {
+	return []AlterActionType{
+		ActionAddColumn,
+		ActionDropColumn,
+		ActionModifyColumn,
+		ActionChangeColumn,
+		ActionRenameColumn,
+		ActionSetDefault,
+		ActionDropDefault,
+		ActionAddIndex,
+		ActionAddUniqueIndex,
+		ActionAddFulltextIndex,
+		ActionDropIndex,
+		ActionRenameIndex,
+		ActionAddPrimaryKey,
+		ActionDropPrimaryKey,
+		ActionAddForeignKey,
+		ActionDropForeignKey,
+		ActionRenameTable,
+		ActionConvertCharset,
+		ActionChangeEngine,
+		ActionChangeRowFormat,
+		ActionAddPartition,
+		ActionDropPartition,
+		ActionAddSpatialIndex,
+		ActionChangeAutoIncrement,
+		ActionChangeKeyBlockSize,
+		ActionForceRebuild,
+		ActionCoalescePartition,
+		ActionReorganizePartition,
+		ActionTruncatePartition,
+		ActionRebuildPartition,
+		ActionRemovePartitioning,
+		ActionPartitionBy,
+		ActionExchangePartition,
+		ActionSpecifyCharset,
+		ActionSetTableStats,
+		ActionTableEncryption,
+		ActionCheckPartition,
+		ActionOptimizePartition,
+		ActionRepairPartition,
+		ActionDiscardPartitionTablespace,
+		ActionImportPartitionTablespace,
+	}
+}
+
 // ActionDetail はALTER操作の詳細情報を保持する。
 type ActionDetail struct {
 	ColumnName     string   `json:"column_name,omitempty"`
@@ -145,6 +206,7 @@ type ActionDetail struct {
 	Engine         string   `json:"engine,omitempty"`
 	Charset        string   `json:"charset,omitempty"`
 	RowFormat      string   `json:"row_format,omitempty"`
+	PartitionType  string   `json:"partition_type,omitempty"` // PARTITION BY の方式 (RANGE, HASH, ...)
 	// カラム属性
 	IsAutoIncrement bool   `json:"is_auto_increment,omitempty"`
 	GeneratedType   string `json:"generated_type,omitempty"` // "", "STORED", "VIRTUAL"
@@ -157,6 +219,8 @@ type ActionDetail struct {
 type AlterAction struct {
 	Type   AlterActionType `json:"type"`
 	Detail ActionDetail    `json:"detail"`
+	// SQL はこのアクションに対応する ALTER TABLE 句を整形したもの（例: "ADD COLUMN `a` INT"）
+	SQL string `json:"sql,omitempty"`
 }
 
 // AlterOperation はパースされたALTER TABLE文を表す。
@@ -165,4 +229,33 @@ type AlterOperation struct {
 	Schema  string        `json:"schema"`
 	Actions []AlterAction `json:"actions"`
 	RawSQL  string        `json:"raw_sql"`
+	// TableRef は元の大文字小文字を保ったクォート済みのテーブル名（例: "`mydb`.`Users`"）
+	TableRef string `json:"-"`
+	// OriginalTable / OriginalSchema は元の大文字小文字を保ったテーブル名・スキーマ名（Table / Schema は小文字）
+	OriginalTable  string `json:"-"`
+	OriginalSchema string `json:"-"`
+	// 明示指定された ALGORITHM/LOCK 句（未指定の場合は空）
+	RequestedAlgorithm Algorithm `json:"requested_algorithm,omitempty"`
+	RequestedLock      LockLevel `json:"requested_lock,omitempty"`
+}
+
+// Severity は検出事項の重要度を表す。
+type Severity string
+
+const (
+	SeverityError   Severity = "ERROR"
+	SeverityWarning Severity = "WARNING"
+	SeverityInfo    Severity = "INFO"
+)
+
+// Finding はALTER文に対する検出事項（MySQLが拒否する指定や注意点など）を表す。
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	// ErrorCode はMySQLが文を拒否する場合のエラー番号（例: 1846）。
+	ErrorCode int    `json:"mysql_error_code,omitempty"`
+	Source    string `json:"source"`
+	// RuleID はポリシー違反の場合の違反したポリシールールのID。
+	RuleID  string `json:"rule_id,omitempty"`
+	Message string `json:"message"`
 }
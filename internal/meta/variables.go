@@ -0,0 +1,32 @@
+package meta
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// ServerVariables はオンラインスキーマ変更ツールの互換性確認に使うサーバー変数。
+// 取得できなかった変数は空文字になる。
+type ServerVariables struct {
+	BinlogFormat   string `json:"binlog_format,omitempty"`
+	BinlogRowImage string `json:"binlog_row_image,omitempty"`
+}
+
+// VariablesProvider はサーバー変数を取得できるコレクター。
+type VariablesProvider interface {
+	GetServerVariables() (*ServerVariables, error)
+}
+
+// GetServerVariables は接続先のグローバル変数を取得する。
+func (c *DBCollector) GetServerVariables() (*ServerVariables, error) {
+	var format, rowImage sql.NullString
+	if err := c.db.QueryRow("SELECT @@global.binlog_format, @@global.binlog_row_image").Scan(&format, &rowImage); err != nil {
+		return nil, fmt.Errorf("failed to query server variables: %w", err)
+	}
+	return &ServerVariables{BinlogFormat: format.String, BinlogRowImage: rowImage.String}, nil
+}
+
+// GetServerVariables はメタデータファイルに記録されたサーバー変数を返す。記録がない場合は nil。
+func (c *FileCollector) GetServerVariables() (*ServerVariables, error) {
+	return c.variables, nil
+}
@@ -0,0 +1,312 @@
+package osc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+// ToolNative はツールを使わない MySQL のオンラインDDL（ALTER TABLE をそのまま実行）。
+const ToolNative Tool = "native"
+
+const (
+	// toolCopyFactor はオンラインスキーマ変更ツールの行コピーがネイティブの COPY より遅くなる倍率。
+	// チャンク単位のコピー、スロットリング、コピー中の DML の反映を見込む。
+	toolCopyFactor = 2.0
+	// nativeBlockingMaxSec は DML をブロックするネイティブDDLを許容する推定実行時間の上限（秒）。
+	nativeBlockingMaxSec = 10
+	// nativeLagMaxSec はレプリカの遅延を許容するネイティブDDLの推定実行時間の上限（秒）。
+	nativeLagMaxSec = 600
+)
+
+// Comparison はネイティブのオンラインDDL・gh-ost・pt-online-schema-change の推定コストの比較結果を表す。
+type Comparison struct {
+	Methods     []MethodEstimate `json:"methods"`
+	Recommended Tool             `json:"recommended"`
+	Reasons     []string         `json:"reasons"`
+}
+
+// MethodEstimate は1つの実行方法の推定コストを表す。
+type MethodEstimate struct {
+	Method Tool `json:"method"`
+	// Duration は推定実行時間（テーブル統計がない場合は nil）
+	Duration *predictor.DurationEstimate `json:"duration_sec,omitempty"`
+	// InitialLock と FinalLock は開始時・終了時に対象テーブルの MDL X を取得する期間
+	InitialLock string `json:"initial_mdl_x"`
+	FinalLock   string `json:"final_mdl_x"`
+	// DMLBlocking は実行中に DML がブロックされる範囲（ブロックされない場合は空）
+	DMLBlocking string `json:"dml_blocking,omitempty"`
+	// ExtraDiskBytes は実行中に追加で必要なディスク容量の概算（binlog を含む。テーブルサイズが不明な場合は nil）
+	ExtraDiskBytes *int64   `json:"extra_disk_bytes,omitempty"`
+	Replication    string   `json:"replication"`
+	ForeignKeys    string   `json:"foreign_keys"`
+	Compatible     bool     `json:"compatible"`
+	Blockers       []string `json:"blockers,omitempty"`
+}
+
+// NeedsComparison は実行方法を比較すべき文（COPY・ブロッキング・テーブル再構築）かを返す。
+func NeedsComparison(v predictor.StatementVerdict) bool {
+	return v.Algorithm != meta.AlgorithmInstant && (NeedsOnlineTool(v) || v.TableRebuild)
+}
+
+// Compare は文全体の判定とテーブルサイズから、ネイティブのオンラインDDL・gh-ost・pt-online-schema-change の
+// 実行時間、MDL X の期間、追加ディスク容量、レプリケーションへの影響、外部キーの制約を見積もり、1つを推奨する。
+func Compare(t Target, predictions []predictor.Prediction, verdict predictor.StatementVerdict, settings predictor.DurationSettings) *Comparison {
+	native := estimateNative(t, predictions, verdict, settings)
+	ghost := estimateGhost(t, settings)
+	ptosc := estimatePTOSC(t, settings)
+	c := &Comparison{Methods: []MethodEstimate{native, ghost, ptosc}}
+	c.Recommended, c.Reasons = recommend(verdict, native, ghost, ptosc)
+	return c
+}
+
+func estimateNative(t Target, predictions []predictor.Prediction, v predictor.StatementVerdict, settings predictor.DurationSettings) MethodEstimate {
+	tm := t.TableMeta
+	e := MethodEstimate{Method: ToolNative, Compatible: true}
+	rebuild := v.TableRebuild || v.Algorithm == meta.AlgorithmCopy
+
+	switch {
+	case v.Algorithm == meta.AlgorithmInstant:
+		e.Duration = &predictor.DurationEstimate{Label: "~0s (metadata only)"}
+	case rebuild:
+		// 1つの文の再構築は1回で行われるため、アクションごとではなく文全体で見積もる
+		e.Duration = predictor.EstimateDuration(settings, predictor.Prediction{Algorithm: v.Algorithm, TableRebuild: true}, tm)
+	default:
+		e.Duration = sumDurations(predictions)
+	}
+
+	if v.Algorithm == meta.AlgorithmInstant {
+		e.InitialLock = "brief (metadata change)"
+		e.FinalLock = "none"
+	} else {
+		e.InitialLock = "brief (prepare phase)"
+		e.FinalLock = "brief (commit phase)"
+	}
+	switch v.Lock {
+	case meta.LockShared:
+		e.DMLBlocking = "writes blocked for the whole run"
+	case meta.LockExclusive:
+		e.DMLBlocking = "reads and writes blocked for the whole run"
+	}
+
+	switch builds := countIndexBuilds(predictions); {
+	case v.Algorithm == meta.AlgorithmInstant || (!rebuild && builds == 0):
+		e.ExtraDiskBytes = diskBytes(0)
+	case !hasTableSize(tm):
+		// テーブルサイズが不明な場合は追加容量も不明とする
+	case rebuild:
+		e.ExtraDiskBytes = diskBytes(tm.DataLength + tm.IndexLength)
+	default:
+		e.ExtraDiskBytes = diskBytes(int64(builds) * averageIndexSize(tm))
+	}
+
+	if v.Algorithm == meta.AlgorithmInstant {
+		e.Replication = "negligible"
+	} else {
+		e.Replication = "Replicas apply the ALTER as a single event after the source finishes — lag " + lagLabel(e.Duration)
+	}
+
+	parents, children := foreignKeyCounts(t)
+	if parents+children == 0 {
+		e.ForeignKeys = "none"
+	} else {
+		e.ForeignKeys = fmt.Sprintf("Supported — %d related table(s) take a shared metadata lock during the ALTER", parents+children)
+	}
+	return e
+}
+
+func estimateGhost(t Target, settings predictor.DurationSettings) MethodEstimate {
+	plan := Ghost(t, Connection{})
+	e := MethodEstimate{
+		Method:         ToolGhost,
+		Duration:       toolDuration(t.TableMeta, settings),
+		InitialLock:    "none (reads the binlog, no triggers)",
+		FinalLock:      "brief (cut-over LOCK TABLES, --cut-over-lock-timeout-seconds=3 per attempt)",
+		ExtraDiskBytes: toolDiskBytes(t.TableMeta),
+		Replication:    "Row copy replicates in small chunks as row events — throttled by --max-lag-millis=1500",
+		ForeignKeys:    "none",
+		Compatible:     plan.Compatible,
+		Blockers:       plan.Blockers,
+	}
+	if parents, children := foreignKeyCounts(t); parents+children > 0 {
+		e.ForeignKeys = "Not supported — the table has foreign keys or is referenced by them"
+	}
+	return e
+}
+
+func estimatePTOSC(t Target, settings predictor.DurationSettings) MethodEstimate {
+	plan := PTOSC(t, Connection{})
+	e := MethodEstimate{
+		Method:         ToolPTOSC,
+		Duration:       toolDuration(t.TableMeta, settings),
+		InitialLock:    "brief (CREATE TRIGGER for INSERT/UPDATE/DELETE)",
+		FinalLock:      "brief (atomic RENAME TABLE)",
+		ExtraDiskBytes: toolDiskBytes(t.TableMeta),
+		Replication:    "Row copy and trigger writes replicate in chunks — throttled by --max-lag=1",
+		ForeignKeys:    "none",
+		Compatible:     plan.Compatible,
+		Blockers:       plan.Blockers,
+	}
+
+	method, _, _ := foreignKeyMethod(t)
+	parents, _ := foreignKeyCounts(t)
+	switch method {
+	case "drop_swap":
+		e.FinalLock = "brief (DROP TABLE + RENAME TABLE, the table briefly does not exist)"
+		e.ForeignKeys = "Supported via --alter-foreign-keys-method=drop_swap — foreign_key_checks is disabled during the swap"
+	case "rebuild_constraints":
+		e.ForeignKeys = "Supported via --alter-foreign-keys-method=rebuild_constraints — child tables are briefly locked while their foreign keys are rebuilt"
+	case "auto":
+		e.ForeignKeys = "Supported via --alter-foreign-keys-method=auto — child table sizes are unknown"
+	default:
+		if parents > 0 {
+			e.ForeignKeys = "Supported — the table's own constraint names get an underscore prefix"
+		}
+	}
+	return e
+}
+
+// recommend は推定コストから実行方法を1つ選び、理由を返す。
+func recommend(v predictor.StatementVerdict, native, ghost, ptosc MethodEstimate) (Tool, []string) {
+	blocking := native.DMLBlocking != ""
+	limit := float64(nativeLagMaxSec)
+	if blocking {
+		limit = nativeBlockingMaxSec
+	}
+	if v.Algorithm == meta.AlgorithmInstant {
+		return ToolNative, []string{"INSTANT — only the table metadata changes"}
+	}
+	if native.Duration != nil && native.Duration.MaxSec <= limit {
+		if blocking {
+			return ToolNative, []string{fmt.Sprintf("DML is blocked for up to %s — short enough to run natively", predictor.FormatDuration(native.Duration.MaxSec))}
+		}
+		return ToolNative, []string{fmt.Sprintf("Native online DDL does not block DML and finishes in up to %s — no shadow table or extra tooling needed", predictor.FormatDuration(native.Duration.MaxSec))}
+	}
+
+	var reasons []string
+	switch {
+	case native.Duration == nil && blocking:
+		reasons = append(reasons, "Native ALTER blocks DML ("+native.DMLBlocking+") and its duration is unknown (no table statistics)")
+	case native.Duration == nil:
+		reasons = append(reasons, "Native rebuild duration is unknown (no table statistics) — replicas lag for as long as it runs")
+	case blocking:
+		reasons = append(reasons, fmt.Sprintf("Native ALTER blocks DML for up to %s", predictor.FormatDuration(native.Duration.MaxSec)))
+	default:
+		reasons = append(reasons, fmt.Sprintf("Native rebuild takes up to %s and replicas lag by about as long", predictor.FormatDuration(native.Duration.MaxSec)))
+	}
+
+	if ghost.Compatible {
+		return ToolGhost, append(reasons, "gh-ost is compatible — no triggers on the table, throttles on replica lag and the cut-over lock is bounded")
+	}
+	reasons = append(reasons, "gh-ost is not compatible: "+ghost.Blockers[0])
+	if ptosc.Compatible {
+		reasons = append(reasons, "pt-online-schema-change is compatible — DML keeps running through triggers")
+		if ptosc.ForeignKeys != "none" {
+			reasons = append(reasons, ptosc.ForeignKeys)
+		}
+		return ToolPTOSC, reasons
+	}
+	reasons = append(reasons, "pt-online-schema-change is not compatible: "+ptosc.Blockers[0])
+	if blocking {
+		reasons = append(reasons, "No online schema change tool can run this statement — run it natively in a maintenance window")
+	} else {
+		reasons = append(reasons, "No online schema change tool can run this statement — run it natively and watch replica lag")
+	}
+	return ToolNative, reasons
+}
+
+// toolDuration はオンラインスキーマ変更ツールでテーブル全体をコピーする時間を見積もる。
+func toolDuration(tm *meta.TableMeta, settings predictor.DurationSettings) *predictor.DurationEstimate {
+	base := predictor.EstimateDuration(settings, predictor.Prediction{Algorithm: meta.AlgorithmCopy, TableRebuild: true}, tm)
+	if base == nil {
+		return nil
+	}
+	return durationRange(base.MinSec*toolCopyFactor, base.MaxSec*toolCopyFactor)
+}
+
+// toolDiskBytes はシャドーテーブルと、コピーした行の binlog（ROW 形式）の容量を見積もる。
+// テーブルサイズが不明な場合は nil を返す。
+func toolDiskBytes(tm *meta.TableMeta) *int64 {
+	if !hasTableSize(tm) {
+		return nil
+	}
+	return diskBytes(tm.DataLength + tm.IndexLength + tm.DataLength)
+}
+
+// hasTableSize はテーブル統計（データサイズまたは行数）があるかを返す。
+func hasTableSize(tm *meta.TableMeta) bool {
+	return tm != nil && (tm.DataLength != 0 || tm.RowCount != 0)
+}
+
+func diskBytes(n int64) *int64 {
+	return &n
+}
+
+// sumDurations はインデックス作成など再構築を伴わないアクションの推定時間を合計する。
+// 推定できないアクションがある場合は nil を返す。
+func sumDurations(predictions []predictor.Prediction) *predictor.DurationEstimate {
+	var minSec, maxSec float64
+	for _, p := range predictions {
+		if p.EstimatedDuration == nil {
+			return nil
+		}
+		minSec += p.EstimatedDuration.MinSec
+		maxSec += p.EstimatedDuration.MaxSec
+	}
+	return durationRange(minSec, maxSec)
+}
+
+func durationRange(minSec, maxSec float64) *predictor.DurationEstimate {
+	if maxSec == 0 {
+		return &predictor.DurationEstimate{Label: "~0s (metadata only)"}
+	}
+	return &predictor.DurationEstimate{
+		MinSec: minSec,
+		MaxSec: maxSec,
+		Label:  predictor.FormatDuration(minSec) + " - " + predictor.FormatDuration(maxSec),
+	}
+}
+
+func lagLabel(d *predictor.DurationEstimate) string {
+	if d == nil {
+		return "about as long as the ALTER runs"
+	}
+	return "up to " + predictor.FormatDuration(d.MaxSec)
+}
+
+// countIndexBuilds は文に含まれるインデックス作成の数を返す。
+func countIndexBuilds(predictions []predictor.Prediction) int {
+	n := 0
+	for _, p := range predictions {
+		switch p.ActionType {
+		case meta.ActionAddIndex, meta.ActionAddUniqueIndex, meta.ActionAddFulltextIndex, meta.ActionAddSpatialIndex:
+			n++
+		}
+	}
+	return n
+}
+
+// averageIndexSize は既存のセカンダリインデックスの平均サイズを返す。
+// セカンダリインデックスがない場合はデータサイズの 10% とする。
+func averageIndexSize(tm *meta.TableMeta) int64 {
+	n := 0
+	for _, idx := range tm.Indexes {
+		if !idx.IsPrimary && !strings.EqualFold(idx.Name, "PRIMARY") {
+			n++
+		}
+	}
+	if n == 0 || tm.IndexLength == 0 {
+		return tm.DataLength / 10
+	}
+	return tm.IndexLength / int64(n)
+}
+
+// foreignKeyCounts はテーブルが参照する親テーブルと、テーブルを参照する子テーブルの数を返す。
+func foreignKeyCounts(t Target) (parents, children int) {
+	if t.TableMeta != nil {
+		parents = len(t.TableMeta.ForeignKeys)
+	}
+	return parents, len(directChildren(t))
+}
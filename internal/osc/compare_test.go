@@ -0,0 +1,145 @@
+package osc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+func copyVerdict() predictor.StatementVerdict {
+	return predictor.StatementVerdict{Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared, TableRebuild: true}
+}
+
+func largeOrdersMeta() *meta.TableMeta {
+	tm := ordersMeta()
+	tm.RowCount = 10_000_000
+	tm.DataLength = 4 * predictor.GB
+	tm.IndexLength = 1 * predictor.GB
+	return tm
+}
+
+func findMethod(t *testing.T, c *Comparison, tool Tool) MethodEstimate {
+	t.Helper()
+	for _, m := range c.Methods {
+		if m.Method == tool {
+			return m
+		}
+	}
+	t.Fatalf("%s の見積もりがあること", tool)
+	return MethodEstimate{}
+}
+
+func TestNeedsComparison(t *testing.T) {
+	tests := []struct {
+		name    string
+		verdict predictor.StatementVerdict
+		want    bool
+	}{
+		{"COPY", copyVerdict(), true},
+		{"INPLACE 再構築", predictor.StatementVerdict{Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone, TableRebuild: true}, true},
+		{"INPLACE インデックス作成", predictor.StatementVerdict{Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone}, false},
+		{"INSTANT", predictor.StatementVerdict{Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsComparison(tt.verdict); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareLargeCopyRecommendsGhost(t *testing.T) {
+	target := Target{Schema: "mydb", Operation: modifyNote(), TableMeta: largeOrdersMeta(), Variables: rowVariables()}
+	c := Compare(target, nil, copyVerdict(), predictor.DefaultDurationSettings())
+
+	if c.Recommended != ToolGhost {
+		t.Errorf("大きなテーブルの COPY は gh-ost を推奨すること: got %s %v", c.Recommended, c.Reasons)
+	}
+	native := findMethod(t, c, ToolNative)
+	if native.DMLBlocking == "" || native.Duration == nil || native.ExtraDiskBytes == nil || *native.ExtraDiskBytes != 5*predictor.GB {
+		t.Errorf("ネイティブはDMLをブロックし、テーブルサイズ分のディスクを使うこと: got %+v", native)
+	}
+	ghost := findMethod(t, c, ToolGhost)
+	if ghost.Duration == nil || ghost.Duration.MaxSec <= native.Duration.MaxSec {
+		t.Errorf("ツールの行コピーはネイティブより時間がかかること: got %+v / %+v", ghost.Duration, native.Duration)
+	}
+	if ghost.ExtraDiskBytes == nil || *ghost.ExtraDiskBytes != 9*predictor.GB {
+		t.Errorf("ツールはシャドーテーブルと binlog の容量を使うこと: got %v", ghost.ExtraDiskBytes)
+	}
+}
+
+func TestCompareSmallTableRecommendsNative(t *testing.T) {
+	tm := ordersMeta()
+	tm.RowCount = 1000
+	tm.DataLength = 64 * predictor.KB
+	c := Compare(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: tm}, nil, copyVerdict(), predictor.DefaultDurationSettings())
+	if c.Recommended != ToolNative || !strings.Contains(c.Reasons[0], "short enough") {
+		t.Errorf("小さなテーブルはネイティブを推奨すること: got %s %v", c.Recommended, c.Reasons)
+	}
+}
+
+func TestCompareForeignKeysRecommendsPTOSC(t *testing.T) {
+	tm := largeOrdersMeta()
+	tm.ReferencedBy = []meta.ForeignKeyMeta{{ConstraintName: "fk_order", SourceSchema: "mydb", SourceTable: "order_items"}}
+	target := Target{Schema: "mydb", Operation: modifyNote(), TableMeta: tm, Variables: rowVariables(), FKGraph: childGraph(30_000_000)}
+	c := Compare(target, nil, copyVerdict(), predictor.DefaultDurationSettings())
+
+	if c.Recommended != ToolPTOSC {
+		t.Errorf("外部キーのあるテーブルは pt-online-schema-change を推奨すること: got %s %v", c.Recommended, c.Reasons)
+	}
+	if got := findMethod(t, c, ToolGhost); got.Compatible || !strings.HasPrefix(got.ForeignKeys, "Not supported") {
+		t.Errorf("gh-ost は外部キーに対応しないこと: got %+v", got)
+	}
+	if got := findMethod(t, c, ToolPTOSC); !strings.Contains(got.ForeignKeys, "drop_swap") || !strings.Contains(got.FinalLock, "does not exist") {
+		t.Errorf("pt-online-schema-change は drop_swap で外部キーに対応すること: got %+v", got)
+	}
+}
+
+func TestCompareNoTableStatistics(t *testing.T) {
+	// スキーマダンプのみなどテーブル統計がない場合、追加ディスクは 0 ではなく不明とする
+	c := Compare(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: ordersMeta()}, nil, copyVerdict(), predictor.DefaultDurationSettings())
+	for _, m := range c.Methods {
+		if m.ExtraDiskBytes != nil {
+			t.Errorf("%s の追加ディスクが不明 (nil) であること: got %d", m.Method, *m.ExtraDiskBytes)
+		}
+	}
+
+	v := predictor.StatementVerdict{Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone}
+	native := findMethod(t, Compare(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: ordersMeta()}, nil, v, predictor.DefaultDurationSettings()), ToolNative)
+	if native.ExtraDiskBytes == nil || *native.ExtraDiskBytes != 0 {
+		t.Errorf("INSTANT は統計がなくても追加ディスクが 0 であること: got %v", native.ExtraDiskBytes)
+	}
+}
+
+func TestCompareNoToolCompatible(t *testing.T) {
+	tm := largeOrdersMeta()
+	tm.Indexes = nil
+	c := Compare(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: tm}, nil, copyVerdict(), predictor.DefaultDurationSettings())
+	if c.Recommended != ToolNative || !strings.Contains(strings.Join(c.Reasons, "\n"), "maintenance window") {
+		t.Errorf("互換なツールがない場合はメンテナンス時間でのネイティブ実行を推奨すること: got %s %v", c.Recommended, c.Reasons)
+	}
+}
+
+func TestCompareInplaceIndexBuild(t *testing.T) {
+	tm := largeOrdersMeta()
+	predictions := []predictor.Prediction{{
+		ActionType:        meta.ActionAddIndex,
+		EstimatedDuration: &predictor.DurationEstimate{MinSec: 30, MaxSec: 120},
+	}}
+	v := predictor.StatementVerdict{Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone}
+	c := Compare(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: tm}, predictions, v, predictor.DefaultDurationSettings())
+
+	native := findMethod(t, c, ToolNative)
+	if native.Duration.MaxSec != 120 || native.DMLBlocking != "" {
+		t.Errorf("インデックス作成はアクションの推定時間を使い、DMLをブロックしないこと: got %+v", native)
+	}
+	if native.ExtraDiskBytes == nil || *native.ExtraDiskBytes != tm.IndexLength {
+		t.Errorf("インデックス作成の追加ディスクは既存セカンダリインデックスの平均サイズであること: got %v", native.ExtraDiskBytes)
+	}
+	if c.Recommended != ToolNative {
+		t.Errorf("短時間でDMLをブロックしないネイティブを推奨すること: got %s %v", c.Recommended, c.Reasons)
+	}
+}
@@ -0,0 +1,101 @@
+package osc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// gh-ost のスロットリング・カットオーバーの推奨値。
+var ghostDefaultFlags = []string{
+	"--chunk-size=1000",
+	"--dml-batch-size=10",
+	"--max-load=Threads_running=25",
+	"--critical-load=Threads_running=1000",
+	"--max-lag-millis=1500",
+	"--cut-over=default",
+	"--cut-over-lock-timeout-seconds=3",
+	"--default-retries=120",
+	"--exact-rowcount",
+	"--concurrent-rowcount",
+	"--initially-drop-ghost-table",
+	"--verbose",
+}
+
+// Ghost は gh-ost の実行コマンドを生成し、gh-ost の必須要件を確認する。
+// 必須要件: 共有できる PRIMARY KEY / UNIQUE KEY があること、外部キーがないこと、
+// トリガーがないこと、binlog_format=ROW かつ binlog_row_image=FULL であること。
+func Ghost(t Target, conn Connection) Plan {
+	op := t.Operation
+	database, databaseWarning := t.database()
+	args := []string{
+		"--host=" + conn.host(),
+		"--port=" + conn.port(),
+		"--user=" + shellQuote(conn.user()),
+		"--ask-pass",
+		"--database=" + shellQuote(database),
+		"--table=" + shellQuote(t.table()),
+		"--alter=" + shellQuote(alterBody(op)),
+	}
+	args = append(args, ghostDefaultFlags...)
+
+	plan := Plan{Tool: ToolGhost, Command: formatCommand(string(ToolGhost), args)}
+	plan.Blockers, plan.Warnings = checkGhost(t)
+	if databaseWarning != "" {
+		plan.Warnings = append(plan.Warnings, databaseWarning)
+	}
+	plan.Compatible = len(plan.Blockers) == 0
+	plan.Notes = []string{
+		"Dry run by default — review the output, then add --execute",
+		"gh-ost connects to a replica by default; add --allow-on-master to run against the primary",
+	}
+	return plan
+}
+
+// checkGhost は gh-ost で実行できない理由（blockers）と確認事項（warnings）を返す。
+func checkGhost(t Target) (blockers, warnings []string) {
+	op := t.Operation
+	if hasAction(op, meta.ActionRenameTable) {
+		blockers = append(blockers, "RENAME TABLE is not supported in --alter — rename the table in a separate statement")
+	}
+	if hasAction(op, meta.ActionAddForeignKey) {
+		blockers = append(blockers, "ADD FOREIGN KEY is not supported — gh-ost does not support foreign keys")
+	}
+
+	tm := t.TableMeta
+	if tm == nil {
+		warnings = append(warnings, "No table metadata — keys, foreign keys and triggers were not checked")
+	} else {
+		if tm.Engine != "" && !strings.EqualFold(tm.Engine, "InnoDB") {
+			blockers = append(blockers, fmt.Sprintf("Engine %s is not supported — gh-ost requires InnoDB", tm.Engine))
+		}
+		keys := sharedUniqueKeys(op, tm)
+		switch {
+		case len(keys) == 0:
+			blockers = append(blockers, "No PRIMARY KEY or UNIQUE KEY shared by the original and altered table")
+		case !hasNotNullKey(keys, tm):
+			warnings = append(warnings, fmt.Sprintf("Unique key %s has nullable columns — gh-ost requires --allow-nullable-unique-key and NULL rows may be lost", keys[0].Name))
+		}
+		if len(tm.ForeignKeys) > 0 {
+			blockers = append(blockers, fmt.Sprintf("Table has foreign keys (%s) — gh-ost does not support foreign keys", strings.Join(foreignKeyNames(tm.ForeignKeys, false), ", ")))
+		}
+		if len(tm.ReferencedBy) > 0 {
+			blockers = append(blockers, fmt.Sprintf("Table is referenced by foreign keys (%s) — gh-ost does not support foreign keys", strings.Join(foreignKeyNames(tm.ReferencedBy, true), ", ")))
+		}
+		if len(tm.Triggers) > 0 {
+			blockers = append(blockers, fmt.Sprintf("Table has triggers (%s) — gh-ost does not support triggers", strings.Join(tm.Triggers, ", ")))
+		}
+	}
+
+	vars := t.Variables
+	if vars == nil || vars.BinlogFormat == "" {
+		warnings = append(warnings, "binlog_format is unknown — gh-ost requires binlog_format=ROW")
+	} else if !strings.EqualFold(vars.BinlogFormat, "ROW") {
+		blockers = append(blockers, fmt.Sprintf("binlog_format=%s — gh-ost requires ROW (or --switch-to-rbr on a replica)", vars.BinlogFormat))
+	}
+	if vars != nil && vars.BinlogRowImage != "" && !strings.EqualFold(vars.BinlogRowImage, "FULL") {
+		blockers = append(blockers, fmt.Sprintf("binlog_row_image=%s — gh-ost requires FULL", vars.BinlogRowImage))
+	}
+	return blockers, warnings
+}
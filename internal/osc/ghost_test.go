@@ -0,0 +1,199 @@
+package osc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+func ordersMeta() *meta.TableMeta {
+	return &meta.TableMeta{
+		Schema: "mydb",
+		Table:  "orders",
+		Engine: "InnoDB",
+		Columns: []meta.ColumnMeta{
+			{Name: "id", ColumnType: "bigint"},
+			{Name: "code", ColumnType: "varchar(32)", IsNullable: true},
+			{Name: "note", ColumnType: "varchar(100)", IsNullable: true},
+		},
+		Indexes: []meta.IndexMeta{
+			{Name: "PRIMARY", Columns: []string{"id"}, IsUnique: true, IsPrimary: true},
+			{Name: "uk_code", Columns: []string{"code"}, IsUnique: true},
+		},
+	}
+}
+
+func modifyNote() meta.AlterOperation {
+	return meta.AlterOperation{
+		Table: "orders",
+		Actions: []meta.AlterAction{
+			{Type: meta.ActionModifyColumn, SQL: "MODIFY COLUMN `note` TEXT", Detail: meta.ActionDetail{ColumnName: "note", ColumnType: "TEXT"}},
+		},
+	}
+}
+
+func rowVariables() *meta.ServerVariables {
+	return &meta.ServerVariables{BinlogFormat: "ROW", BinlogRowImage: "FULL"}
+}
+
+func TestNeedsOnlineTool(t *testing.T) {
+	tests := []struct {
+		name    string
+		verdict predictor.StatementVerdict
+		want    bool
+	}{
+		{"COPY", predictor.StatementVerdict{Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared}, true},
+		{"INPLACE/SHARED", predictor.StatementVerdict{Algorithm: meta.AlgorithmInplace, Lock: meta.LockShared}, true},
+		{"INPLACE/NONE", predictor.StatementVerdict{Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone}, false},
+		{"INSTANT", predictor.StatementVerdict{Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsOnlineTool(tt.verdict); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGhostCommand(t *testing.T) {
+	op := modifyNote()
+	op.Actions = append(op.Actions, meta.AlterAction{Type: meta.ActionAddColumn, SQL: "ADD COLUMN `memo` VARCHAR(10) DEFAULT 'n/a'"})
+	plan := Ghost(Target{Schema: "mydb", Operation: op, TableMeta: ordersMeta(), Variables: rowVariables()}, Connection{Host: "replica1", Port: 3307, User: "ghost"})
+
+	if !plan.Compatible || len(plan.Blockers) != 0 {
+		t.Errorf("要件を満たすテーブルは互換であること: got %v", plan.Blockers)
+	}
+	for _, want := range []string{
+		"--host=replica1",
+		"--port=3307",
+		"--database='mydb'",
+		"--table='orders'",
+		`--alter='MODIFY COLUMN ` + "`note`" + ` TEXT, ADD COLUMN ` + "`memo`" + ` VARCHAR(10) DEFAULT '"'"'n/a'"'"''`,
+		"--max-load=Threads_running=25",
+	} {
+		if !strings.Contains(plan.Command, want) {
+			t.Errorf("コマンドに %s が含まれること:\n%s", want, plan.Command)
+		}
+	}
+	if strings.Contains(plan.Command, "ALTER TABLE") || strings.Contains(plan.Command, "--execute") {
+		t.Errorf("ALTER TABLE と --execute を含まないこと:\n%s", plan.Command)
+	}
+}
+
+func TestGhostConnectionPlaceholder(t *testing.T) {
+	plan := Ghost(Target{Schema: "mydb", Operation: modifyNote()}, Connection{})
+	if !strings.Contains(plan.Command, "--host=<host>") || !strings.Contains(plan.Command, "--port=3306") || !strings.Contains(plan.Command, "--user='<user>'") {
+		t.Errorf("接続先が未指定の場合はプレースホルダになること:\n%s", plan.Command)
+	}
+	if !plan.Compatible {
+		t.Errorf("メタデータがない場合はブロッカーにしないこと: got %v", plan.Blockers)
+	}
+}
+
+func TestGhostDatabaseFallback(t *testing.T) {
+	// ALTER 文と --database でスキーマが指定されていない場合はテーブル定義のスキーマを使う
+	plan := Ghost(Target{Operation: modifyNote(), TableMeta: ordersMeta(), Variables: rowVariables()}, Connection{})
+	if !strings.Contains(plan.Command, "--database='mydb'") || len(plan.Warnings) != 0 {
+		t.Errorf("テーブル定義のスキーマを使うこと: %v\n%s", plan.Warnings, plan.Command)
+	}
+
+	plan = Ghost(Target{Operation: modifyNote()}, Connection{})
+	if !strings.Contains(plan.Command, "--database='<database>'") || strings.Contains(plan.Command, "--database=''") {
+		t.Errorf("スキーマが分からない場合はプレースホルダになること:\n%s", plan.Command)
+	}
+	if !containsSubstring(plan.Warnings, "<database>") {
+		t.Errorf("プレースホルダの置き換えを警告すること: got %v", plan.Warnings)
+	}
+}
+
+func TestGhostMixedCaseTable(t *testing.T) {
+	// lower_case_table_names=0 のサーバーでも対象を特定できるよう、ALTER 文の大文字小文字を保つ
+	op := modifyNote()
+	op.Schema, op.Table, op.OriginalSchema, op.OriginalTable = "mydb", "orders", "MyDB", "Orders"
+	plan := Ghost(Target{Schema: "mydb", Operation: op, TableMeta: ordersMeta(), Variables: rowVariables()}, Connection{})
+	if !strings.Contains(plan.Command, "--database='MyDB'") || !strings.Contains(plan.Command, "--table='Orders'") {
+		t.Errorf("元の大文字小文字のデータベース名・テーブル名を使うこと:\n%s", plan.Command)
+	}
+}
+
+func containsSubstring(list []string, sub string) bool {
+	for _, s := range list {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGhostBlockers(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Target)
+		want   string
+	}{
+		{"外部キー", func(tg *Target) {
+			tg.TableMeta.ForeignKeys = []meta.ForeignKeyMeta{{ConstraintName: "fk_user", ReferencedTable: "users"}}
+		}, "foreign keys (users.fk_user)"},
+		{"被参照", func(tg *Target) {
+			tg.TableMeta.ReferencedBy = []meta.ForeignKeyMeta{{ConstraintName: "fk_order", SourceTable: "order_items"}}
+		}, "referenced by foreign keys (order_items.fk_order)"},
+		{"トリガー", func(tg *Target) {
+			tg.TableMeta.Triggers = []string{"trg_orders_audit"}
+		}, "triggers (trg_orders_audit)"},
+		{"binlog_format", func(tg *Target) {
+			tg.Variables.BinlogFormat = "MIXED"
+		}, "binlog_format=MIXED"},
+		{"binlog_row_image", func(tg *Target) {
+			tg.Variables.BinlogRowImage = "MINIMAL"
+		}, "binlog_row_image=MINIMAL"},
+		{"ユニークキーなし", func(tg *Target) {
+			tg.TableMeta.Indexes = nil
+		}, "No PRIMARY KEY or UNIQUE KEY"},
+		{"キーの削除", func(tg *Target) {
+			tg.Operation.Actions = append(tg.Operation.Actions,
+				meta.AlterAction{Type: meta.ActionDropPrimaryKey, SQL: "DROP PRIMARY KEY"},
+				meta.AlterAction{Type: meta.ActionDropColumn, SQL: "DROP COLUMN `code`", Detail: meta.ActionDetail{ColumnName: "code"}})
+		}, "No PRIMARY KEY or UNIQUE KEY"},
+		{"外部キーの追加", func(tg *Target) {
+			tg.Operation.Actions = append(tg.Operation.Actions, meta.AlterAction{Type: meta.ActionAddForeignKey, SQL: "ADD FOREIGN KEY (user_id) REFERENCES users (id)"})
+		}, "ADD FOREIGN KEY"},
+		{"非InnoDB", func(tg *Target) {
+			tg.TableMeta.Engine = "MyISAM"
+		}, "Engine MyISAM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := Target{Schema: "mydb", Operation: modifyNote(), TableMeta: ordersMeta(), Variables: rowVariables()}
+			tt.modify(&target)
+			plan := Ghost(target, Connection{})
+			if plan.Compatible {
+				t.Fatalf("互換でないこと")
+			}
+			if !strings.Contains(strings.Join(plan.Blockers, "\n"), tt.want) {
+				t.Errorf("%q を含むブロッカーになること: got %v", tt.want, plan.Blockers)
+			}
+		})
+	}
+}
+
+func TestGhostNullableUniqueKey(t *testing.T) {
+	target := Target{Schema: "mydb", Operation: modifyNote(), TableMeta: ordersMeta(), Variables: rowVariables()}
+	target.Operation.Actions = append(target.Operation.Actions, meta.AlterAction{Type: meta.ActionDropPrimaryKey, SQL: "DROP PRIMARY KEY"})
+	plan := Ghost(target, Connection{})
+	if !plan.Compatible {
+		t.Errorf("NULL を許可するユニークキーはブロッカーにしないこと: got %v", plan.Blockers)
+	}
+	if !strings.Contains(strings.Join(plan.Warnings, "\n"), "--allow-nullable-unique-key") {
+		t.Errorf("NULL を許可するユニークキーを警告すること: got %v", plan.Warnings)
+	}
+}
+
+func TestGhostUnknownVariables(t *testing.T) {
+	plan := Ghost(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: ordersMeta()}, Connection{})
+	if !plan.Compatible || !strings.Contains(strings.Join(plan.Warnings, "\n"), "binlog_format is unknown") {
+		t.Errorf("サーバー変数が不明な場合は警告にすること: got %v %v", plan.Blockers, plan.Warnings)
+	}
+}
@@ -0,0 +1,219 @@
+// Package osc はオンラインスキーマ変更ツール（gh-ost など）の実行コマンドを生成し、
+// テーブルとサーバーがツールの要件を満たすかを確認する。
+package osc
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/fkresolver"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+// Tool はオンラインスキーマ変更ツールの種類を表す。
+type Tool string
+
+const (
+	// ToolGhost は gh-ost。
+	ToolGhost Tool = "gh-ost"
+	// ToolPTOSC は Percona Toolkit の pt-online-schema-change。
+	ToolPTOSC Tool = "pt-online-schema-change"
+)
+
+// Plan はオンラインスキーマ変更ツールによる実行計画を表す。
+type Plan struct {
+	Tool Tool `json:"tool"`
+	// Command はレビュー用のコマンド（--execute を付けると実行される）
+	Command string `json:"command"`
+	// Compatible は Blockers がなくツールで実行できること
+	Compatible bool `json:"compatible"`
+	// Blockers はツールの必須要件を満たさない理由
+	Blockers []string `json:"blockers,omitempty"`
+	// Warnings は実行前に確認が必要な事項
+	Warnings []string `json:"warnings,omitempty"`
+	// Notes は選んだオプションの理由や実行手順
+	Notes []string `json:"notes,omitempty"`
+}
+
+// Connection はコマンドに埋め込む接続先。空の値はプレースホルダになる。
+type Connection struct {
+	Host string
+	Port int
+	User string
+}
+
+// Target はコマンドを生成する対象の ALTER 文とテーブルの情報。
+type Target struct {
+	// Schema は対象データベース（ALTER 文で省略されている場合は接続先のデータベース）
+	Schema    string
+	Operation meta.AlterOperation
+	TableMeta *meta.TableMeta
+	// Variables はサーバー変数（取得できない場合は nil）
+	Variables *meta.ServerVariables
+	// MySQLVersion は対象サーバーのバージョン
+	MySQLVersion string
+	// FKGraph は外部キー依存グラフ（子テーブルの行数を含む）
+	FKGraph *fkresolver.FKGraph
+}
+
+// NeedsOnlineTool は文がテーブルコピーまたは DML をブロックするロックで実行され、
+// オンラインスキーマ変更ツールの使用を検討すべきかを返す。
+func NeedsOnlineTool(v predictor.StatementVerdict) bool {
+	return v.Algorithm == meta.AlgorithmCopy || v.Lock == meta.LockShared || v.Lock == meta.LockExclusive
+}
+
+// alterBody は ALTER TABLE <table> を除いた変更内容を返す。
+// 1つの句から複数のアクションが作られる場合は同じSQLが続くため重複を除く。
+func alterBody(op meta.AlterOperation) string {
+	clauses := make([]string, 0, len(op.Actions))
+	for _, a := range op.Actions {
+		if a.SQL == "" || (len(clauses) > 0 && clauses[len(clauses)-1] == a.SQL) {
+			continue
+		}
+		clauses = append(clauses, a.SQL)
+	}
+	return strings.Join(clauses, ", ")
+}
+
+// shellQuote は値をシングルクォートで囲む。バッククォートや $ を含む SQL をそのまま渡せる。
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// formatCommand はコマンドと引数を1行1引数の形式に整形する。
+func formatCommand(name string, args []string) string {
+	return name + " \\\n  " + strings.Join(args, " \\\n  ")
+}
+
+// database は対象データベースを返す。ALTER 文と --database で指定されていない場合はテーブル定義のスキーマを使い、
+// それも分からない場合はプレースホルダと、置き換えが必要であることの警告を返す。
+// ALTER 文で指定されたスキーマは元の大文字小文字で返す。
+func (t Target) database() (name, warning string) {
+	if t.Schema != "" {
+		if strings.EqualFold(t.Operation.OriginalSchema, t.Schema) {
+			return t.Operation.OriginalSchema, ""
+		}
+		return t.Schema, ""
+	}
+	if t.TableMeta != nil && t.TableMeta.Schema != "" {
+		return t.TableMeta.Schema, ""
+	}
+	return "<database>", "Database is unknown — replace <database> in the command, or qualify the table in the ALTER statement or pass --database"
+}
+
+// table は対象テーブル名を ALTER 文の元の大文字小文字で返す（lower_case_table_names=0 のサーバーで一致させるため）。
+func (t Target) table() string {
+	if t.Operation.OriginalTable != "" {
+		return t.Operation.OriginalTable
+	}
+	return t.Operation.Table
+}
+
+func (c Connection) host() string {
+	if c.Host == "" {
+		return "<host>"
+	}
+	return c.Host
+}
+
+func (c Connection) port() string {
+	if c.Port == 0 {
+		return "3306"
+	}
+	return strconv.Itoa(c.Port)
+}
+
+func (c Connection) user() string {
+	if c.User == "" {
+		return "<user>"
+	}
+	return c.User
+}
+
+// hasAction は文に指定した種類のアクションが含まれるかを返す。
+func hasAction(op meta.AlterOperation, t meta.AlterActionType) bool {
+	for _, a := range op.Actions {
+		if a.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// foreignKeyNames は外部キーを "テーブル.制約名" の形式で返す。
+func foreignKeyNames(fks []meta.ForeignKeyMeta, useSource bool) []string {
+	names := make([]string, 0, len(fks))
+	for _, fk := range fks {
+		table := fk.ReferencedTable
+		if useSource {
+			table = fk.SourceTable
+		}
+		names = append(names, table+"."+fk.ConstraintName)
+	}
+	return names
+}
+
+// sharedUniqueKeys は ALTER の前後で残る PRIMARY KEY / UNIQUE KEY を返す（PRIMARY KEY を先頭にする）。
+// ALTER で削除されるインデックスや、削除されるカラムを含むインデックスは除く。
+func sharedUniqueKeys(op meta.AlterOperation, tm *meta.TableMeta) []meta.IndexMeta {
+	droppedIndexes := make(map[string]bool)
+	droppedColumns := make(map[string]bool)
+	for _, a := range op.Actions {
+		switch a.Type {
+		case meta.ActionDropPrimaryKey:
+			droppedIndexes["primary"] = true
+		case meta.ActionDropIndex:
+			droppedIndexes[strings.ToLower(a.Detail.IndexName)] = true
+		case meta.ActionDropColumn:
+			droppedColumns[strings.ToLower(a.Detail.ColumnName)] = true
+		}
+	}
+
+	var keys []meta.IndexMeta
+	for _, idx := range tm.Indexes {
+		// 式を含むキーはカラムで行を特定できないため使わない
+		if (!idx.IsUnique && !idx.IsPrimary) || idx.HasExpression {
+			continue
+		}
+		if droppedIndexes[strings.ToLower(idx.Name)] {
+			continue
+		}
+		dropped := false
+		for _, col := range idx.Columns {
+			if droppedColumns[strings.ToLower(col)] {
+				dropped = true
+			}
+		}
+		if dropped {
+			continue
+		}
+		if idx.IsPrimary {
+			keys = append([]meta.IndexMeta{idx}, keys...)
+		} else {
+			keys = append(keys, idx)
+		}
+	}
+	return keys
+}
+
+// hasNotNullKey はすべてのカラムが NOT NULL のキーがあるかを返す。
+func hasNotNullKey(keys []meta.IndexMeta, tm *meta.TableMeta) bool {
+	for _, idx := range keys {
+		if idx.IsPrimary {
+			return true
+		}
+		nullable := false
+		for _, name := range idx.Columns {
+			for _, col := range tm.Columns {
+				if strings.EqualFold(col.Name, name) && col.IsNullable {
+					nullable = true
+				}
+			}
+		}
+		if !nullable {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package osc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/fkresolver"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+// rebuildConstraintsMaxRows は --alter-foreign-keys-method=rebuild_constraints を選ぶ子テーブルの行数の上限。
+// rebuild_constraints は子テーブルを ALTER TABLE で再構築するため、これより大きい子テーブルがある場合は drop_swap を選ぶ。
+const rebuildConstraintsMaxRows int64 = 1000000
+
+// preserveTriggersVersion は既存トリガーのあるテーブルに pt-online-schema-change がトリガーを作成できる最小バージョン
+// （同じタイミング・イベントの複数トリガー）。
+const preserveTriggersVersion = "5.7.2"
+
+// pt-online-schema-change のスロットリングの推奨値。
+var ptoscDefaultFlags = []string{
+	"--chunk-time=0.5",
+	"--max-load=Threads_running=25",
+	"--critical-load=Threads_running=50",
+	"--max-lag=1",
+	"--progress=time,30",
+	"--print",
+	"--dry-run",
+}
+
+// PTOSC は pt-online-schema-change の実行コマンドを生成し、必須要件を確認する。
+// 子テーブルがある場合は子テーブルの行数から --alter-foreign-keys-method を選ぶ。
+func PTOSC(t Target, conn Connection) Plan {
+	op := t.Operation
+	database, databaseWarning := t.database()
+	dsn := fmt.Sprintf("h=%s,P=%s,u=%s,D=%s,t=%s", conn.host(), conn.port(), conn.user(), database, t.table())
+	args := []string{
+		"--alter=" + shellQuote(alterBody(op)),
+		"--ask-pass",
+	}
+
+	plan := Plan{Tool: ToolPTOSC}
+	method, reason, warning := foreignKeyMethod(t)
+	if method != "" {
+		args = append(args, "--alter-foreign-keys-method="+method)
+		plan.Notes = append(plan.Notes, reason)
+		if warning != "" {
+			plan.Warnings = append(plan.Warnings, warning)
+		}
+	}
+
+	blockers, warnings, preserveTriggers := checkPTOSC(t)
+	if preserveTriggers {
+		args = append(args, "--preserve-triggers")
+	}
+	args = append(args, ptoscDefaultFlags...)
+	args = append(args, shellQuote(dsn))
+
+	plan.Command = formatCommand(string(ToolPTOSC), args)
+	plan.Blockers = blockers
+	plan.Warnings = append(plan.Warnings, warnings...)
+	if databaseWarning != "" {
+		plan.Warnings = append(plan.Warnings, databaseWarning)
+	}
+	plan.Compatible = len(plan.Blockers) == 0
+	plan.Notes = append(plan.Notes, "--dry-run creates and alters the new table without copying rows — replace it with --execute to run")
+	return plan
+}
+
+// checkPTOSC は pt-online-schema-change で実行できない理由と確認事項を返す。
+// preserveTriggers は既存のトリガーを新しいテーブルに引き継ぐ必要があること。
+func checkPTOSC(t Target) (blockers, warnings []string, preserveTriggers bool) {
+	op := t.Operation
+	if hasAction(op, meta.ActionRenameTable) {
+		blockers = append(blockers, "RENAME TABLE is not supported in --alter — rename the table in a separate statement")
+	}
+	if hasAction(op, meta.ActionAddForeignKey) {
+		warnings = append(warnings, "ADD FOREIGN KEY — pt-online-schema-change prefixes the new constraint name with an underscore")
+	}
+
+	tm := t.TableMeta
+	if tm == nil {
+		warnings = append(warnings, "No table metadata — keys, foreign keys and triggers were not checked")
+		return blockers, warnings, false
+	}
+	if len(sharedUniqueKeys(op, tm)) == 0 {
+		blockers = append(blockers, "No PRIMARY KEY or UNIQUE KEY shared by the original and altered table — pt-online-schema-change needs one for its DELETE trigger")
+	}
+	if len(tm.Triggers) > 0 {
+		triggers := strings.Join(tm.Triggers, ", ")
+		v, err := predictor.ParseVersion(t.MySQLVersion)
+		minimum, _ := predictor.ParseVersion(preserveTriggersVersion)
+		switch {
+		case err != nil || v.IsZero():
+			preserveTriggers = true
+			warnings = append(warnings, fmt.Sprintf("Table has triggers (%s) — --preserve-triggers requires MySQL %s+; older servers cannot add pt-online-schema-change's triggers", triggers, preserveTriggersVersion))
+		case v.Compare(minimum) < 0:
+			blockers = append(blockers, fmt.Sprintf("Table has triggers (%s) — MySQL %s cannot add pt-online-schema-change's triggers (requires %s+)", triggers, v, preserveTriggersVersion))
+		default:
+			preserveTriggers = true
+			warnings = append(warnings, fmt.Sprintf("Table has triggers (%s) — --preserve-triggers copies them to the new table; check they tolerate the row copy", triggers))
+		}
+	}
+	return blockers, warnings, preserveTriggers
+}
+
+// foreignKeyMethod は子テーブルの行数から --alter-foreign-keys-method を選び、その理由と注意点を返す。
+// 子テーブルがない場合は空文字を返す。
+func foreignKeyMethod(t Target) (method, reason, warning string) {
+	children := directChildren(t)
+	if len(children) == 0 {
+		return "", "", ""
+	}
+
+	var unknown []string
+	var largest fkresolver.FKRelation
+	for _, c := range children {
+		if c.RowCount == 0 && c.DataLength == 0 {
+			unknown = append(unknown, c.Table)
+			continue
+		}
+		if c.RowCount >= largest.RowCount {
+			largest = c
+		}
+	}
+
+	switch {
+	case largest.RowCount > rebuildConstraintsMaxRows:
+		return "drop_swap",
+			fmt.Sprintf("Child table %s has %d rows — rebuild_constraints would rebuild it with a blocking ALTER, so drop_swap replaces the table without touching child tables", largest.Table, largest.RowCount),
+			"drop_swap disables foreign_key_checks, drops the original table and renames the new one — the table briefly does not exist and the swap cannot be rolled back"
+	case len(unknown) > 0:
+		return "auto",
+			fmt.Sprintf("Child table size is unknown (%s) — auto uses rebuild_constraints when the child tables can be altered quickly, otherwise drop_swap", strings.Join(unknown, ", ")),
+			""
+	default:
+		return "rebuild_constraints",
+			fmt.Sprintf("Child tables are small (largest: %s, %d rows) — rebuild_constraints re-points their foreign keys with a quick ALTER and keeps the table swap atomic", largest.Table, largest.RowCount),
+			""
+	}
+}
+
+// directChildren はテーブルを直接参照する子テーブルを返す。
+// FK依存グラフがない場合は ReferencedBy から行数不明の子テーブルを作る。
+func directChildren(t Target) []fkresolver.FKRelation {
+	if t.FKGraph != nil {
+		var children []fkresolver.FKRelation
+		for _, c := range t.FKGraph.Children {
+			if c.Depth == 1 {
+				children = append(children, c)
+			}
+		}
+		return children
+	}
+	if t.TableMeta == nil {
+		return nil
+	}
+	children := make([]fkresolver.FKRelation, 0, len(t.TableMeta.ReferencedBy))
+	for _, fk := range t.TableMeta.ReferencedBy {
+		table := fk.SourceTable
+		if fk.SourceSchema != "" {
+			table = fk.SourceSchema + "." + fk.SourceTable
+		}
+		children = append(children, fkresolver.FKRelation{Table: table, Constraint: fk, Direction: fkresolver.FKDirectionChild, Depth: 1})
+	}
+	return children
+}
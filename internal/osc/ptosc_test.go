@@ -0,0 +1,132 @@
+package osc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/fkresolver"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func childGraph(rows ...int64) *fkresolver.FKGraph {
+	graph := &fkresolver.FKGraph{Root: "mydb.orders"}
+	for i, n := range rows {
+		graph.Children = append(graph.Children, fkresolver.FKRelation{
+			Table:     "mydb.child" + string(rune('a'+i)),
+			Direction: fkresolver.FKDirectionChild,
+			Depth:     1,
+			RowCount:  n,
+		})
+	}
+	return graph
+}
+
+func TestPTOSCCommand(t *testing.T) {
+	plan := PTOSC(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: ordersMeta(), MySQLVersion: "8.0.35"}, Connection{Host: "db1", Port: 3306, User: "osc"})
+	if !plan.Compatible {
+		t.Errorf("要件を満たすテーブルは互換であること: got %v", plan.Blockers)
+	}
+	for _, want := range []string{
+		"--alter='MODIFY COLUMN `note` TEXT'",
+		"--dry-run",
+		"'h=db1,P=3306,u=osc,D=mydb,t=orders'",
+	} {
+		if !strings.Contains(plan.Command, want) {
+			t.Errorf("コマンドに %s が含まれること:\n%s", want, plan.Command)
+		}
+	}
+	if strings.Contains(plan.Command, "--alter-foreign-keys-method") {
+		t.Errorf("子テーブルがない場合は --alter-foreign-keys-method を付けないこと:\n%s", plan.Command)
+	}
+}
+
+func TestPTOSCDatabaseFallback(t *testing.T) {
+	// ALTER 文と --database でスキーマが指定されていない場合はテーブル定義のスキーマを使う
+	plan := PTOSC(Target{Operation: modifyNote(), TableMeta: ordersMeta(), MySQLVersion: "8.0.35"}, Connection{Host: "db1", User: "osc"})
+	if !strings.Contains(plan.Command, "'h=db1,P=3306,u=osc,D=mydb,t=orders'") || containsSubstring(plan.Warnings, "<database>") {
+		t.Errorf("テーブル定義のスキーマを使うこと: %v\n%s", plan.Warnings, plan.Command)
+	}
+
+	plan = PTOSC(Target{Operation: modifyNote()}, Connection{Host: "db1", User: "osc"})
+	if !strings.Contains(plan.Command, "D=<database>,t=orders") || strings.Contains(plan.Command, "D=,") {
+		t.Errorf("スキーマが分からない場合はプレースホルダになること:\n%s", plan.Command)
+	}
+	if !containsSubstring(plan.Warnings, "<database>") {
+		t.Errorf("プレースホルダの置き換えを警告すること: got %v", plan.Warnings)
+	}
+}
+
+func TestPTOSCMixedCaseTable(t *testing.T) {
+	// lower_case_table_names=0 のサーバーでも対象を特定できるよう、ALTER 文の大文字小文字を保つ
+	op := modifyNote()
+	op.Schema, op.Table, op.OriginalSchema, op.OriginalTable = "mydb", "orders", "MyDB", "Orders"
+	plan := PTOSC(Target{Schema: "mydb", Operation: op, TableMeta: ordersMeta()}, Connection{Host: "db1", User: "osc"})
+	if !strings.Contains(plan.Command, "D=MyDB,t=Orders") {
+		t.Errorf("元の大文字小文字のデータベース名・テーブル名を使うこと:\n%s", plan.Command)
+	}
+}
+
+func TestPTOSCForeignKeyMethod(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph *fkresolver.FKGraph
+		want  string
+	}{
+		{"小さい子テーブル", childGraph(5000, 20000), "rebuild_constraints"},
+		{"大きい子テーブル", childGraph(5000, 30000000), "drop_swap"},
+		{"行数不明", childGraph(5000, 0), "auto"},
+		{"行数不明でも大きい子テーブルがある", childGraph(0, 30000000), "drop_swap"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PTOSC(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: ordersMeta(), FKGraph: tt.graph}, Connection{})
+			if !strings.Contains(plan.Command, "--alter-foreign-keys-method="+tt.want) {
+				t.Errorf("--alter-foreign-keys-method=%s になること:\n%s", tt.want, plan.Command)
+			}
+			if !strings.Contains(strings.Join(plan.Notes, "\n"), tt.want) {
+				t.Errorf("選んだ理由が notes に含まれること: got %v", plan.Notes)
+			}
+		})
+	}
+
+	plan := PTOSC(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: ordersMeta(), FKGraph: childGraph(30000000)}, Connection{})
+	if !strings.Contains(strings.Join(plan.Warnings, "\n"), "cannot be rolled back") {
+		t.Errorf("drop_swap の注意点を警告すること: got %v", plan.Warnings)
+	}
+}
+
+func TestPTOSCForeignKeyMethodWithoutGraph(t *testing.T) {
+	tm := ordersMeta()
+	tm.ReferencedBy = []meta.ForeignKeyMeta{{ConstraintName: "fk_order", SourceSchema: "mydb", SourceTable: "order_items"}}
+	plan := PTOSC(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: tm}, Connection{})
+	if !strings.Contains(plan.Command, "--alter-foreign-keys-method=auto") {
+		t.Errorf("FK依存グラフがない場合は ReferencedBy から auto を選ぶこと:\n%s", plan.Command)
+	}
+}
+
+func TestPTOSCTriggers(t *testing.T) {
+	tm := ordersMeta()
+	tm.Triggers = []string{"trg_orders_audit"}
+
+	plan := PTOSC(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: tm, MySQLVersion: "8.0.35"}, Connection{})
+	if !plan.Compatible || !strings.Contains(plan.Command, "--preserve-triggers") {
+		t.Errorf("5.7.2 以降は --preserve-triggers を付けること: got %v\n%s", plan.Blockers, plan.Command)
+	}
+	if !strings.Contains(strings.Join(plan.Warnings, "\n"), "trg_orders_audit") {
+		t.Errorf("既存のトリガーを警告すること: got %v", plan.Warnings)
+	}
+
+	plan = PTOSC(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: tm, MySQLVersion: "5.7.1"}, Connection{})
+	if plan.Compatible || !strings.Contains(strings.Join(plan.Blockers, "\n"), "MySQL 5.7.1 cannot add") {
+		t.Errorf("5.7.2 より前はブロッカーにすること: got %v", plan.Blockers)
+	}
+}
+
+func TestPTOSCNoUniqueKey(t *testing.T) {
+	tm := ordersMeta()
+	tm.Indexes = nil
+	plan := PTOSC(Target{Schema: "mydb", Operation: modifyNote(), TableMeta: tm}, Connection{})
+	if plan.Compatible || !strings.Contains(strings.Join(plan.Blockers, "\n"), "DELETE trigger") {
+		t.Errorf("ユニークキーがない場合はブロッカーにすること: got %v", plan.Blockers)
+	}
+}
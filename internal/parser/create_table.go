@@ -0,0 +1,485 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// spatialMarker はTiDBパーサーが解釈できないSPATIAL構文を置換した箇所に付与する目印。
+const spatialMarker = "__ddl_lock_analyzer_spatial__"
+
+var (
+	// spatialIndexRe は SPATIAL KEY/INDEX 定義にマッチする。
+	spatialIndexRe = regexp.MustCompile("(?i)\\bSPATIAL\\s+(?:KEY|INDEX)(\\s+(?:`[^`]+`|\\w+))?\\s*\\(([^()]*)\\)")
+	// spatialColumnRe は空間データ型のカラム定義にマッチする。
+	spatialColumnRe = regexp.MustCompile("(?i)([(,]\\s*(?:`[^`]+`|\\w+)\\s+)" +
+		"(geometrycollection|geomcollection|multilinestring|multipolygon|multipoint|linestring|polygon|geometry|point)\\b")
+	// sridRe は空間カラムのSRID属性（バージョンコメント形式を含む）にマッチする。
+	sridRe = regexp.MustCompile(`(?i)/\*!\d+\s+SRID\s+\d+\s*\*/|\bSRID\s+\d+\b`)
+)
+
+// ParseCreateTables はスキーマダンプ（mysqldump --no-data や SHOW CREATE TABLE の出力）から
+// CREATE TABLE 文を読み取り、テーブルメタデータのリストを返す。
+// USE 文でスキーマを切り替えられる。CREATE TABLE 以外の文は無視する。
+// 行数やデータサイズなどの統計情報はダンプに含まれないため0となる。
+func ParseCreateTables(sql string) ([]meta.TableMeta, error) {
+	p := parser.New()
+	stmts, _, err := p.Parse(maskSpatialSyntax(sql), "", "")
+	if err != nil {
+		return nil, fmt.Errorf("SQL parse error: %w", err)
+	}
+
+	var tables []meta.TableMeta
+	index := make(map[string]int)
+	currentSchema := ""
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.UseStmt:
+			currentSchema = s.DBName
+		case *ast.CreateTableStmt:
+			schema := s.Table.Schema.O
+			if schema == "" {
+				schema = currentSchema
+			}
+			var tm meta.TableMeta
+			if s.ReferTable != nil {
+				// CREATE TABLE ... LIKE は参照元テーブルの定義を複製する
+				refSchema := s.ReferTable.Schema.O
+				if refSchema == "" {
+					refSchema = currentSchema
+				}
+				i, ok := index[createTableKey(refSchema, s.ReferTable.Name.O)]
+				if !ok {
+					return nil, fmt.Errorf("table %s referenced by CREATE TABLE ... LIKE not found", s.ReferTable.Name.O)
+				}
+				tm = cloneTableDefinition(tables[i], schema, s.Table.Name.O)
+			} else {
+				tm = buildTableMeta(s, schema)
+			}
+
+			key := createTableKey(tm.Schema, tm.Table)
+			if i, ok := index[key]; ok {
+				tables[i] = tm
+				continue
+			}
+			index[key] = len(tables)
+			tables = append(tables, tm)
+		}
+	}
+
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no CREATE TABLE statements found")
+	}
+	meta.LinkReferencedBy(tables)
+	return tables, nil
+}
+
+// maskSpatialSyntax はTiDBパーサーが未対応の空間データ型と SPATIAL インデックスを
+// パース可能な構文に置き換え、元の情報をCOMMENTに目印として残す。
+func maskSpatialSyntax(sql string) string {
+	sql = sridRe.ReplaceAllString(sql, "")
+	sql = spatialIndexRe.ReplaceAllString(sql, "KEY$1 ($2) COMMENT '"+spatialMarker+"'")
+	return spatialColumnRe.ReplaceAllString(sql, "${1}blob COMMENT '"+spatialMarker+":$2'")
+}
+
+func buildTableMeta(stmt *ast.CreateTableStmt, schema string) meta.TableMeta {
+	tm := meta.TableMeta{
+		Schema: schema,
+		Table:  stmt.Table.Name.O,
+	}
+
+	tableCharset, tableCollation := "", ""
+	for _, opt := range stmt.Options {
+		switch opt.Tp {
+		case ast.TableOptionEngine:
+			tm.Engine = opt.StrValue
+		case ast.TableOptionCharset:
+			tableCharset = strings.ToLower(opt.StrValue)
+		case ast.TableOptionCollate:
+			tableCollation = strings.ToLower(opt.StrValue)
+		}
+	}
+	if tableCharset == "" && tableCollation != "" {
+		tableCharset = charsetOfCollation(tableCollation)
+	}
+
+	b := &indexBuilder{names: make(map[string]bool)}
+	for i, col := range stmt.Cols {
+		tm.Columns = append(tm.Columns, buildColumnMeta(col, i+1, tableCharset, tableCollation))
+		for _, opt := range col.Options {
+			switch opt.Tp {
+			case ast.ColumnOptionPrimaryKey:
+				b.add(meta.IndexMeta{Name: "PRIMARY", Columns: []string{col.Name.Name.O}, IsUnique: true, IsPrimary: true, IndexType: "BTREE"})
+			case ast.ColumnOptionUniqKey:
+				b.add(meta.IndexMeta{Columns: []string{col.Name.Name.O}, IsUnique: true, IndexType: "BTREE"})
+			case ast.ColumnOptionReference:
+				tm.ForeignKeys = append(tm.ForeignKeys, buildForeignKey(opt.Refer, "", []string{col.Name.Name.O}, schema, tm.Table))
+			}
+		}
+	}
+
+	for _, c := range stmt.Constraints {
+		cols := constraintColumns(c.Keys)
+		if c.Name == "" && len(c.Keys) > 0 && c.Keys[0].Column == nil && c.Tp != ast.ConstraintForeignKey {
+			// 先頭が式の名前のない関数インデックスは functional_index と命名される
+			c.Name = b.uniqueName("functional_index")
+		}
+		switch c.Tp {
+		case ast.ConstraintPrimaryKey:
+			b.add(meta.IndexMeta{Name: "PRIMARY", Columns: cols, IsUnique: true, IsPrimary: true, IndexType: "BTREE", HasExpression: hasExpressionKey(c.Keys)})
+		case ast.ConstraintKey, ast.ConstraintIndex:
+			b.add(meta.IndexMeta{Name: c.Name, Columns: cols, IndexType: indexTypeString(c.Option), HasExpression: hasExpressionKey(c.Keys)})
+		case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+			b.add(meta.IndexMeta{Name: c.Name, Columns: cols, IsUnique: true, IndexType: indexTypeString(c.Option), HasExpression: hasExpressionKey(c.Keys)})
+		case ast.ConstraintFulltext:
+			b.add(meta.IndexMeta{Name: c.Name, Columns: cols, IndexType: "FULLTEXT"})
+		case ast.ConstraintForeignKey:
+			tm.ForeignKeys = append(tm.ForeignKeys, buildForeignKey(c.Refer, c.Name, cols, schema, tm.Table))
+		}
+	}
+	tm.Indexes = b.indexes
+
+	// 制約名のない外部キーはMySQLと同様に <table>_ibfk_N と命名する
+	n := 0
+	for i := range tm.ForeignKeys {
+		if tm.ForeignKeys[i].ConstraintName == "" {
+			n++
+			tm.ForeignKeys[i].ConstraintName = fmt.Sprintf("%s_ibfk_%d", tm.Table, n)
+		}
+	}
+
+	applyColumnKeys(&tm)
+
+	if stmt.Partition != nil {
+		tm.IsPartitioned = true
+		tm.PartitionType = partitionTypeString(&stmt.Partition.PartitionMethod)
+	}
+	return tm
+}
+
+func buildColumnMeta(col *ast.ColumnDef, pos int, tableCharset, tableCollation string) meta.ColumnMeta {
+	cm := meta.ColumnMeta{
+		Name:       col.Name.Name.O,
+		OrdinalPos: pos,
+		ColumnType: createColumnTypeString(col),
+		IsNullable: true,
+	}
+
+	var extras []string
+	collation := ""
+	spatialType := ""
+	for _, opt := range col.Options {
+		switch opt.Tp {
+		case ast.ColumnOptionNotNull, ast.ColumnOptionPrimaryKey:
+			cm.IsNullable = false
+		case ast.ColumnOptionDefaultValue:
+			cm.DefaultValue, cm.HasDefault = createDefaultValueString(opt.Expr)
+		case ast.ColumnOptionAutoIncrement:
+			extras = append(extras, "auto_increment")
+		case ast.ColumnOptionOnUpdate:
+			extras = append(extras, "on update CURRENT_TIMESTAMP")
+		case ast.ColumnOptionGenerated:
+			if opt.Stored {
+				extras = append(extras, "STORED GENERATED")
+			} else {
+				extras = append(extras, "VIRTUAL GENERATED")
+			}
+		case ast.ColumnOptionCollate:
+			collation = strings.ToLower(opt.StrValue)
+		case ast.ColumnOptionComment:
+			if t, ok := spatialMarkerValue(opt.Expr); ok {
+				spatialType = t
+			}
+		}
+	}
+	if spatialType != "" {
+		cm.ColumnType = spatialType
+	}
+	cm.DataType = dataTypeOf(cm.ColumnType)
+	cm.Extra = strings.Join(extras, " ")
+
+	if spatialType == "" && isTextType(col) {
+		charset := strings.ToLower(col.Tp.GetCharset())
+		if collation == "" {
+			collation = strings.ToLower(col.Tp.GetCollate())
+		}
+		switch {
+		case charset == "" && collation != "":
+			charset = charsetOfCollation(collation)
+		case charset == "":
+			charset = tableCharset
+			collation = tableCollation
+		}
+		cm.CharacterSet = charset
+		cm.Collation = collation
+	}
+	return cm
+}
+
+// createColumnTypeString はカラム型を information_schema.COLUMNS.COLUMN_TYPE に近い形式で返す。
+// 文字セット・照合順序の指定は除き、ENUM/SET のリテラル以外を小文字にする。
+func createColumnTypeString(col *ast.ColumnDef) string {
+	s := columnTypeString(col)
+	for _, suffix := range []string{" CHARACTER SET ", " COLLATE "} {
+		if i := strings.Index(s, suffix); i >= 0 {
+			s = s[:i]
+		}
+	}
+	return lowerOutsideQuotes(s)
+}
+
+func lowerOutsideQuotes(s string) string {
+	var sb strings.Builder
+	inQuote := false
+	for _, r := range s {
+		if r == '\'' {
+			inQuote = !inQuote
+		}
+		if inQuote {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteString(strings.ToLower(string(r)))
+		}
+	}
+	return sb.String()
+}
+
+// dataTypeOf はカラム型から information_schema.COLUMNS.DATA_TYPE 相当の型名を返す。
+func dataTypeOf(columnType string) string {
+	if i := strings.IndexAny(columnType, "( "); i >= 0 {
+		return columnType[:i]
+	}
+	return columnType
+}
+
+// createDefaultValueString はDEFAULT句の値を information_schema.COLUMNS.COLUMN_DEFAULT に近い形式で返す。
+// DEFAULT NULL は information_schema と同様にデフォルトなし（false）とする。
+func createDefaultValueString(expr ast.ExprNode) (string, bool) {
+	if v, ok := expr.(ast.ValueExpr); ok {
+		// 文字列リテラルは charset introducer（_utf8mb4'...'）を含めずに値を取り出す
+		switch val := v.GetValue().(type) {
+		case nil:
+			return "", false
+		case string:
+			return val, true
+		}
+	}
+	// CURRENT_TIMESTAMP() などの関数呼び出しは information_schema と同様に括弧を省く
+	return strings.TrimSuffix(restoreExpr(expr), "()"), true
+}
+
+func restoreExpr(expr ast.ExprNode) string {
+	if expr == nil {
+		return ""
+	}
+	var sb strings.Builder
+	ctx := format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)
+	if err := expr.Restore(ctx); err != nil {
+		return ""
+	}
+	return sb.String()
+}
+
+// spatialMarkerValue はCOMMENTに埋め込まれた空間データ型の目印から元の型名を取り出す。
+func spatialMarkerValue(expr ast.ExprNode) (string, bool) {
+	s := restoreExpr(expr)
+	prefix := "'" + spatialMarker + ":"
+	if !strings.HasPrefix(s, prefix) {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(s, prefix), "'")), true
+}
+
+// isTextType は文字セットを持つ文字列系カラムかを判定する。
+func isTextType(col *ast.ColumnDef) bool {
+	if col.Tp == nil || col.Tp.GetCharset() == "binary" {
+		return false
+	}
+	switch col.Tp.GetType() {
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString,
+		mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob,
+		mysql.TypeEnum, mysql.TypeSet:
+		return true
+	default:
+		return false
+	}
+}
+
+// charsetOfCollation は照合順序名から文字セット名を返す（例: utf8mb4_bin → utf8mb4）。
+func charsetOfCollation(collation string) string {
+	if i := strings.Index(collation, "_"); i > 0 {
+		return collation[:i]
+	}
+	return collation
+}
+
+func constraintColumns(keys []*ast.IndexPartSpecification) []string {
+	cols := make([]string, 0, len(keys))
+	for _, key := range keys {
+		// 関数インデックスの式部分はカラム名を持たない
+		if key.Column != nil {
+			cols = append(cols, key.Column.Name.O)
+		}
+	}
+	return cols
+}
+
+// hasExpressionKey はキー部に関数インデックスの式が含まれるかを返す。
+func hasExpressionKey(keys []*ast.IndexPartSpecification) bool {
+	for _, key := range keys {
+		if key.Column == nil && key.Expr != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func indexTypeString(opt *ast.IndexOption) string {
+	if opt == nil {
+		return "BTREE"
+	}
+	if opt.Comment == spatialMarker {
+		return "SPATIAL"
+	}
+	if opt.Tp == ast.IndexTypeHash {
+		return "HASH"
+	}
+	return "BTREE"
+}
+
+func buildForeignKey(ref *ast.ReferenceDef, name string, cols []string, schema, table string) meta.ForeignKeyMeta {
+	fk := meta.ForeignKeyMeta{
+		ConstraintName:   name,
+		SourceSchema:     schema,
+		SourceTable:      table,
+		SourceColumns:    cols,
+		ReferencedSchema: schema,
+		OnDelete:         "NO ACTION",
+		OnUpdate:         "NO ACTION",
+	}
+	if ref == nil {
+		return fk
+	}
+	if ref.Table != nil {
+		fk.ReferencedTable = ref.Table.Name.O
+		if ref.Table.Schema.O != "" {
+			fk.ReferencedSchema = ref.Table.Schema.O
+		}
+	}
+	fk.ReferencedColumns = constraintColumns(ref.IndexPartSpecifications)
+	if ref.OnDelete != nil && ref.OnDelete.ReferOpt != ast.ReferOptionNoOption {
+		fk.OnDelete = ref.OnDelete.ReferOpt.String()
+	}
+	if ref.OnUpdate != nil && ref.OnUpdate.ReferOpt != ast.ReferOptionNoOption {
+		fk.OnUpdate = ref.OnUpdate.ReferOpt.String()
+	}
+	return fk
+}
+
+// indexBuilder はインデックスを定義順に収集し、名前のないインデックスに名前を付ける。
+type indexBuilder struct {
+	indexes []meta.IndexMeta
+	names   map[string]bool
+}
+
+func (b *indexBuilder) add(idx meta.IndexMeta) {
+	if len(idx.Columns) == 0 && !idx.HasExpression {
+		return
+	}
+	if idx.IsPrimary {
+		for _, existing := range b.indexes {
+			if existing.IsPrimary {
+				return
+			}
+		}
+	}
+	if idx.Name == "" {
+		// MySQLと同様に先頭カラム名を使う
+		idx.Name = b.uniqueName(idx.Columns[0])
+	}
+	b.names[strings.ToLower(idx.Name)] = true
+	b.indexes = append(b.indexes, idx)
+}
+
+// uniqueName は base が既存のインデックス名と重複する場合に _2, _3 ... を付与した名前を返す。
+func (b *indexBuilder) uniqueName(base string) string {
+	name := base
+	for n := 2; b.names[strings.ToLower(name)]; n++ {
+		name = fmt.Sprintf("%s_%d", base, n)
+	}
+	return name
+}
+
+// applyColumnKeys はインデックス定義から各カラムの COLUMN_KEY（PRI/UNI/MUL）を設定する。
+func applyColumnKeys(tm *meta.TableMeta) {
+	rank := map[string]int{"": 0, "MUL": 1, "UNI": 2, "PRI": 3}
+	set := func(name, key string) {
+		for i := range tm.Columns {
+			c := &tm.Columns[i]
+			if strings.EqualFold(c.Name, name) && rank[key] > rank[c.ColumnKey] {
+				c.ColumnKey = key
+			}
+		}
+	}
+	for _, idx := range tm.Indexes {
+		switch {
+		case idx.IsPrimary:
+			for _, col := range idx.Columns {
+				set(col, "PRI")
+			}
+		case idx.HasExpression:
+			// 式を含むインデックスはキー部の順序を保持しないため先頭カラムを判定しない
+		case idx.IsUnique && len(idx.Columns) == 1:
+			set(idx.Columns[0], "UNI")
+		default:
+			set(idx.Columns[0], "MUL")
+		}
+	}
+	// PRIMARY KEY に含まれるカラムは暗黙的に NOT NULL となる
+	for i := range tm.Columns {
+		if tm.Columns[i].ColumnKey == "PRI" {
+			tm.Columns[i].IsNullable = false
+		}
+	}
+}
+
+func partitionTypeString(pm *ast.PartitionMethod) string {
+	s := pm.Tp.String()
+	if pm.Linear {
+		s = "LINEAR " + s
+	}
+	if (pm.Tp == ast.PartitionTypeRange || pm.Tp == ast.PartitionTypeList) && len(pm.ColumnNames) > 0 {
+		s += " COLUMNS"
+	}
+	return s
+}
+
+// cloneTableDefinition はテーブル定義を別名で複製する（CREATE TABLE ... LIKE 用）。
+// 外部キーは LIKE では複製されないため含めない。
+func cloneTableDefinition(src meta.TableMeta, schema, table string) meta.TableMeta {
+	tm := meta.TableMeta{
+		Schema:        schema,
+		Table:         table,
+		Engine:        src.Engine,
+		Columns:       append([]meta.ColumnMeta(nil), src.Columns...),
+		IsPartitioned: src.IsPartitioned,
+		PartitionType: src.PartitionType,
+	}
+	for _, idx := range src.Indexes {
+		idx.Columns = append([]string(nil), idx.Columns...)
+		tm.Indexes = append(tm.Indexes, idx)
+	}
+	return tm
+}
+
+func createTableKey(schema, table string) string {
+	return strings.ToLower(schema) + "." + strings.ToLower(table)
+}
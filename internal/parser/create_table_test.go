@@ -0,0 +1,300 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// sampleSchemaDump は mysqldump --no-data 形式のスキーマダンプ。
+const sampleSchemaDump = "/*!40101 SET @saved_cs_client     = @@character_set_client */;\n" +
+	"/*!50503 SET character_set_client = utf8mb4 */;\n" +
+	"USE `shop`;\n" +
+	"DROP TABLE IF EXISTS `users`;\n" +
+	"CREATE TABLE `users` (\n" +
+	"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+	"  `email` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,\n" +
+	"  `name` varchar(100) DEFAULT 'guest',\n" +
+	"  `status` enum('Active','Banned') NOT NULL DEFAULT 'Active',\n" +
+	"  `location` point NOT NULL /*!80003 SRID 4326 */,\n" +
+	"  `bio` text,\n" +
+	"  `name_upper` varchar(100) GENERATED ALWAYS AS (upper(`name`)) VIRTUAL,\n" +
+	"  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  UNIQUE KEY `uk_email` (`email`),\n" +
+	"  KEY `idx_name_status` (`name`,`status`),\n" +
+	"  FULLTEXT KEY `ft_bio` (`bio`),\n" +
+	"  SPATIAL KEY `sp_location` (`location`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;\n" +
+	"/*!40101 SET character_set_client = @saved_cs_client */;\n" +
+	"CREATE TABLE `orders` (\n" +
+	"  `id` bigint NOT NULL,\n" +
+	"  `user_id` bigint unsigned NOT NULL,\n" +
+	"  `created_at` datetime NOT NULL,\n" +
+	"  PRIMARY KEY (`id`,`created_at`),\n" +
+	"  KEY `user_id` (`user_id`),\n" +
+	"  CONSTRAINT `fk_orders_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4\n" +
+	"/*!50100 PARTITION BY RANGE (year(`created_at`))\n" +
+	"(PARTITION p2024 VALUES LESS THAN (2025) ENGINE = InnoDB,\n" +
+	" PARTITION pmax VALUES LESS THAN MAXVALUE ENGINE = InnoDB) */;\n"
+
+func findTable(t *testing.T, tables []meta.TableMeta, name string) *meta.TableMeta {
+	t.Helper()
+	for i := range tables {
+		if tables[i].Table == name {
+			return &tables[i]
+		}
+	}
+	t.Fatalf("テーブル%sが存在すること", name)
+	return nil
+}
+
+func findColumnMeta(t *testing.T, tm *meta.TableMeta, name string) *meta.ColumnMeta {
+	t.Helper()
+	for i := range tm.Columns {
+		if tm.Columns[i].Name == name {
+			return &tm.Columns[i]
+		}
+	}
+	t.Fatalf("カラム%sが存在すること", name)
+	return nil
+}
+
+// TestParseCreateTablesColumns — カラム定義からColumnMetaが構築されることを検証
+func TestParseCreateTablesColumns(t *testing.T) {
+	tables, err := ParseCreateTables(sampleSchemaDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("テーブル数が2であること: got %d", len(tables))
+	}
+	users := findTable(t, tables, "users")
+	if users.Schema != "shop" {
+		t.Errorf("USE文のスキーマが設定されること: got %q", users.Schema)
+	}
+	if users.Engine != "InnoDB" {
+		t.Errorf("エンジンがInnoDBであること: got %q", users.Engine)
+	}
+
+	tests := []struct {
+		name       string
+		columnType string
+		dataType   string
+		nullable   bool
+		key        string
+		def        string
+		extra      string
+		charset    string
+		collation  string
+	}{
+		{"id", "bigint unsigned", "bigint", false, "PRI", "", "auto_increment", "", ""},
+		{"email", "varchar(255)", "varchar", false, "UNI", "", "", "utf8mb4", "utf8mb4_bin"},
+		{"name", "varchar(100)", "varchar", true, "MUL", "guest", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+		{"status", "enum('Active','Banned')", "enum", false, "", "Active", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+		{"location", "point", "point", false, "MUL", "", "", "", ""},
+		{"name_upper", "varchar(100)", "varchar", true, "", "", "VIRTUAL GENERATED", "utf8mb4", "utf8mb4_0900_ai_ci"},
+		{"updated_at", "timestamp", "timestamp", true, "", "CURRENT_TIMESTAMP", "on update CURRENT_TIMESTAMP", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := findColumnMeta(t, users, tt.name)
+			if col.ColumnType != tt.columnType {
+				t.Errorf("ColumnTypeが%qであること: got %q", tt.columnType, col.ColumnType)
+			}
+			if col.DataType != tt.dataType {
+				t.Errorf("DataTypeが%qであること: got %q", tt.dataType, col.DataType)
+			}
+			if col.IsNullable != tt.nullable {
+				t.Errorf("IsNullableが%vであること: got %v", tt.nullable, col.IsNullable)
+			}
+			if col.ColumnKey != tt.key {
+				t.Errorf("ColumnKeyが%qであること: got %q", tt.key, col.ColumnKey)
+			}
+			if col.DefaultValue != tt.def {
+				t.Errorf("DefaultValueが%qであること: got %q", tt.def, col.DefaultValue)
+			}
+			if col.HasDefault != (tt.def != "") {
+				t.Errorf("HasDefaultが%vであること: got %v", tt.def != "", col.HasDefault)
+			}
+			if col.Extra != tt.extra {
+				t.Errorf("Extraが%qであること: got %q", tt.extra, col.Extra)
+			}
+			if col.CharacterSet != tt.charset || col.Collation != tt.collation {
+				t.Errorf("文字セット/照合順序が%s/%sであること: got %s/%s", tt.charset, tt.collation, col.CharacterSet, col.Collation)
+			}
+		})
+	}
+}
+
+// TestParseCreateTablesIndexes — FULLTEXT/SPATIALを含むインデックスが構築されることを検証
+func TestParseCreateTablesIndexes(t *testing.T) {
+	tables, err := ParseCreateTables(sampleSchemaDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := findTable(t, tables, "users")
+
+	want := map[string]struct {
+		indexType string
+		unique    bool
+		primary   bool
+	}{
+		"PRIMARY":         {"BTREE", true, true},
+		"uk_email":        {"BTREE", true, false},
+		"idx_name_status": {"BTREE", false, false},
+		"ft_bio":          {"FULLTEXT", false, false},
+		"sp_location":     {"SPATIAL", false, false},
+	}
+	if len(users.Indexes) != len(want) {
+		t.Fatalf("インデックス数が%dであること: got %d", len(want), len(users.Indexes))
+	}
+	for _, idx := range users.Indexes {
+		w, ok := want[idx.Name]
+		if !ok {
+			t.Errorf("想定外のインデックス: %s", idx.Name)
+			continue
+		}
+		if idx.IndexType != w.indexType || idx.IsUnique != w.unique || idx.IsPrimary != w.primary {
+			t.Errorf("%s: IndexType=%s IsUnique=%v IsPrimary=%vであること: got %s %v %v",
+				idx.Name, w.indexType, w.unique, w.primary, idx.IndexType, idx.IsUnique, idx.IsPrimary)
+		}
+	}
+}
+
+// TestParseCreateTablesForeignKeys — 外部キーとReferencedByが構築されることを検証
+func TestParseCreateTablesForeignKeys(t *testing.T) {
+	tables, err := ParseCreateTables(sampleSchemaDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders := findTable(t, tables, "orders")
+	if len(orders.ForeignKeys) != 1 {
+		t.Fatalf("外部キー数が1であること: got %d", len(orders.ForeignKeys))
+	}
+	fk := orders.ForeignKeys[0]
+	if fk.ConstraintName != "fk_orders_user" || fk.ReferencedTable != "users" || fk.ReferencedSchema != "shop" {
+		t.Errorf("fk_orders_userがshop.usersを参照すること: got %+v", fk)
+	}
+	if fk.OnDelete != "CASCADE" || fk.OnUpdate != "NO ACTION" {
+		t.Errorf("ON DELETE CASCADE / ON UPDATE NO ACTIONであること: got %s / %s", fk.OnDelete, fk.OnUpdate)
+	}
+
+	users := findTable(t, tables, "users")
+	if len(users.ReferencedBy) != 1 || users.ReferencedBy[0].SourceTable != "orders" {
+		t.Errorf("usersがordersから参照されること: got %+v", users.ReferencedBy)
+	}
+}
+
+// TestParseCreateTablesPartition — パーティション方式が取得されることを検証
+func TestParseCreateTablesPartition(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{"RANGE", sampleSchemaDump, "RANGE"},
+		{"LINEAR KEY", "CREATE TABLE t (id INT PRIMARY KEY) PARTITION BY LINEAR KEY (id) PARTITIONS 4", "LINEAR KEY"},
+		{"LIST COLUMNS", "CREATE TABLE t (c VARCHAR(10)) PARTITION BY LIST COLUMNS (c) (PARTITION p0 VALUES IN ('a'))", "LIST COLUMNS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := ParseCreateTables(tt.sql)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tm := &tables[len(tables)-1]
+			if !tm.IsPartitioned || tm.PartitionType != tt.want {
+				t.Errorf("パーティション方式が%sであること: got %v %q", tt.want, tm.IsPartitioned, tm.PartitionType)
+			}
+		})
+	}
+}
+
+// TestParseCreateTablesInlineConstraints — カラム定義内のPRIMARY KEY/UNIQUE/REFERENCESを検証
+func TestParseCreateTablesInlineConstraints(t *testing.T) {
+	tables, err := ParseCreateTables(`
+		CREATE TABLE parent (id INT PRIMARY KEY, code VARCHAR(10) UNIQUE);
+		CREATE TABLE child (id INT PRIMARY KEY, parent_id INT, FOREIGN KEY (parent_id) REFERENCES parent (id), INDEX (parent_id));`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := findTable(t, tables, "parent")
+	if col := findColumnMeta(t, parent, "id"); col.IsNullable || col.ColumnKey != "PRI" {
+		t.Errorf("PRIMARY KEYカラムはNOT NULLかつPRIであること: got %v %q", col.IsNullable, col.ColumnKey)
+	}
+	if len(parent.Indexes) != 2 || parent.Indexes[1].Name != "code" || !parent.Indexes[1].IsUnique {
+		t.Errorf("無名UNIQUEインデックスがカラム名で命名されること: got %+v", parent.Indexes)
+	}
+
+	child := findTable(t, tables, "child")
+	if len(child.ForeignKeys) != 1 || child.ForeignKeys[0].ConstraintName != "child_ibfk_1" {
+		t.Errorf("無名外部キーが<table>_ibfk_Nで命名されること: got %+v", child.ForeignKeys)
+	}
+	if len(parent.ReferencedBy) != 1 {
+		t.Errorf("parentがchildから参照されること: got %d", len(parent.ReferencedBy))
+	}
+}
+
+// TestParseCreateTablesLike — CREATE TABLE ... LIKE が参照元の定義を複製することを検証
+func TestParseCreateTablesLike(t *testing.T) {
+	tables, err := ParseCreateTables("CREATE TABLE a (id INT PRIMARY KEY, name VARCHAR(10)); CREATE TABLE b LIKE a;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := findTable(t, tables, "b")
+	if len(b.Columns) != 2 || len(b.Indexes) != 1 {
+		t.Errorf("カラムとインデックスが複製されること: got %d columns, %d indexes", len(b.Columns), len(b.Indexes))
+	}
+}
+
+// TestParseCreateTablesNoStatements — CREATE TABLE がない場合はエラーになることを検証
+func TestParseCreateTablesNoStatements(t *testing.T) {
+	if _, err := ParseCreateTables("SELECT 1"); err == nil {
+		t.Error("CREATE TABLE がない場合はエラーになること")
+	}
+}
+
+// TestParseCreateTablesEmptyDefault — DEFAULT ” と DEFAULT なし・DEFAULT NULL が区別されることを検証
+func TestParseCreateTablesEmptyDefault(t *testing.T) {
+	tables, err := ParseCreateTables("CREATE TABLE `t` (`a` varchar(10) NOT NULL DEFAULT '', `b` varchar(10) NOT NULL, `c` varchar(10) DEFAULT NULL);")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := findTable(t, tables, "t")
+	for name, want := range map[string]bool{"a": true, "b": false, "c": false} {
+		col := findColumnMeta(t, tm, name)
+		if col.HasDefault != want || col.DefaultValue != "" {
+			t.Errorf("%s: HasDefaultが%vであること: got %v %q", name, want, col.HasDefault, col.DefaultValue)
+		}
+	}
+}
+
+// TestParseCreateTablesFunctionalIndex — 関数インデックスを式の目印付きで保持することを検証
+func TestParseCreateTablesFunctionalIndex(t *testing.T) {
+	tables, err := ParseCreateTables("CREATE TABLE `users` (`id` int NOT NULL, `email` varchar(255) NOT NULL, `name` varchar(100),\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `idx_lower` ((lower(`email`))),\n" +
+		"  KEY `idx_name_lower` (`name`,(lower(`email`))),\n" +
+		"  KEY ((upper(`name`)))\n" +
+		");")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := findTable(t, tables, "users")
+	want := map[string][]string{"idx_lower": nil, "idx_name_lower": {"name"}, "functional_index": nil}
+	for _, idx := range tm.Indexes {
+		cols, ok := want[idx.Name]
+		if !ok {
+			continue
+		}
+		delete(want, idx.Name)
+		if !idx.HasExpression || len(idx.Columns) != len(cols) || (len(cols) > 0 && idx.Columns[0] != cols[0]) {
+			t.Errorf("%s: 式の目印とカラム %v を持つこと: got %+v", idx.Name, cols, idx)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("関数インデックスが保持されること: missing %v (got %+v)", want, tm.Indexes)
+	}
+}
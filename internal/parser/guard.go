@@ -0,0 +1,191 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// GuardClause は ALTER TABLE 文に付ける ALGORITHM/LOCK 句。
+type GuardClause struct {
+	Algorithm meta.Algorithm
+	Lock      meta.LockLevel
+}
+
+// Guard はSQLスクリプト中の ALTER TABLE 文に ALGORITHM/LOCK 句を付けたスクリプトを返す。
+// guards[i] は i 番目の ALTER TABLE 文（Parse が返す i 番目の操作）に付ける句。
+// 句を付ける ALTER 文の本体だけを書き換え、ALTER 以外の文やコメント（マイグレーションツールの
+// ディレクティブなど）、空白は元のスクリプトのまま残す。
+// ALGORITHM/LOCK 句を既に明示している文と、対応する句がない文は変更しない。
+// INSTANT は LOCK 句を指定できないため ALGORITHM 句のみを付ける。
+func Guard(sql string, guards []GuardClause) (string, error) {
+	p := parser.New()
+	stmts, _, err := p.Parse(sql, "", "")
+	if err != nil {
+		return "", fmt.Errorf("SQL parse error: %w", err)
+	}
+
+	var sb strings.Builder
+	pos, alterIdx := 0, 0
+	for _, stmt := range stmts {
+		// 文のテキストは元のスクリプトの部分文字列なので、位置を求めて間のテキストをそのまま残す
+		text := stmt.Text()
+		off := strings.Index(sql[pos:], text)
+		if off < 0 {
+			return "", fmt.Errorf("statement not found in the SQL: %s", text)
+		}
+		sb.WriteString(sql[pos : pos+off])
+		pos += off + len(text)
+
+		if alterStmt, ok := stmt.(*ast.AlterTableStmt); ok {
+			if alterIdx < len(guards) {
+				start, end := codeSpan(text)
+				text = text[:start] + guardAlter(alterStmt, guards[alterIdx], text[start:end]) + text[end:]
+			}
+			alterIdx++
+		}
+		sb.WriteString(text)
+	}
+	sb.WriteString(sql[pos:])
+	return sb.String(), nil
+}
+
+// codeSpan は文のテキストから前後のコメント・空白と末尾の ; を除いた本体の範囲 [start, end) を返す。
+// 文字列・識別子の引用符内は読み飛ばし、/*! */ と /*+ */ は本体として扱う。
+func codeSpan(text string) (start, end int) {
+	start = -1
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '#' || (strings.HasPrefix(text[i:], "--") && (i+2 == len(text) || text[i+2] <= ' ')):
+			if j := strings.IndexByte(text[i:], '\n'); j >= 0 {
+				i += j + 1
+			} else {
+				i = len(text)
+			}
+			continue
+		case strings.HasPrefix(text[i:], "/*") && !strings.HasPrefix(text[i:], "/*!") && !strings.HasPrefix(text[i:], "/*+"):
+			i = skipBlockComment(text, i)
+			continue
+		case c == ';' || c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(text, i)
+		case strings.HasPrefix(text[i:], "/*"):
+			i = skipBlockComment(text, i)
+		default:
+			i++
+		}
+		end = i
+	}
+	if start < 0 {
+		return 0, 0
+	}
+	return start, end
+}
+
+// skipBlockComment は i から始まる /* */ コメントの直後の位置を返す。
+func skipBlockComment(text string, i int) int {
+	if j := strings.Index(text[i+2:], "*/"); j >= 0 {
+		return i + 2 + j + 2
+	}
+	return len(text)
+}
+
+// skipQuoted は i から始まる引用符で囲まれた文字列・識別子の直後の位置を返す。
+// バックスラッシュによるエスケープ（識別子以外）と引用符の二重化を扱う。
+func skipQuoted(text string, i int) int {
+	quote := text[i]
+	for j := i + 1; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			if quote != '`' {
+				j++
+			}
+		case quote:
+			if j+1 < len(text) && text[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(text)
+}
+
+// guardAlter は ALTER TABLE 文に句を追加して整形したSQLを返す。追加しない場合は元のSQLを返す。
+func guardAlter(stmt *ast.AlterTableStmt, g GuardClause, original string) string {
+	for _, spec := range stmt.Specs {
+		if spec.Tp == ast.AlterTableAlgorithm || spec.Tp == ast.AlterTableLock {
+			return original
+		}
+	}
+	alg, ok := algorithmTypes[g.Algorithm]
+	if !ok {
+		return original
+	}
+	clauses := []*ast.AlterTableSpec{{Tp: ast.AlterTableAlgorithm, Algorithm: alg}}
+	if lock, ok := lockTypes[g.Lock]; ok && g.Algorithm != meta.AlgorithmInstant {
+		clauses = append(clauses, &ast.AlterTableSpec{Tp: ast.AlterTableLock, LockType: lock})
+	}
+	guarded := *stmt
+	if hasStandalonePartitionSpec(stmt) {
+		// パーティション管理の句の後に ALGORITHM/LOCK 句は書けないため前に置く
+		guarded.Specs = append(clauses, stmt.Specs...)
+	} else {
+		guarded.Specs = append(append([]*ast.AlterTableSpec(nil), stmt.Specs...), clauses...)
+	}
+	if restored := restoreNode(&guarded); restored != "" {
+		return restored
+	}
+	return original
+}
+
+// standalonePartitionSpecs は他の変更と並べられず、ALGORITHM/LOCK 句を前にしか書けないパーティション・テーブルスペース操作。
+var standalonePartitionSpecs = map[ast.AlterTableType]bool{
+	ast.AlterTableAddPartitions:              true,
+	ast.AlterTableDropPartition:              true,
+	ast.AlterTableTruncatePartition:          true,
+	ast.AlterTableCoalescePartitions:         true,
+	ast.AlterTableReorganizePartition:        true,
+	ast.AlterTableRebuildPartition:           true,
+	ast.AlterTableCheckPartitions:            true,
+	ast.AlterTableOptimizePartition:          true,
+	ast.AlterTableRepairPartition:            true,
+	ast.AlterTableExchangePartition:          true,
+	ast.AlterTableImportPartitionTablespace:  true,
+	ast.AlterTableDiscardPartitionTablespace: true,
+	ast.AlterTableImportTablespace:           true,
+	ast.AlterTableDiscardTablespace:          true,
+}
+
+func hasStandalonePartitionSpec(stmt *ast.AlterTableStmt) bool {
+	for _, spec := range stmt.Specs {
+		if standalonePartitionSpecs[spec.Tp] {
+			return true
+		}
+	}
+	return false
+}
+
+var algorithmTypes = map[meta.Algorithm]ast.AlgorithmType{
+	meta.AlgorithmInstant: ast.AlgorithmTypeInstant,
+	meta.AlgorithmInplace: ast.AlgorithmTypeInplace,
+	meta.AlgorithmCopy:    ast.AlgorithmTypeCopy,
+}
+
+var lockTypes = map[meta.LockLevel]ast.LockType{
+	meta.LockNone:      ast.LockTypeNone,
+	meta.LockShared:    ast.LockTypeShared,
+	meta.LockExclusive: ast.LockTypeExclusive,
+}
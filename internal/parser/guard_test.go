@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func TestGuard(t *testing.T) {
+	sql := "CREATE TABLE logs (id INT PRIMARY KEY);\n" +
+		"ALTER TABLE users ADD COLUMN nickname VARCHAR(255);\n" +
+		"INSERT INTO logs VALUES (1);\n" +
+		"ALTER TABLE users ADD INDEX idx_email (email);\n" +
+		"ALTER TABLE users MODIFY COLUMN email VARCHAR(512) NOT NULL;\n"
+	guards := []GuardClause{
+		{Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone},
+		{Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone},
+		{Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared},
+	}
+	got, err := Guard(sql, guards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "CREATE TABLE logs (id INT PRIMARY KEY);\n" +
+		"ALTER TABLE `users` ADD COLUMN `nickname` VARCHAR(255), ALGORITHM = INSTANT;\n" +
+		"INSERT INTO logs VALUES (1);\n" +
+		"ALTER TABLE `users` ADD INDEX `idx_email`(`email`), ALGORITHM = INPLACE, LOCK = NONE;\n" +
+		"ALTER TABLE `users` MODIFY COLUMN `email` VARCHAR(512) NOT NULL, ALGORITHM = COPY, LOCK = SHARED;\n"
+	if got != want {
+		t.Errorf("ALTER文に句が付き、ALTER以外の文と順序が保たれること:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGuardExplicitClause(t *testing.T) {
+	sql := "ALTER TABLE users ADD COLUMN nickname VARCHAR(255), ALGORITHM=INPLACE"
+	got, err := Guard(sql, []GuardClause{{Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != sql {
+		t.Errorf("句を明示している文は変更しないこと: got %q", got)
+	}
+}
+
+func TestGuardKeepsComments(t *testing.T) {
+	// ALTER 文の本体以外（マイグレーションツールのディレクティブやコメント、空白）は元のまま残す
+	sql := "-- +goose Up\n" +
+		"-- +goose StatementBegin\n" +
+		"ALTER TABLE users ADD COLUMN nickname VARCHAR(255); -- hello\n" +
+		"/* c */ INSERT INTO logs VALUES ('a;b');\n" +
+		"ALTER TABLE users ADD INDEX idx_email (email) -- no semicolon\n" +
+		"-- +goose StatementEnd\n"
+	got, err := Guard(sql, []GuardClause{
+		{Algorithm: meta.AlgorithmInstant},
+		{Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "-- +goose Up\n" +
+		"-- +goose StatementBegin\n" +
+		"ALTER TABLE `users` ADD COLUMN `nickname` VARCHAR(255), ALGORITHM = INSTANT; -- hello\n" +
+		"/* c */ INSERT INTO logs VALUES ('a;b');\n" +
+		"ALTER TABLE `users` ADD INDEX `idx_email`(`email`), ALGORITHM = INPLACE, LOCK = NONE -- no semicolon\n" +
+		"-- +goose StatementEnd\n"
+	if got != want {
+		t.Errorf("コメントと空白が保たれること:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCodeSpan(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"ALTER TABLE t ADD c INT;", "ALTER TABLE t ADD c INT"},
+		{"-- a\n# b\n/* c */ ALTER TABLE t COMMENT '-- x; /* y */' /* d */;", "ALTER TABLE t COMMENT '-- x; /* y */'"},
+		{"/*!50100 ALTER TABLE t ENGINE=InnoDB */", "/*!50100 ALTER TABLE t ENGINE=InnoDB */"},
+		{"ALTER TABLE `a``b` COMMENT 'it''s \\' --' -- tail", "ALTER TABLE `a``b` COMMENT 'it''s \\' --'"},
+	}
+	for _, tt := range tests {
+		start, end := codeSpan(tt.text)
+		if got := tt.text[start:end]; got != tt.want {
+			t.Errorf("codeSpan(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestGuardPartition(t *testing.T) {
+	// パーティション管理の句の後には ALGORITHM/LOCK 句を書けないため前に置く
+	sql := "ALTER TABLE logs ADD PARTITION (PARTITION p2025 VALUES LESS THAN (2026));\n" +
+		"ALTER TABLE logs TRUNCATE PARTITION p2024;\n"
+	got, err := Guard(sql, []GuardClause{
+		{Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone},
+		{Algorithm: meta.AlgorithmInplace, Lock: meta.LockShared},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ALTER TABLE `logs` ALGORITHM = INPLACE, LOCK = NONE, ADD PARTITION (PARTITION `p2025` VALUES LESS THAN (2026));\n" +
+		"ALTER TABLE `logs` ALGORITHM = INPLACE, LOCK = SHARED, TRUNCATE PARTITION `p2024`;\n"
+	if got != want {
+		t.Errorf("パーティション管理の文は句を前に置くこと:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGuardParseError(t *testing.T) {
+	if _, err := Guard("ALTER TABLE", nil); err == nil {
+		t.Error("パースエラーを返すこと")
+	}
+}
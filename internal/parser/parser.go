@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -12,7 +13,11 @@ import (
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
 )
 
+// ErrNoAlterStatements はSQLにALTER TABLE文が含まれない場合に返される。
+var ErrNoAlterStatements = errors.New("no ALTER TABLE statements found")
+
 // Parse は1つ以上のSQL文をパースし、ALTER操作のリストを返す。
+// ALTER TABLE文が1つもない場合は ErrNoAlterStatements を返す。
 func Parse(sql string) ([]meta.AlterOperation, error) {
 	p := parser.New()
 	stmts, _, err := p.Parse(sql, "", "")
@@ -34,21 +39,39 @@ func Parse(sql string) ([]meta.AlterOperation, error) {
 	}
 
 	if len(ops) == 0 {
-		return nil, fmt.Errorf("no ALTER TABLE statements found")
+		return nil, ErrNoAlterStatements
 	}
 	return ops, nil
 }
 
 func buildAlterOperation(stmt *ast.AlterTableStmt, rawSQL string) (meta.AlterOperation, error) {
 	op := meta.AlterOperation{
-		Table:  stmt.Table.Name.L,
-		Schema: stmt.Table.Schema.L,
-		RawSQL: extractSQL(stmt, rawSQL),
+		Table:    stmt.Table.Name.L,
+		Schema:   stmt.Table.Schema.L,
+		RawSQL:   extractSQL(stmt, rawSQL),
+		TableRef: restoreNode(stmt.Table),
+		// 外部ツールのコマンドには lower_case_table_names=0 でも一致するよう元の表記を使う
+		OriginalTable:  stmt.Table.Name.O,
+		OriginalSchema: stmt.Table.Schema.O,
 	}
 
 	for _, spec := range stmt.Specs {
-		actions := specToActions(spec)
-		op.Actions = append(op.Actions, actions...)
+		switch spec.Tp {
+		case ast.AlterTableAlgorithm:
+			op.RequestedAlgorithm = meta.Algorithm(spec.Algorithm.String())
+			continue
+		case ast.AlterTableLock:
+			op.RequestedLock = meta.LockLevel(spec.LockType.String())
+			continue
+		}
+		for _, single := range splitSpec(spec) {
+			actions := specToActions(single)
+			sql := restoreNode(single)
+			for i := range actions {
+				actions[i].SQL = sql
+			}
+			op.Actions = append(op.Actions, actions...)
+		}
 	}
 
 	if len(op.Actions) == 0 {
@@ -66,6 +89,47 @@ func extractSQL(stmt *ast.AlterTableStmt, rawSQL string) string {
 	return rawSQL
 }
 
+// restoreNode はASTノードを整形済みSQLに戻す。失敗した場合は空文字を返す。
+func restoreNode(node ast.Node) string {
+	var sb strings.Builder
+	ctx := format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)
+	if err := node.Restore(ctx); err != nil {
+		return ""
+	}
+	return sb.String()
+}
+
+// splitSpec は複数のアクションを含む句をアクション単位の句に分割する。
+// ADD COLUMN (a INT, b INT) や ENGINE=InnoDB ROW_FORMAT=DYNAMIC のように
+// 1つの句が複数のアクションになる場合、各アクションのSQLを個別に得るために使う。
+func splitSpec(spec *ast.AlterTableSpec) []*ast.AlterTableSpec {
+	switch {
+	case spec.Tp == ast.AlterTableAddColumns && len(spec.NewColumns) > 1:
+		specs := make([]*ast.AlterTableSpec, 0, len(spec.NewColumns))
+		for _, col := range spec.NewColumns {
+			single := *spec
+			single.NewColumns = []*ast.ColumnDef{col}
+			single.NewConstraints = nil
+			if single.Position == nil {
+				// Position がないと括弧付きの複数カラム形式で整形される
+				single.Position = &ast.ColumnPosition{Tp: ast.ColumnPositionNone}
+			}
+			specs = append(specs, &single)
+		}
+		return specs
+	case spec.Tp == ast.AlterTableOption && len(spec.Options) > 1:
+		specs := make([]*ast.AlterTableSpec, 0, len(spec.Options))
+		for _, opt := range spec.Options {
+			single := *spec
+			single.Options = []*ast.TableOption{opt}
+			specs = append(specs, &single)
+		}
+		return specs
+	default:
+		return []*ast.AlterTableSpec{spec}
+	}
+}
+
 // simpleSpecActions は単純な1対1マッピング（specType → ActionType）。
 var simpleSpecActions = map[ast.AlterTableType]meta.AlterActionType{
 	ast.AlterTableDropPrimaryKey:             meta.ActionDropPrimaryKey,
@@ -76,7 +140,6 @@ var simpleSpecActions = map[ast.AlterTableType]meta.AlterActionType{
 	ast.AlterTableTruncatePartition:          meta.ActionTruncatePartition,
 	ast.AlterTableRebuildPartition:           meta.ActionRebuildPartition,
 	ast.AlterTableRemovePartitioning:         meta.ActionRemovePartitioning,
-	ast.AlterTablePartition:                  meta.ActionPartitionBy,
 	ast.AlterTableExchangePartition:          meta.ActionExchangePartition,
 	ast.AlterTableForce:                      meta.ActionForceRebuild,
 	ast.AlterTableCheckPartitions:            meta.ActionCheckPartition,
@@ -100,6 +163,7 @@ var complexSpecHandlers = map[ast.AlterTableType]func(*ast.AlterTableSpec) []met
 	ast.AlterTableRenameIndex:    handleRenameIndex,
 	ast.AlterTableRenameTable:    handleRenameTable,
 	ast.AlterTableOption:         handleTableOptions,
+	ast.AlterTablePartition:      handlePartitionBy,
 }
 
 func specToActions(spec *ast.AlterTableSpec) []meta.AlterAction {
@@ -155,6 +219,7 @@ func handleModifyColumn(spec *ast.AlterTableSpec) []meta.AlterAction {
 	nullable := isNullable(col)
 	detail.IsNullable = &nullable
 	detail.Position = positionString(spec.Position)
+	detail.DefaultValue = defaultValueString(col)
 	detail.IsAutoIncrement = hasAutoIncrement(col)
 	detail.GeneratedType = generatedColumnType(col)
 	return []meta.AlterAction{{
@@ -176,6 +241,7 @@ func handleChangeColumn(spec *ast.AlterTableSpec) []meta.AlterAction {
 	nullable := isNullable(col)
 	detail.IsNullable = &nullable
 	detail.Position = positionString(spec.Position)
+	detail.DefaultValue = defaultValueString(col)
 	detail.IsAutoIncrement = hasAutoIncrement(col)
 	detail.GeneratedType = generatedColumnType(col)
 
@@ -207,7 +273,8 @@ func handleAlterColumn(spec *ast.AlterTableSpec) []meta.AlterAction {
 		return []meta.AlterAction{{
 			Type: meta.ActionSetDefault,
 			Detail: meta.ActionDetail{
-				ColumnName: colName,
+				ColumnName:   colName,
+				DefaultValue: restoreExpr(col.Options[0].Expr),
 			},
 		}}
 	}
@@ -329,6 +396,14 @@ func handleRenameTable(spec *ast.AlterTableSpec) []meta.AlterAction {
 	}}
 }
 
+func handlePartitionBy(spec *ast.AlterTableSpec) []meta.AlterAction {
+	detail := meta.ActionDetail{}
+	if spec.Partition != nil {
+		detail.PartitionType = partitionTypeString(&spec.Partition.PartitionMethod)
+	}
+	return []meta.AlterAction{{Type: meta.ActionPartitionBy, Detail: detail}}
+}
+
 func handleTableOptions(spec *ast.AlterTableSpec) []meta.AlterAction {
 	var actions []meta.AlterAction
 	for _, opt := range spec.Options {
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
@@ -218,6 +220,9 @@ func TestParseAlterColumnSetDefault(t *testing.T) {
 	if action.Type != meta.ActionSetDefault {
 		t.Errorf("アクションタイプがALTER_COLUMN_SET_DEFAULTであること: got %s", action.Type)
 	}
+	if !strings.Contains(action.Detail.DefaultValue, "'active'") {
+		t.Errorf("デフォルト値の式が設定されること: got %q", action.Detail.DefaultValue)
+	}
 }
 
 // TestParseAlterColumnDropDefault — ALTER COLUMN DROP DEFAULTのパースを検証
@@ -465,6 +470,22 @@ func TestParseRemovePartitioning(t *testing.T) {
 	}
 }
 
+// TestParsePartitionBy — PARTITION BYのパースとパーティション方式の取得を検証
+// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-partitioning-operations
+func TestParsePartitionBy(t *testing.T) {
+	ops, err := Parse("ALTER TABLE users PARTITION BY HASH(id) PARTITIONS 4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	action := ops[0].Actions[0]
+	if action.Type != meta.ActionPartitionBy {
+		t.Errorf("アクションタイプがPARTITION_BYであること: got %s", action.Type)
+	}
+	if action.Detail.PartitionType != "HASH" {
+		t.Errorf("パーティション方式がHASHであること: got %q", action.Detail.PartitionType)
+	}
+}
+
 // TestParseTruncatePartition — TRUNCATE PARTITIONのパースを検証
 // https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-partitioning-operations
 func TestParseTruncatePartition(t *testing.T) {
@@ -534,6 +555,9 @@ func TestParseNonAlterStatement(t *testing.T) {
 	if err == nil {
 		t.Fatal("ALTER以外の文はエラーになるべき")
 	}
+	if !errors.Is(err, ErrNoAlterStatements) {
+		t.Errorf("ErrNoAlterStatementsが返されること: got %v", err)
+	}
 }
 
 func TestParseInvalidSQL(t *testing.T) {
@@ -555,3 +579,60 @@ func TestParseSchemaQualifiedTable(t *testing.T) {
 		t.Errorf("テーブルが'users'であること: got %q", ops[0].Table)
 	}
 }
+
+// TestParseAlgorithmLockClause — ALGORITHM/LOCK句が取得されることを検証
+// https://dev.mysql.com/doc/refman/8.0/en/alter-table.html#alter-table-performance
+func TestParseAlgorithmLockClause(t *testing.T) {
+	ops, err := Parse("ALTER TABLE users ADD COLUMN a INT, ALGORITHM=INPLACE, LOCK=NONE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := ops[0]
+	if op.RequestedAlgorithm != meta.AlgorithmInplace {
+		t.Errorf("要求アルゴリズムがINPLACEであること: got %q", op.RequestedAlgorithm)
+	}
+	if op.RequestedLock != meta.LockNone {
+		t.Errorf("要求ロックがNONEであること: got %q", op.RequestedLock)
+	}
+	if len(op.Actions) != 1 {
+		t.Errorf("ALGORITHM/LOCK句はアクションに含まれないこと: got %d", len(op.Actions))
+	}
+
+	ops, err = Parse("ALTER TABLE users ADD COLUMN a INT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ops[0].RequestedAlgorithm != "" || ops[0].RequestedLock != "" {
+		t.Errorf("未指定の場合は空であること: got %q %q", ops[0].RequestedAlgorithm, ops[0].RequestedLock)
+	}
+}
+
+// TestParseActionSQL — 各アクションに対応する句のSQLが整形されて保持されることを検証
+func TestParseActionSQL(t *testing.T) {
+	ops, err := Parse("ALTER TABLE mydb.Users ADD COLUMN (a INT, b VARCHAR(10)), ADD INDEX idx_a (a), ENGINE=InnoDB ROW_FORMAT=DYNAMIC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := ops[0]
+	if op.TableRef != "`mydb`.`Users`" {
+		t.Errorf("テーブル名が元の大文字小文字で保持されること: got %q", op.TableRef)
+	}
+	if op.OriginalTable != "Users" || op.OriginalSchema != "mydb" || op.Table != "users" {
+		t.Errorf("元の表記のテーブル名・スキーマ名が保持されること: got %q %q", op.OriginalSchema, op.OriginalTable)
+	}
+	want := []string{
+		"ADD COLUMN `a` INT",
+		"ADD COLUMN `b` VARCHAR(10)",
+		"ADD INDEX `idx_a`(`a`)",
+		"ENGINE = InnoDB",
+		"ROW_FORMAT = DYNAMIC",
+	}
+	if len(op.Actions) != len(want) {
+		t.Fatalf("アクション数が%dであること: got %d", len(want), len(op.Actions))
+	}
+	for i, w := range want {
+		if op.Actions[i].SQL != w {
+			t.Errorf("アクション%dのSQLが%qであること: got %q", i, w, op.Actions[i].SQL)
+		}
+	}
+}
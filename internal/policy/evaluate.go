@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+// FindingSource はポリシー違反の検出事項の Source。
+const FindingSource = "policy"
+
+// FindingCode はポリシー違反の検出事項の Code。
+const FindingCode = "POLICY_VIOLATION"
+
+// Statement はポリシーの評価対象となるALTER文の予測結果。
+type Statement struct {
+	// Schema は対象テーブルのスキーマ（文で省略された場合は接続先のデータベース）
+	Schema    string
+	Operation meta.AlterOperation
+	// Verdict は文全体として実行されるアルゴリズム・ロック・リスク
+	Verdict   predictor.StatementVerdict
+	TableMeta *meta.TableMeta
+}
+
+// Evaluate は文に一致するルールごとにポリシー違反の検出事項を返す。
+// アルゴリズム・ロック・リスクは文全体の判定（MySQLが実際に実行する方法）で評価する。
+func (p *Policy) Evaluate(stmt Statement) []meta.Finding {
+	if p == nil {
+		return nil
+	}
+	var findings []meta.Finding
+	for _, r := range p.Rules {
+		if !r.matches(stmt) {
+			continue
+		}
+		findings = append(findings, meta.Finding{
+			Severity: r.Severity,
+			Code:     FindingCode,
+			Source:   FindingSource,
+			RuleID:   r.ID,
+			Message:  r.message(stmt),
+		})
+	}
+	return findings
+}
+
+func (r Rule) matches(stmt Statement) bool {
+	if len(r.Tables) > 0 && !slices.ContainsFunc(r.Tables, func(pattern string) bool {
+		return matchTable(pattern, stmt.Schema, stmt.Operation.Table)
+	}) {
+		return false
+	}
+	if len(r.Actions) > 0 && !slices.ContainsFunc(stmt.Operation.Actions, func(a meta.AlterAction) bool {
+		return slices.Contains(r.Actions, a.Type)
+	}) {
+		return false
+	}
+	if len(r.Algorithms) > 0 && !slices.Contains(r.Algorithms, stmt.Verdict.Algorithm) {
+		return false
+	}
+	if len(r.Locks) > 0 && !slices.Contains(r.Locks, stmt.Verdict.Lock) {
+		return false
+	}
+	if r.MinRisk != "" && riskRank(stmt.Verdict.RiskLevel) < riskRank(r.MinRisk) {
+		return false
+	}
+	if r.MinRows > 0 || r.MinSize > 0 {
+		// テーブル統計がない場合はサイズ条件を判定できないため一致としない
+		tm := stmt.TableMeta
+		if tm == nil || tm.RowCount < r.MinRows || Size(tm.DataLength+tm.IndexLength) < r.MinSize {
+			return false
+		}
+	}
+	return true
+}
+
+// matchTable はテーブルがグロブパターンに一致するかを返す（大文字小文字は区別しない）。
+// パターンに "." を含む場合は "schema.table"、含まない場合はテーブル名と照合する。
+func matchTable(pattern, schema, table string) bool {
+	pattern = strings.ToLower(pattern)
+	name := strings.ToLower(table)
+	if strings.Contains(pattern, ".") {
+		name = strings.ToLower(schema) + "." + name
+	}
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
+
+// message は違反内容のメッセージを作成する。
+func (r Rule) message(stmt Statement) string {
+	desc := r.Description
+	if desc == "" {
+		desc = "policy rule matched"
+	}
+	table := stmt.Operation.Table
+	if stmt.Schema != "" {
+		table = stmt.Schema + "." + table
+	}
+	return fmt.Sprintf("%s — %s runs with ALGORITHM=%s, LOCK=%s, risk %s", desc, table, stmt.Verdict.Algorithm, stmt.Verdict.Lock, stmt.Verdict.RiskLevel)
+}
+
+// HasViolation は検出事項に ERROR のポリシー違反が含まれるかを返す。
+func HasViolation(findings []meta.Finding) bool {
+	return slices.ContainsFunc(findings, func(f meta.Finding) bool {
+		return f.Source == FindingSource && f.Severity == meta.SeverityError
+	})
+}
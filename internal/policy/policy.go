@@ -0,0 +1,214 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+// DefaultFileName はカレントディレクトリから自動で読み込むポリシーファイル名。
+const DefaultFileName = ".ddl-lock-analyzer.yaml"
+
+// Policy はプロジェクトごとのDDLポリシーを表す。
+type Policy struct {
+	// RiskThresholds はリスクスコアの閾値（--risk-thresholds が優先）
+	RiskThresholds *predictor.RiskThresholds `yaml:"risk_thresholds"`
+	// FailOn はこのリスクレベル以上で失敗とする（--fail-on が優先）
+	FailOn meta.RiskLevel `yaml:"fail_on"`
+	Rules  []Rule         `yaml:"rules"`
+}
+
+// Rule は1つのポリシールールを表す。指定した条件をすべて満たすALTER文を違反とする。
+// リストの条件はいずれかに一致すればよく、省略した条件は常に一致する。
+type Rule struct {
+	ID          string `yaml:"id"`
+	Description string `yaml:"description"`
+	// Severity は違反の重要度。ERROR（デフォルト）の違反は終了コードに反映される
+	Severity meta.Severity `yaml:"severity"`
+	// Tables は "schema.table" または "table" 形式のグロブパターン
+	Tables     []string               `yaml:"tables"`
+	Actions    []meta.AlterActionType `yaml:"actions"`
+	Algorithms []meta.Algorithm       `yaml:"algorithms"`
+	Locks      []meta.LockLevel       `yaml:"locks"`
+	// MinRows, MinSize はテーブルの行数・サイズ（データ+インデックス）の下限
+	MinRows int64          `yaml:"min_rows"`
+	MinSize Size           `yaml:"min_size"`
+	MinRisk meta.RiskLevel `yaml:"min_risk"`
+}
+
+// Size はバイト数。YAMLでは数値または "500MB" のような単位付きの文字列で指定する。
+type Size int64
+
+var sizeRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMGT]?B?)$`)
+
+// UnmarshalYAML は単位付きのサイズを解析する。
+func (s *Size) UnmarshalYAML(node *yaml.Node) error {
+	v, err := ParseSize(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*s = v
+	return nil
+}
+
+// ParseSize は "1048576", "100MB", "1.5GB" のようなサイズ指定を解析する。
+func ParseSize(s string) (Size, error) {
+	m := sizeRegex.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, fmt.Errorf("invalid size %q (e.g. 1048576, 100MB, 1.5GB)", s)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	unit := map[string]int64{"": 1, "B": 1, "K": predictor.KB, "KB": predictor.KB, "M": predictor.MB, "MB": predictor.MB,
+		"G": predictor.GB, "GB": predictor.GB, "T": 1024 * predictor.GB, "TB": 1024 * predictor.GB}[m[2]]
+	return Size(n * float64(unit)), nil
+}
+
+// Find は dir 配下のデフォルトのポリシーファイルのパスを返す。存在しない場合は空文字を返す。
+func Find(dir string) (string, error) {
+	p := filepath.Join(dir, DefaultFileName)
+	if _, err := os.Stat(p); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to stat policy file: %w", err)
+	}
+	return p, nil
+}
+
+// LoadFile はポリシーファイルを読み込む。
+func LoadFile(p string) (*Policy, error) {
+	data, err := os.ReadFile(p) // #nosec G304 -- ユーザー指定のポリシーファイルを読む
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	pol, err := Load(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", p, err)
+	}
+	return pol, nil
+}
+
+// Load はYAMLのポリシーを読み込んで検証する。未知のキーはエラーにする。
+func Load(r io.Reader) (*Policy, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var p Policy
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if err := p.normalize(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// normalize は大文字小文字の表記ゆれを揃え、値を検証する。
+func (p *Policy) normalize() error {
+	if p.RiskThresholds != nil {
+		if err := p.RiskThresholds.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.FailOn != "" {
+		p.FailOn = meta.RiskLevel(strings.ToUpper(string(p.FailOn)))
+		if riskRank(p.FailOn) < 0 {
+			return fmt.Errorf("invalid fail_on %q (must be LOW, MEDIUM, HIGH or CRITICAL)", p.FailOn)
+		}
+	}
+
+	known := make(map[meta.AlterActionType]bool)
+	for _, t := range meta.AllActionTypes() {
+		known[t] = true
+	}
+	seen := make(map[string]bool)
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if r.ID == "" {
+			return fmt.Errorf("rules[%d]: id is required", i)
+		}
+		if seen[r.ID] {
+			return fmt.Errorf("rules[%d]: duplicate id %q", i, r.ID)
+		}
+		seen[r.ID] = true
+
+		r.Severity = meta.Severity(strings.ToUpper(string(r.Severity)))
+		switch r.Severity {
+		case "":
+			r.Severity = meta.SeverityError
+		case meta.SeverityError, meta.SeverityWarning, meta.SeverityInfo:
+		default:
+			return fmt.Errorf("rule %s: invalid severity %q (must be ERROR, WARNING or INFO)", r.ID, r.Severity)
+		}
+		for _, pattern := range r.Tables {
+			if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+				return fmt.Errorf("rule %s: invalid table pattern %q: %w", r.ID, pattern, err)
+			}
+		}
+		for j, a := range r.Actions {
+			a = meta.AlterActionType(strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(string(a))), " ", "_"))
+			if !known[a] {
+				return fmt.Errorf("rule %s: unknown action %q", r.ID, r.Actions[j])
+			}
+			r.Actions[j] = a
+		}
+		for j, a := range r.Algorithms {
+			a = meta.Algorithm(strings.ToUpper(string(a)))
+			switch a {
+			case meta.AlgorithmInstant, meta.AlgorithmInplace, meta.AlgorithmCopy:
+			default:
+				return fmt.Errorf("rule %s: invalid algorithm %q (must be INSTANT, INPLACE or COPY)", r.ID, r.Algorithms[j])
+			}
+			r.Algorithms[j] = a
+		}
+		for j, l := range r.Locks {
+			l = meta.LockLevel(strings.ToUpper(string(l)))
+			switch l {
+			case meta.LockNone, meta.LockShared, meta.LockExclusive:
+			default:
+				return fmt.Errorf("rule %s: invalid lock %q (must be NONE, SHARED or EXCLUSIVE)", r.ID, r.Locks[j])
+			}
+			r.Locks[j] = l
+		}
+		if r.MinRisk != "" {
+			r.MinRisk = meta.RiskLevel(strings.ToUpper(string(r.MinRisk)))
+			if riskRank(r.MinRisk) < 0 {
+				return fmt.Errorf("rule %s: invalid min_risk %q (must be LOW, MEDIUM, HIGH or CRITICAL)", r.ID, r.MinRisk)
+			}
+		}
+		if r.MinRows < 0 || r.MinSize < 0 {
+			return fmt.Errorf("rule %s: min_rows and min_size must not be negative", r.ID)
+		}
+	}
+	return nil
+}
+
+// riskRank はリスクレベルの順序を返す。未知の値は -1。
+func riskRank(r meta.RiskLevel) int {
+	switch r {
+	case meta.RiskLow:
+		return 0
+	case meta.RiskMedium:
+		return 1
+	case meta.RiskHigh:
+		return 2
+	case meta.RiskCritical:
+		return 3
+	default:
+		return -1
+	}
+}
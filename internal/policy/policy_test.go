@@ -0,0 +1,189 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+const testPolicy = `
+fail_on: high
+risk_thresholds:
+  medium: 10
+  high: 30
+  critical: 60
+rules:
+  - id: no-copy-large
+    description: No COPY on tables over 1M rows
+    algorithms: [copy]
+    min_rows: 1000000
+  - id: drop-column-approval
+    description: DROP COLUMN requires approval
+    severity: warning
+    actions: [drop_column]
+  - id: no-charset-orders
+    tables: ["*.orders"]
+    actions: [CONVERT_CHARACTER_SET]
+  - id: large-rebuild
+    tables: [users]
+    min_size: 10GB
+    min_risk: high
+`
+
+func TestLoad(t *testing.T) {
+	p, err := Load(strings.NewReader(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.FailOn != meta.RiskHigh {
+		t.Errorf("fail_onが大文字に正規化されること: got %s", p.FailOn)
+	}
+	if p.RiskThresholds == nil || *p.RiskThresholds != (predictor.RiskThresholds{Medium: 10, High: 30, Critical: 60}) {
+		t.Errorf("risk_thresholdsが読み込まれること: got %+v", p.RiskThresholds)
+	}
+	if len(p.Rules) != 4 {
+		t.Fatalf("ルール数が4であること: got %d", len(p.Rules))
+	}
+	if p.Rules[0].Severity != meta.SeverityError || p.Rules[0].Algorithms[0] != meta.AlgorithmCopy {
+		t.Errorf("severityのデフォルトがERRORでアルゴリズムが正規化されること: got %+v", p.Rules[0])
+	}
+	if p.Rules[1].Severity != meta.SeverityWarning || p.Rules[1].Actions[0] != meta.ActionDropColumn {
+		t.Errorf("severityとアクションが正規化されること: got %+v", p.Rules[1])
+	}
+	if p.Rules[3].MinSize != Size(10*predictor.GB) {
+		t.Errorf("min_sizeが単位付きで解析されること: got %d", p.Rules[3].MinSize)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"未知のキー", "rules:\n  - id: a\n    algoritm: [COPY]\n", "algoritm"},
+		{"IDなし", "rules:\n  - actions: [DROP_COLUMN]\n", "id is required"},
+		{"ID重複", "rules:\n  - id: a\n  - id: a\n", "duplicate id"},
+		{"未知のアクション", "rules:\n  - id: a\n    actions: [DROP_COLUMNS]\n", "unknown action"},
+		{"不正なアルゴリズム", "rules:\n  - id: a\n    algorithms: [FAST]\n", "invalid algorithm"},
+		{"不正なロック", "rules:\n  - id: a\n    locks: [WRITE]\n", "invalid lock"},
+		{"不正なseverity", "rules:\n  - id: a\n    severity: fatal\n", "invalid severity"},
+		{"不正なサイズ", "rules:\n  - id: a\n    min_size: big\n", "invalid size"},
+		{"不正なパターン", "rules:\n  - id: a\n    tables: [\"[orders\"]\n", "invalid table pattern"},
+		{"不正な閾値", "risk_thresholds: {medium: 50, high: 40, critical: 70}\n", "risk thresholds"},
+		{"不正なfail_on", "fail_on: severe\n", "invalid fail_on"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(strings.NewReader(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%q を含むエラーになること: got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadEmpty(t *testing.T) {
+	p, err := Load(strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Rules) != 0 || p.RiskThresholds != nil {
+		t.Errorf("空のポリシーになること: got %+v", p)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Size
+	}{
+		{"1048576", 1048576},
+		{"100MB", Size(100 * predictor.MB)},
+		{"1.5GB", Size(1.5 * float64(predictor.GB))},
+		{"2 tb", Size(2 * 1024 * predictor.GB)},
+		{"512k", Size(512 * predictor.KB)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.input)
+		if err != nil {
+			t.Errorf("ParseSize(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Load(strings.NewReader(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	large := &meta.TableMeta{RowCount: 5_000_000, DataLength: 20 * predictor.GB}
+	small := &meta.TableMeta{RowCount: 1000, DataLength: predictor.MB}
+	op := func(table string, types ...meta.AlterActionType) meta.AlterOperation {
+		o := meta.AlterOperation{Table: table}
+		for _, typ := range types {
+			o.Actions = append(o.Actions, meta.AlterAction{Type: typ})
+		}
+		return o
+	}
+	copyVerdict := predictor.StatementVerdict{Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared, RiskLevel: meta.RiskCritical}
+	instantVerdict := predictor.StatementVerdict{Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone, RiskLevel: meta.RiskLow}
+	rebuildVerdict := predictor.StatementVerdict{Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone, TableRebuild: true, RiskLevel: meta.RiskHigh}
+
+	tests := []struct {
+		name string
+		stmt Statement
+		want []string
+	}{
+		{"大きいテーブルのCOPY", Statement{Schema: "mydb", Operation: op("items", meta.ActionModifyColumn), Verdict: copyVerdict, TableMeta: large}, []string{"no-copy-large"}},
+		{"小さいテーブルのCOPY", Statement{Schema: "mydb", Operation: op("items", meta.ActionModifyColumn), Verdict: copyVerdict, TableMeta: small}, nil},
+		{"統計なしのCOPY", Statement{Schema: "mydb", Operation: op("items", meta.ActionModifyColumn), Verdict: copyVerdict}, nil},
+		{"DROP COLUMNを含む文", Statement{Schema: "mydb", Operation: op("items", meta.ActionAddColumn, meta.ActionDropColumn), Verdict: instantVerdict}, []string{"drop-column-approval"}},
+		{"ordersの文字セット変換", Statement{Schema: "shop", Operation: op("Orders", meta.ActionConvertCharset), Verdict: copyVerdict, TableMeta: small}, []string{"no-charset-orders"}},
+		{"orders以外の文字セット変換", Statement{Schema: "shop", Operation: op("order_items", meta.ActionConvertCharset), Verdict: copyVerdict, TableMeta: small}, nil},
+		{"usersの大きな再構築", Statement{Schema: "mydb", Operation: op("users", meta.ActionForceRebuild), Verdict: rebuildVerdict, TableMeta: large}, []string{"large-rebuild"}},
+		{"usersのINSTANT", Statement{Schema: "mydb", Operation: op("users", meta.ActionAddColumn), Verdict: instantVerdict, TableMeta: large}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := p.Evaluate(tt.stmt)
+			var got []string
+			for _, f := range findings {
+				if f.Source != FindingSource || f.Code != FindingCode {
+					t.Errorf("ポリシーの検出事項であること: got %+v", f)
+				}
+				got = append(got, f.RuleID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("違反ルールが %v であること: got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHasViolation(t *testing.T) {
+	findings := []meta.Finding{
+		{Severity: meta.SeverityError, Source: "clause"},
+		{Severity: meta.SeverityWarning, Source: FindingSource, RuleID: "a"},
+	}
+	if HasViolation(findings) {
+		t.Error("WARNINGのポリシー違反やポリシー以外のERRORは違反としないこと")
+	}
+	findings = append(findings, meta.Finding{Severity: meta.SeverityError, Source: FindingSource, RuleID: "b"})
+	if !HasViolation(findings) {
+		t.Error("ERRORのポリシー違反は違反とすること")
+	}
+}
+
+func TestEvaluateNilPolicy(t *testing.T) {
+	var p *Policy
+	if got := p.Evaluate(Statement{}); got != nil {
+		t.Errorf("ポリシーがない場合は検出事項なし: got %v", got)
+	}
+}
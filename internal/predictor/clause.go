@@ -0,0 +1,129 @@
+package predictor
+
+import (
+	"fmt"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// FindingSourceClause は ALGORITHM/LOCK 句の検証による検出事項の発生元。
+const FindingSourceClause = "clause"
+
+// ALGORITHM/LOCK 句の検証で報告する検出事項のコード。
+const (
+	CodeAlgorithmNotSupported = "ALGORITHM_NOT_SUPPORTED"
+	CodeLockNotSupported      = "LOCK_NOT_SUPPORTED"
+	CodeInstantWithLock       = "INSTANT_WITH_LOCK"
+	CodeLockDisablesInstant   = "LOCK_DISABLES_INSTANT"
+	CodeAlgorithmSlower       = "ALGORITHM_SLOWER_THAN_NEEDED"
+	CodeLockStricter          = "LOCK_STRICTER_THAN_NEEDED"
+)
+
+// MySQLのエラー番号。
+const (
+	// ER_ALTER_OPERATION_NOT_SUPPORTED
+	errAlterOperationNotSupported = 1845
+	// ER_ALTER_OPERATION_NOT_SUPPORTED_REASON
+	errAlterOperationNotSupportedReason = 1846
+)
+
+// CheckClauses は明示指定された ALGORITHM/LOCK 句を予測結果と比較する。
+// MySQLが文を拒否する指定はERROR、不要に遅いアルゴリズムや厳しすぎるロックの指定はWARNINGとして返す。
+func CheckClauses(op meta.AlterOperation, predictions []Prediction) []meta.Finding {
+	if len(predictions) == 0 {
+		return nil
+	}
+	reqAlg := op.RequestedAlgorithm
+	reqLock := op.RequestedLock
+	algSpecified := reqAlg != "" && reqAlg != meta.AlgorithmDefault
+	lockSpecified := reqLock != "" && reqLock != meta.LockDefault
+	if !algSpecified && !lockSpecified {
+		return nil
+	}
+
+	// 文全体として実行されるアルゴリズム・ロックと比較する
+	verdict := CombinePredictions(predictions)
+	alg, lock := verdict.Algorithm, verdict.Lock
+	algReason, lockReason := verdict.AlgorithmReason, verdict.LockReason
+
+	var findings []meta.Finding
+	add := func(sev meta.Severity, code string, errCode int, format string, args ...any) {
+		findings = append(findings, meta.Finding{
+			Severity:  sev,
+			Code:      code,
+			ErrorCode: errCode,
+			Source:    FindingSourceClause,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	// 実際に使われるアルゴリズム（明示指定が予測より重い場合は指定が優先される）
+	effective := alg
+	if algSpecified {
+		switch {
+		case algorithmOrd(reqAlg) < algorithmOrd(alg):
+			add(meta.SeverityError, CodeAlgorithmNotSupported, errAlterOperationNotSupported,
+				"ALGORITHM=%s is not supported for this operation (%s). Try ALGORITHM=%s.", reqAlg, algReason, alg)
+		case algorithmOrd(reqAlg) > algorithmOrd(alg):
+			add(meta.SeverityWarning, CodeAlgorithmSlower, 0,
+				"ALGORITHM=%s is slower than necessary — ALGORITHM=%s is supported for this statement", reqAlg, alg)
+			effective = reqAlg
+		}
+	}
+	if !lockSpecified {
+		return findings
+	}
+
+	if reqAlg == meta.AlgorithmInstant {
+		add(meta.SeverityError, CodeInstantWithLock, errAlterOperationNotSupportedReason,
+			"LOCK=%s is not supported with ALGORITHM=INSTANT. Only LOCK=DEFAULT is permitted.", reqLock)
+		return findings
+	}
+	if !algSpecified && alg == meta.AlgorithmInstant {
+		// LOCK句があるとINSTANTは選択されず、INPLACEで実行される
+		add(meta.SeverityWarning, CodeLockDisablesInstant, 0,
+			"LOCK=%s prevents ALGORITHM=INSTANT — MySQL falls back to INPLACE, which may rebuild the table. Remove the LOCK clause.", reqLock)
+		effective = meta.AlgorithmInplace
+	}
+
+	required := lock
+	if effective == meta.AlgorithmCopy && lockOrd(required) < lockOrd(meta.LockShared) {
+		// COPY は同時DMLを許可しない
+		required, lockReason = meta.LockShared, "ALGORITHM=COPY"
+	}
+	switch {
+	case lockOrd(reqLock) < lockOrd(required):
+		add(meta.SeverityError, CodeLockNotSupported, errAlterOperationNotSupportedReason,
+			"LOCK=%s is not supported. Reason: %s requires LOCK=%s. Try LOCK=%s.", reqLock, lockReason, required, required)
+	case lockOrd(reqLock) > lockOrd(required):
+		add(meta.SeverityWarning, CodeLockStricter, 0,
+			"LOCK=%s is stricter than necessary — LOCK=%s is sufficient for this statement", reqLock, required)
+	}
+	return findings
+}
+
+func algorithmOrd(a meta.Algorithm) int {
+	switch a {
+	case meta.AlgorithmInstant:
+		return 0
+	case meta.AlgorithmInplace:
+		return 1
+	case meta.AlgorithmCopy:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func lockOrd(l meta.LockLevel) int {
+	switch l {
+	case meta.LockNone:
+		return 0
+	case meta.LockShared:
+		return 1
+	case meta.LockExclusive:
+		return 2
+	default:
+		return 0
+	}
+}
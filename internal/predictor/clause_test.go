@@ -0,0 +1,65 @@
+package predictor
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// ============================================================
+// ALGORITHM/LOCK clause validation tests
+// MySQL公式ドキュメント:
+//   https://dev.mysql.com/doc/refman/8.0/en/alter-table.html#alter-table-performance
+//   https://dev.mysql.com/doc/refman/8.0/en/alter-table.html#alter-table-concurrency
+// ============================================================
+
+func TestCheckClauses(t *testing.T) {
+	instant := Prediction{Description: "ADD COLUMN", Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone}
+	inplace := Prediction{Description: "ADD INDEX", Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone}
+	copyPred := Prediction{Description: "MODIFY COLUMN (type change)", Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared}
+
+	tests := []struct {
+		name        string
+		alg         meta.Algorithm
+		lock        meta.LockLevel
+		predictions []Prediction
+		wantCodes   []string
+		wantErrCode int
+	}{
+		{"指定なし", "", "", []Prediction{copyPred}, nil, 0},
+		{"DEFAULT指定", meta.AlgorithmDefault, meta.LockDefault, []Prediction{copyPred}, nil, 0},
+		{"INSTANT指定でINSTANT可能", meta.AlgorithmInstant, "", []Prediction{instant}, nil, 0},
+		{"INSTANT指定で型変更", meta.AlgorithmInstant, "", []Prediction{copyPred}, []string{CodeAlgorithmNotSupported}, 1845},
+		{"INPLACE指定で文全体がCOPY", meta.AlgorithmInplace, "", []Prediction{inplace, copyPred}, []string{CodeAlgorithmNotSupported}, 1845},
+		{"LOCK=NONEでSHARED必須", "", meta.LockNone, []Prediction{copyPred}, []string{CodeLockNotSupported}, 1846},
+		{"INSTANTとLOCK=NONEの併用", meta.AlgorithmInstant, meta.LockNone, []Prediction{instant}, []string{CodeInstantWithLock}, 1846},
+		{"LOCK句でINSTANTが無効化", "", meta.LockNone, []Prediction{instant}, []string{CodeLockDisablesInstant}, 0},
+		{"厳しすぎるLOCK", "", meta.LockExclusive, []Prediction{inplace}, []string{CodeLockStricter}, 0},
+		{"COPY指定とLOCK=NONE", meta.AlgorithmCopy, meta.LockNone, []Prediction{inplace}, []string{CodeAlgorithmSlower, CodeLockNotSupported}, 1846},
+		{"必要十分なLOCK", meta.AlgorithmInplace, meta.LockNone, []Prediction{inplace}, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := meta.AlterOperation{RequestedAlgorithm: tt.alg, RequestedLock: tt.lock}
+			findings := CheckClauses(op, tt.predictions)
+			if len(findings) != len(tt.wantCodes) {
+				t.Fatalf("検出件数が%dであること: got %+v", len(tt.wantCodes), findings)
+			}
+			errCode := 0
+			for i, f := range findings {
+				if f.Code != tt.wantCodes[i] {
+					t.Errorf("コードが%sであること: got %s", tt.wantCodes[i], f.Code)
+				}
+				if f.Source != FindingSourceClause {
+					t.Errorf("Sourceがclauseであること: got %s", f.Source)
+				}
+				if f.Severity == meta.SeverityError {
+					errCode = f.ErrorCode
+				}
+			}
+			if errCode != tt.wantErrCode {
+				t.Errorf("MySQLエラー番号が%dであること: got %d", tt.wantErrCode, errCode)
+			}
+		})
+	}
+}
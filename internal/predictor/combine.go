@@ -0,0 +1,146 @@
+package predictor
+
+import (
+	"fmt"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// StatementVerdict は1つのALTER文全体として実行されるアルゴリズム・ロックを表す。
+// MySQLは文中の全アクションを単一のアルゴリズムで実行するため、
+// アクション単位の予測とは異なる場合がある。
+type StatementVerdict struct {
+	Algorithm       meta.Algorithm `json:"algorithm"`
+	Lock            meta.LockLevel `json:"lock_level"`
+	TableRebuild    bool           `json:"table_rebuild"`
+	RiskLevel       meta.RiskLevel `json:"risk_level"`
+	RiskScore       *RiskScore     `json:"risk_score,omitempty"`
+	AlgorithmReason string         `json:"algorithm_reason,omitempty"`
+	LockReason      string         `json:"lock_reason,omitempty"`
+	// Permitted は文全体に指定できるアルゴリズムと、それぞれで指定できる最も弱いロック
+	Permitted []AlgorithmOption `json:"permitted,omitempty"`
+	Notes     []string          `json:"notes,omitempty"`
+}
+
+// CombinePredictions はアクション単位の予測を文全体の判定に統合する。
+// 最も重いアルゴリズム・ロックを採用し、以下の特殊ケースを考慮する:
+//   - DROP PRIMARY KEY と ADD PRIMARY KEY を同じ文で行う場合は INPLACE で実行できる
+//   - INSTANT の ADD/DROP COLUMN は INPLACE の操作と組み合わせるとテーブル再構築になる（VIRTUAL 生成カラムを除く）
+//   - COPY で実行される文は同時DMLを許可しない（LOCK=SHARED 以上）
+func CombinePredictions(predictions []Prediction) StatementVerdict {
+	if len(predictions) == 0 {
+		return StatementVerdict{}
+	}
+	preds, notes := applyPrimaryKeyReplacement(predictions)
+
+	v := StatementVerdict{Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone, Permitted: combinePermitted(preds), Notes: notes}
+	risk := meta.RiskLow
+	for _, p := range preds {
+		if v.AlgorithmReason == "" || algorithmOrd(p.Algorithm) > algorithmOrd(v.Algorithm) {
+			v.Algorithm, v.AlgorithmReason = p.Algorithm, p.Description
+		}
+		if v.LockReason == "" || lockOrd(p.Lock) > lockOrd(v.Lock) {
+			v.Lock, v.LockReason = p.Lock, p.Description
+		}
+		if p.TableRebuild {
+			v.TableRebuild = true
+		}
+		if riskOrd(p.RiskLevel) > riskOrd(risk) {
+			risk = p.RiskLevel
+		}
+	}
+
+	switch v.Algorithm {
+	case meta.AlgorithmInplace:
+		// INSTANT 扱いだったカラム追加・削除は INPLACE ではテーブル再構築を伴う
+		// VIRTUAL 生成カラムは INPLACE でも再構築せずに追加・削除できる
+		for _, p := range preds {
+			if p.Algorithm != meta.AlgorithmInstant || p.GeneratedType == "VIRTUAL" {
+				continue
+			}
+			if p.ActionType == meta.ActionAddColumn || p.ActionType == meta.ActionDropColumn {
+				v.TableRebuild = true
+				v.Notes = append(v.Notes, fmt.Sprintf("%s cannot use INSTANT together with %s — the table is rebuilt", p.Description, v.AlgorithmReason))
+			}
+		}
+	case meta.AlgorithmCopy:
+		v.TableRebuild = true
+		if lockOrd(v.Lock) < lockOrd(meta.LockShared) {
+			v.Lock, v.LockReason = meta.LockShared, "ALGORITHM=COPY"
+		}
+	}
+
+	if v.RiskScore = combineRiskScore(v, preds); v.RiskScore != nil {
+		if r := v.RiskScore.Level(); riskOrd(r) > riskOrd(risk) {
+			risk = r
+		}
+	} else if r := calculateRisk(v.Algorithm, v.Lock, v.TableRebuild); riskOrd(r) > riskOrd(risk) {
+		risk = r
+	}
+	v.RiskLevel = risk
+	return v
+}
+
+// combineRiskScore は文全体のアルゴリズム・ロック・再構築でリスクスコアを再計算する。
+// テーブル統計による要因は最も大きいアクションのものを使う。スコアのない予測のみの場合は nil を返す。
+func combineRiskScore(v StatementVerdict, preds []Prediction) *RiskScore {
+	var base *RiskScore
+	touches := v.Algorithm == meta.AlgorithmCopy || (v.Algorithm == meta.AlgorithmInplace && v.TableRebuild)
+	for _, p := range preds {
+		if p.RiskScore != nil && (base == nil || p.RiskScore.sizePoints() > base.sizePoints()) {
+			base = p.RiskScore
+		}
+		if v.Algorithm != meta.AlgorithmInstant && isIndexBuild(p.ActionType) {
+			touches = true
+		}
+	}
+	if base == nil {
+		return nil
+	}
+	score := *base
+	score.build(v.Algorithm, v.Lock, v.TableRebuild, touches)
+	return &score
+}
+
+// applyPrimaryKeyReplacement は同一文内の DROP PRIMARY KEY + ADD PRIMARY KEY を
+// INPLACE の主キー置き換えとして扱った予測を返す。
+func applyPrimaryKeyReplacement(predictions []Prediction) ([]Prediction, []string) {
+	dropIdx, addIdx := -1, -1
+	for i, p := range predictions {
+		switch p.ActionType {
+		case meta.ActionDropPrimaryKey:
+			dropIdx = i
+		case meta.ActionAddPrimaryKey:
+			addIdx = i
+		}
+	}
+	// 非InnoDBなど ADD PRIMARY KEY 自体が COPY の場合は置き換えても変わらない
+	if dropIdx < 0 || addIdx < 0 || predictions[addIdx].Algorithm == meta.AlgorithmCopy {
+		return predictions, nil
+	}
+	preds := append([]Prediction(nil), predictions...)
+	drop := preds[dropIdx]
+	drop.Description = "DROP PRIMARY KEY + ADD PRIMARY KEY"
+	drop.Algorithm = predictions[addIdx].Algorithm
+	drop.Lock = predictions[addIdx].Lock
+	drop.TableRebuild = true
+	drop.Permitted = predictions[addIdx].Permitted
+	rescore(&drop)
+	preds[dropIdx] = drop
+	return preds, []string{"DROP PRIMARY KEY combined with ADD PRIMARY KEY runs as INPLACE (primary key replacement)"}
+}
+
+func riskOrd(r meta.RiskLevel) int {
+	switch r {
+	case meta.RiskLow:
+		return 0
+	case meta.RiskMedium:
+		return 1
+	case meta.RiskHigh:
+		return 2
+	case meta.RiskCritical:
+		return 3
+	default:
+		return 0
+	}
+}
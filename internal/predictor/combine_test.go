@@ -0,0 +1,85 @@
+package predictor
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// ============================================================
+// Statement-level verdict tests
+// MySQL公式ドキュメント:
+//   https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-primary-key-operations
+// ============================================================
+
+func TestCombinePredictions(t *testing.T) {
+	p := New()
+	tm := &meta.TableMeta{
+		Schema: "mydb",
+		Table:  "users",
+		Engine: "InnoDB",
+		Columns: []meta.ColumnMeta{
+			{Name: "id", DataType: "int", ColumnType: "int"},
+			{Name: "y", DataType: "int", ColumnType: "int", IsNullable: true},
+		},
+	}
+	addCol := meta.AlterAction{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "x", ColumnType: "INT", IsNullable: boolPtr(true)}}
+	renameCol := meta.AlterAction{Type: meta.ActionRenameColumn, Detail: meta.ActionDetail{OldColumnName: "y", ColumnName: "z"}}
+	modifyType := meta.AlterAction{Type: meta.ActionModifyColumn, Detail: meta.ActionDetail{ColumnName: "y", ColumnType: "BIGINT", IsNullable: boolPtr(true)}}
+	addIndex := meta.AlterAction{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "idx_y", IndexColumns: []string{"y"}}}
+	addVirtual := meta.AlterAction{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "v", ColumnType: "INT", IsNullable: boolPtr(true), GeneratedType: "VIRTUAL"}}
+	dropPK := meta.AlterAction{Type: meta.ActionDropPrimaryKey}
+	addPK := meta.AlterAction{Type: meta.ActionAddPrimaryKey, Detail: meta.ActionDetail{IndexColumns: []string{"id", "y"}}}
+
+	tests := []struct {
+		name        string
+		actions     []meta.AlterAction
+		wantAlg     meta.Algorithm
+		wantLock    meta.LockLevel
+		wantRebuild bool
+		wantRisk    meta.RiskLevel
+	}{
+		{"INSTANTのみ", []meta.AlterAction{addCol, renameCol}, meta.AlgorithmInstant, meta.LockNone, false, meta.RiskLow},
+		{"INSTANTと型変更はCOPY", []meta.AlterAction{addCol, modifyType}, meta.AlgorithmCopy, meta.LockShared, true, meta.RiskCritical},
+		{"ADD COLUMNとADD INDEXは再構築", []meta.AlterAction{addCol, addIndex}, meta.AlgorithmInplace, meta.LockNone, true, meta.RiskHigh},
+		{"VIRTUAL生成カラムの追加とADD INDEXは再構築なし", []meta.AlterAction{addVirtual, addIndex}, meta.AlgorithmInplace, meta.LockNone, false, meta.RiskMedium},
+		{"RENAME COLUMNとADD INDEXは再構築なし", []meta.AlterAction{renameCol, addIndex}, meta.AlgorithmInplace, meta.LockNone, false, meta.RiskMedium},
+		{"主キーの置き換えはINPLACE", []meta.AlterAction{dropPK, addPK}, meta.AlgorithmInplace, meta.LockNone, true, meta.RiskHigh},
+		{"DROP PRIMARY KEY単独はCOPY", []meta.AlterAction{dropPK}, meta.AlgorithmCopy, meta.LockShared, true, meta.RiskCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := CombinePredictions(p.PredictAll(meta.AlterOperation{Table: "users", Actions: tt.actions}, tm))
+			if v.Algorithm != tt.wantAlg {
+				t.Errorf("アルゴリズムが%sであること: got %s (%s)", tt.wantAlg, v.Algorithm, v.AlgorithmReason)
+			}
+			if v.Lock != tt.wantLock {
+				t.Errorf("ロックが%sであること: got %s", tt.wantLock, v.Lock)
+			}
+			if v.TableRebuild != tt.wantRebuild {
+				t.Errorf("テーブル再構築が%vであること: got %v", tt.wantRebuild, v.TableRebuild)
+			}
+			if v.RiskLevel != tt.wantRisk {
+				t.Errorf("リスクレベルが%sであること: got %s", tt.wantRisk, v.RiskLevel)
+			}
+		})
+	}
+}
+
+func TestCombinePredictionsEmpty(t *testing.T) {
+	if v := CombinePredictions(nil); v.Algorithm != "" {
+		t.Errorf("予測がない場合はゼロ値であること: got %+v", v)
+	}
+}
+
+func TestCheckClausesPrimaryKeyReplacement(t *testing.T) {
+	// 主キーの置き換えではALGORITHM=INPLACEが受け付けられることを検証
+	preds := New().PredictAll(meta.AlterOperation{Actions: []meta.AlterAction{
+		{Type: meta.ActionDropPrimaryKey},
+		{Type: meta.ActionAddPrimaryKey, Detail: meta.ActionDetail{IndexColumns: []string{"id"}}},
+	}}, nil)
+	op := meta.AlterOperation{RequestedAlgorithm: meta.AlgorithmInplace, RequestedLock: meta.LockNone}
+	if findings := CheckClauses(op, preds); len(findings) != 0 {
+		t.Errorf("検出事項がないこと: got %+v", findings)
+	}
+}
@@ -0,0 +1,326 @@
+package predictor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// CustomRuleMode はユーザー定義ルールとデフォルトルールの組み合わせ方を表す。
+type CustomRuleMode string
+
+const (
+	// CustomRulesPrepend はユーザー定義ルールをデフォルトルールより先に評価する。
+	CustomRulesPrepend CustomRuleMode = "prepend"
+	// CustomRulesReplace はデフォルトルールを使わずユーザー定義ルールのみで判定する。
+	CustomRulesReplace CustomRuleMode = "replace"
+)
+
+// customRuleSource はファイルパスなしで読み込んだユーザー定義ルールの Source。
+const customRuleSource = "custom"
+
+// CustomRuleFile はユーザー定義の判定ルールファイル（YAML または JSON）を表す。
+type CustomRuleFile struct {
+	// Mode は prepend（デフォルト）または replace
+	Mode  CustomRuleMode `yaml:"mode"`
+	Rules []CustomRule   `yaml:"rules"`
+	// Path は読み込み元のファイルパス（予測の Source に使う）
+	Path string `yaml:"-"`
+}
+
+// CustomRule は宣言的な条件を持つユーザー定義の判定ルール。
+// 記述順に評価し、最初に条件をすべて満たしたルールの結果を使う。
+type CustomRule struct {
+	ID           string               `yaml:"id"`
+	Description  string               `yaml:"description"`
+	Action       meta.AlterActionType `yaml:"action"`
+	When         CustomCondition      `yaml:"when"`
+	Algorithm    meta.Algorithm       `yaml:"algorithm"`
+	Lock         meta.LockLevel       `yaml:"lock"`
+	TableRebuild bool                 `yaml:"table_rebuild"`
+	Notes        []string             `yaml:"notes"`
+	Warnings     []string             `yaml:"warnings"`
+}
+
+// CustomCondition はユーザー定義ルールの条件。省略した条件は常に一致し、リストはいずれかに一致すればよい。
+type CustomCondition struct {
+	// ColumnTypes は変更後のカラム型のグロブパターン（例: "varchar(*)", "enum*"、大文字小文字を区別しない）
+	ColumnTypes []string `yaml:"column_types"`
+	// OldColumnTypes はメタデータ上の現在のカラム型のグロブパターン。カラムが見つからない場合は一致しない
+	OldColumnTypes []string `yaml:"old_column_types"`
+	// Nullable は変更後のカラムが NULL を許可するか
+	Nullable *bool `yaml:"nullable"`
+	// Position は trailing（位置指定なし）, first, after, any のいずれか
+	Position string `yaml:"position"`
+	// Engines はテーブルのストレージエンジン。テーブル情報がない場合は一致しない
+	Engines     []string `yaml:"engines"`
+	Partitioned *bool    `yaml:"partitioned"`
+	// MinVersion 以上 MaxVersion 未満のMySQLバージョンにのみ適用する
+	MinVersion string `yaml:"min_version"`
+	MaxVersion string `yaml:"max_version"`
+}
+
+// LoadCustomRulesFile はユーザー定義ルールファイルを読み込む。
+func LoadCustomRulesFile(p string) (*CustomRuleFile, error) {
+	data, err := os.ReadFile(p) // #nosec G304 -- ユーザー指定のルールファイルを読む
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	f, err := LoadCustomRules(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", p, err)
+	}
+	f.Path = p
+	return f, nil
+}
+
+// LoadCustomRules はYAML（JSONを含む）のユーザー定義ルールを読み込んで検証する。未知のキーはエラーにする。
+func LoadCustomRules(r io.Reader) (*CustomRuleFile, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var f CustomRuleFile
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if err := f.normalize(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// normalize は大文字小文字の表記ゆれを揃え、値を検証する。
+func (f *CustomRuleFile) normalize() error {
+	f.Mode = CustomRuleMode(strings.ToLower(string(f.Mode)))
+	switch f.Mode {
+	case "":
+		f.Mode = CustomRulesPrepend
+	case CustomRulesPrepend, CustomRulesReplace:
+	default:
+		return fmt.Errorf("invalid mode %q (must be prepend or replace)", f.Mode)
+	}
+
+	known := make(map[meta.AlterActionType]bool)
+	for _, t := range meta.AllActionTypes() {
+		known[t] = true
+	}
+	seen := make(map[string]bool)
+	for i := range f.Rules {
+		r := &f.Rules[i]
+		if r.ID == "" {
+			return fmt.Errorf("rules[%d]: id is required", i)
+		}
+		if seen[r.ID] {
+			return fmt.Errorf("rules[%d]: duplicate id %q", i, r.ID)
+		}
+		seen[r.ID] = true
+
+		action := meta.AlterActionType(strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(string(r.Action))), " ", "_"))
+		if !known[action] {
+			return fmt.Errorf("rule %s: unknown action %q", r.ID, r.Action)
+		}
+		r.Action = action
+
+		r.Algorithm = meta.Algorithm(strings.ToUpper(string(r.Algorithm)))
+		switch r.Algorithm {
+		case meta.AlgorithmInstant, meta.AlgorithmInplace, meta.AlgorithmCopy:
+		default:
+			return fmt.Errorf("rule %s: invalid algorithm %q (must be INSTANT, INPLACE or COPY)", r.ID, r.Algorithm)
+		}
+		r.Lock = meta.LockLevel(strings.ToUpper(string(r.Lock)))
+		switch r.Lock {
+		case meta.LockNone, meta.LockShared, meta.LockExclusive:
+		default:
+			return fmt.Errorf("rule %s: invalid lock %q (must be NONE, SHARED or EXCLUSIVE)", r.ID, r.Lock)
+		}
+
+		if err := r.When.normalize(); err != nil {
+			return fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+		if r.Description == "" {
+			r.Description = string(r.Action) + " (" + r.ID + ")"
+		}
+	}
+	return nil
+}
+
+func (c *CustomCondition) normalize() error {
+	for _, patterns := range [][]string{c.ColumnTypes, c.OldColumnTypes} {
+		for j, pattern := range patterns {
+			patterns[j] = strings.ToLower(strings.TrimSpace(pattern))
+			if _, err := path.Match(patterns[j], ""); err != nil {
+				return fmt.Errorf("invalid column type pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	c.Position = strings.ToLower(c.Position)
+	switch c.Position {
+	case "", "any", "trailing", "first", "after":
+	default:
+		return fmt.Errorf("invalid position %q (must be trailing, first, after or any)", c.Position)
+	}
+	for _, v := range []string{c.MinVersion, c.MaxVersion} {
+		if v == "" {
+			continue
+		}
+		if _, err := ParseVersion(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// source は予測の Source に表示する読み込み元を返す。
+func (f *CustomRuleFile) source() string {
+	if f.Path == "" {
+		return customRuleSource
+	}
+	return f.Path
+}
+
+// predictionRules はユーザー定義ルールを PredictionRule に変換する。
+func (f *CustomRuleFile) predictionRules() []PredictionRule {
+	rules := make([]PredictionRule, 0, len(f.Rules))
+	for _, r := range f.Rules {
+		cond := r.When
+		rules = append(rules, PredictionRule{
+			ID:           r.ID,
+			Source:       f.source(),
+			ActionType:   r.Action,
+			Description:  r.Description,
+			When:         cond.describe(),
+			Condition:    cond.matches,
+			Algorithm:    r.Algorithm,
+			Lock:         r.Lock,
+			TableRebuild: r.TableRebuild,
+			Notes:        r.Notes,
+			Warnings:     r.Warnings,
+			MinVersion:   cond.MinVersion,
+			MaxVersion:   cond.MaxVersion,
+		})
+	}
+	return rules
+}
+
+// matches はアクションとテーブルが条件をすべて満たすかを判定する。バージョンは appliesTo で判定する。
+func (c CustomCondition) matches(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
+	if len(c.ColumnTypes) > 0 && !matchAnyGlob(c.ColumnTypes, a.Detail.ColumnType) {
+		return false, fmt.Sprintf("column type %s does not match %s", a.Detail.ColumnType, strings.Join(c.ColumnTypes, "|"))
+	}
+	if len(c.OldColumnTypes) > 0 {
+		name := a.Detail.OldColumnName
+		if name == "" {
+			name = a.Detail.ColumnName
+		}
+		col := findColumn(tm, name)
+		if col == nil {
+			return columnNotFound(name)
+		}
+		if !matchAnyGlob(c.OldColumnTypes, col.ColumnType) {
+			return false, fmt.Sprintf("current column type %s does not match %s", col.ColumnType, strings.Join(c.OldColumnTypes, "|"))
+		}
+	}
+	if c.Nullable != nil && isNullablePtr(a.Detail.IsNullable) != *c.Nullable {
+		return false, "column is " + nullLabel(isNullablePtr(a.Detail.IsNullable))
+	}
+	if !matchPosition(c.Position, a.Detail.Position) {
+		pos := a.Detail.Position
+		if pos == "" {
+			pos = "trailing"
+		}
+		return false, "position " + pos + " is not " + c.Position
+	}
+	if len(c.Engines) > 0 {
+		if tm == nil {
+			return noTableMeta()
+		}
+		if !containsFold(c.Engines, tm.Engine) {
+			return false, fmt.Sprintf("engine %s is not %s", tm.Engine, strings.Join(c.Engines, "|"))
+		}
+	}
+	if c.Partitioned != nil {
+		if tm == nil {
+			return noTableMeta()
+		}
+		if tm.IsPartitioned != *c.Partitioned {
+			return false, fmt.Sprintf("table partitioned=%t", tm.IsPartitioned)
+		}
+	}
+	if d := c.describe(); d != "" {
+		return true, d
+	}
+	return true, "no condition"
+}
+
+// describe は rules サブコマンドで表示する条件の説明を返す。バージョン範囲は別に表示するため含めない。
+func (c CustomCondition) describe() string {
+	var parts []string
+	if len(c.ColumnTypes) > 0 {
+		parts = append(parts, "column type "+strings.Join(c.ColumnTypes, "|"))
+	}
+	if len(c.OldColumnTypes) > 0 {
+		parts = append(parts, "current column type "+strings.Join(c.OldColumnTypes, "|"))
+	}
+	if c.Nullable != nil {
+		if *c.Nullable {
+			parts = append(parts, "nullable")
+		} else {
+			parts = append(parts, "NOT NULL")
+		}
+	}
+	if c.Position != "" && c.Position != "any" {
+		parts = append(parts, "position "+c.Position)
+	}
+	if len(c.Engines) > 0 {
+		parts = append(parts, "engine "+strings.Join(c.Engines, "|"))
+	}
+	if c.Partitioned != nil {
+		if *c.Partitioned {
+			parts = append(parts, "partitioned table")
+		} else {
+			parts = append(parts, "non-partitioned table")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// matchPosition は "FIRST" / "AFTER <col>" / "" の位置指定が条件に一致するかを判定する。
+func matchPosition(cond, position string) bool {
+	upper := strings.ToUpper(position)
+	switch cond {
+	case "trailing":
+		return position == ""
+	case "first":
+		return upper == "FIRST"
+	case "after":
+		return strings.HasPrefix(upper, "AFTER")
+	default:
+		return true
+	}
+}
+
+func matchAnyGlob(patterns []string, s string) bool {
+	s = strings.ToLower(s)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package predictor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+const testCustomRules = `
+rules:
+  - id: fork-varchar-instant
+    description: MODIFY COLUMN (VARCHAR widen, fork)
+    action: modify_column
+    when:
+      column_types: ["varchar(*)"]
+      old_column_types: ["VARCHAR(*)"]
+      min_version: "8.0.30"
+    algorithm: instant
+    lock: none
+    notes: ["Our fork widens VARCHAR in place"]
+  - id: rocksdb-add-column
+    action: ADD_COLUMN
+    when:
+      engines: [RocksDB]
+      position: trailing
+      nullable: true
+    algorithm: INPLACE
+    lock: NONE
+`
+
+func usersVarcharMeta() *meta.TableMeta {
+	return &meta.TableMeta{
+		Schema: "mydb",
+		Table:  "users",
+		Engine: "InnoDB",
+		Columns: []meta.ColumnMeta{
+			{Name: "name", ColumnType: "varchar(200)", IsNullable: true},
+		},
+	}
+}
+
+func TestLoadCustomRules(t *testing.T) {
+	f, err := LoadCustomRules(strings.NewReader(testCustomRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Mode != CustomRulesPrepend {
+		t.Errorf("modeのデフォルトがprependであること: got %s", f.Mode)
+	}
+	r := f.Rules[0]
+	if r.Action != meta.ActionModifyColumn || r.Algorithm != meta.AlgorithmInstant || r.Lock != meta.LockNone {
+		t.Errorf("アクション・アルゴリズム・ロックが正規化されること: got %+v", r)
+	}
+	if r.When.OldColumnTypes[0] != "varchar(*)" {
+		t.Errorf("カラム型のパターンが小文字に正規化されること: got %s", r.When.OldColumnTypes[0])
+	}
+	if f.Rules[1].Description != "ADD_COLUMN (rocksdb-add-column)" {
+		t.Errorf("descriptionのデフォルトがアクションとIDになること: got %s", f.Rules[1].Description)
+	}
+}
+
+func TestLoadCustomRulesJSON(t *testing.T) {
+	f, err := LoadCustomRules(strings.NewReader(`{"mode": "replace", "rules": [{"id": "a", "action": "DROP_INDEX", "algorithm": "INSTANT", "lock": "NONE"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Mode != CustomRulesReplace || len(f.Rules) != 1 {
+		t.Errorf("JSONのルールファイルを読み込めること: got %+v", f)
+	}
+}
+
+func TestLoadCustomRulesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"id未指定", "rules: [{action: ADD_COLUMN, algorithm: INSTANT, lock: NONE}]", "id is required"},
+		{"未知のアクション", "rules: [{id: a, action: ADD_THING, algorithm: INSTANT, lock: NONE}]", "unknown action"},
+		{"不正なアルゴリズム", "rules: [{id: a, action: ADD_COLUMN, algorithm: FAST, lock: NONE}]", "invalid algorithm"},
+		{"ロック未指定", "rules: [{id: a, action: ADD_COLUMN, algorithm: INSTANT}]", "invalid lock"},
+		{"不正な位置", "rules: [{id: a, action: ADD_COLUMN, algorithm: INSTANT, lock: NONE, when: {position: middle}}]", "invalid position"},
+		{"不正なバージョン", "rules: [{id: a, action: ADD_COLUMN, algorithm: INSTANT, lock: NONE, when: {min_version: latest}}]", "invalid MySQL version"},
+		{"不正なモード", "mode: append", "invalid mode"},
+		{"未知のキー", "rules: [{id: a, action: ADD_COLUMN, algorithm: INSTANT, lock: NONE, when: {charset: utf8}}]", "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCustomRules(strings.NewReader(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("%q を含むエラーになること: got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestPredictCustomRulePrepend(t *testing.T) {
+	f, err := LoadCustomRules(strings.NewReader(testCustomRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Path = "rules.yaml"
+	action := meta.AlterAction{
+		Type:   meta.ActionModifyColumn,
+		Detail: meta.ActionDetail{ColumnName: "name", ColumnType: "VARCHAR(300)", IsNullable: boolPtr(true)},
+	}
+
+	pred := New(WithCustomRules(f), WithMySQLVersion("8.0.35")).Predict(action, usersVarcharMeta())
+	if pred.Algorithm != meta.AlgorithmInstant || pred.RuleID != "fork-varchar-instant" || pred.RuleSource != "rules.yaml" {
+		t.Errorf("カスタムルールがデフォルトルールより優先されること: got %s %s (%s)", pred.Algorithm, pred.RuleID, pred.RuleSource)
+	}
+
+	// バージョン範囲外ではデフォルトルール（255境界をまたぐ型変更）にフォールバックする
+	pred = New(WithCustomRules(f), WithMySQLVersion("8.0.28")).Predict(action, usersVarcharMeta())
+	if pred.Algorithm != meta.AlgorithmCopy || pred.RuleSource != "" {
+		t.Errorf("バージョン範囲外ではデフォルトルールを使うこと: got %s (%s)", pred.Algorithm, pred.RuleSource)
+	}
+}
+
+func TestPredictCustomRuleEngine(t *testing.T) {
+	f, err := LoadCustomRules(strings.NewReader(testCustomRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := usersVarcharMeta()
+	tm.Engine = "RocksDB"
+	p := New(WithCustomRules(f))
+
+	pred := p.Predict(meta.AlterAction{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "a", ColumnType: "INT"}}, tm)
+	if pred.Algorithm != meta.AlgorithmInplace || pred.RuleID != "rocksdb-add-column" {
+		t.Errorf("非InnoDBの判定よりカスタムルールが優先されること: got %s %s", pred.Algorithm, pred.RuleID)
+	}
+	if pred.RuleSource != customRuleSource {
+		t.Errorf("ファイルパスがない場合のSourceがcustomであること: got %s", pred.RuleSource)
+	}
+
+	pred = p.Predict(meta.AlterAction{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "a", ColumnType: "INT", Position: "FIRST"}}, tm)
+	if pred.Algorithm != meta.AlgorithmCopy || pred.RuleID != RuleIDNonInnoDB {
+		t.Errorf("位置の条件に一致しない場合は非InnoDBとして判定すること: got %s %s", pred.Algorithm, pred.RuleID)
+	}
+}
+
+func TestPredictCustomRuleReplace(t *testing.T) {
+	f, err := LoadCustomRules(strings.NewReader("mode: replace\n" + testCustomRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pred := New(WithCustomRules(f)).Predict(meta.AlterAction{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx"}}, nil)
+	if pred.RuleID != RuleIDUnknown {
+		t.Errorf("replaceモードではデフォルトルールを使わないこと: got %s", pred.RuleID)
+	}
+}
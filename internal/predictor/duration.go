@@ -2,6 +2,7 @@ package predictor
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
 )
@@ -34,10 +35,140 @@ func CollectTableInfo(tableMeta *meta.TableMeta) TableInfo {
 func formatTableInfo(info TableInfo) string {
 	return fmt.Sprintf("rows: ~%s, data: %s, indexes: %d",
 		formatCount(info.RowCount),
-		formatSize(info.DataSize+info.IndexSize),
+		FormatSize(info.DataSize+info.IndexSize),
 		info.IndexCount)
 }
 
+// DurationSettings は推定実行時間の算出に使うスループット係数を保持する。
+// 実環境のディスク・CPU性能に合わせて調整できる。
+type DurationSettings struct {
+	// RebuildBytesPerSec は INPLACE でテーブルを再構築する際のデータ処理速度（バイト/秒）
+	RebuildBytesPerSec float64 `json:"rebuild_bytes_per_sec"`
+	// CopyBytesPerSec は ALGORITHM=COPY でテーブルをコピーする際のデータ処理速度（バイト/秒）
+	CopyBytesPerSec float64 `json:"copy_bytes_per_sec"`
+	// IndexScanBytesPerSec はインデックス作成時にテーブルを走査する速度（バイト/秒）
+	IndexScanBytesPerSec float64 `json:"index_scan_bytes_per_sec"`
+	// IndexRowsPerSec は1つのセカンダリインデックスを構築する際の行処理速度（行/秒）
+	IndexRowsPerSec float64 `json:"index_rows_per_sec"`
+	// MinFactor, MaxFactor は推定値に掛けてレンジの下限・上限とする係数
+	MinFactor float64 `json:"min_factor"`
+	MaxFactor float64 `json:"max_factor"`
+}
+
+// DefaultDurationSettings は一般的なSSD環境を想定した係数を返す。
+func DefaultDurationSettings() DurationSettings {
+	return DurationSettings{
+		RebuildBytesPerSec:   float64(50 * MB),
+		CopyBytesPerSec:      float64(20 * MB),
+		IndexScanBytesPerSec: float64(100 * MB),
+		IndexRowsPerSec:      500_000,
+		MinFactor:            0.5,
+		MaxFactor:            2.0,
+	}
+}
+
+// withDefaults は未設定（0以下）の係数をデフォルト値で補完する。
+func (s DurationSettings) withDefaults() DurationSettings {
+	d := DefaultDurationSettings()
+	if s.RebuildBytesPerSec <= 0 {
+		s.RebuildBytesPerSec = d.RebuildBytesPerSec
+	}
+	if s.CopyBytesPerSec <= 0 {
+		s.CopyBytesPerSec = d.CopyBytesPerSec
+	}
+	if s.IndexScanBytesPerSec <= 0 {
+		s.IndexScanBytesPerSec = d.IndexScanBytesPerSec
+	}
+	if s.IndexRowsPerSec <= 0 {
+		s.IndexRowsPerSec = d.IndexRowsPerSec
+	}
+	if s.MinFactor <= 0 {
+		s.MinFactor = d.MinFactor
+	}
+	if s.MaxFactor <= 0 {
+		s.MaxFactor = d.MaxFactor
+	}
+	return s
+}
+
+// DurationEstimate は推定実行時間のレンジ（秒）を表す。
+type DurationEstimate struct {
+	MinSec float64 `json:"min"`
+	MaxSec float64 `json:"max"`
+	Label  string  `json:"-"`
+}
+
+// EstimateDuration はアルゴリズム・再構築の有無・テーブルサイズ・行数・
+// セカンダリインデックス数から実行時間のレンジを概算する。
+// テーブル統計がなく推定できない場合は nil を返す。
+func EstimateDuration(settings DurationSettings, pred Prediction, tableMeta *meta.TableMeta) *DurationEstimate {
+	if pred.Algorithm == meta.AlgorithmInstant {
+		return &DurationEstimate{Label: "~0s (metadata only)"}
+	}
+	rebuild := pred.TableRebuild || pred.Algorithm == meta.AlgorithmCopy
+	if !rebuild && !isIndexBuild(pred.ActionType) {
+		// 再構築もインデックス作成も伴わない INPLACE はメタデータ変更のみ
+		return &DurationEstimate{Label: "~0s (metadata only)"}
+	}
+	if tableMeta == nil || (tableMeta.DataLength == 0 && tableMeta.RowCount == 0) {
+		return nil
+	}
+
+	s := settings.withDefaults()
+	data := float64(tableMeta.DataLength)
+	rows := float64(tableMeta.RowCount)
+	var sec float64
+	switch {
+	case pred.Algorithm == meta.AlgorithmCopy:
+		sec = data/s.CopyBytesPerSec + rows*float64(secondaryIndexCount(tableMeta))/s.IndexRowsPerSec
+	case rebuild:
+		sec = data/s.RebuildBytesPerSec + rows*float64(secondaryIndexCount(tableMeta))/s.IndexRowsPerSec
+	default:
+		// インデックス作成: テーブルを走査して1つのインデックスをソート・構築する
+		sec = data/s.IndexScanBytesPerSec + rows/s.IndexRowsPerSec
+	}
+
+	est := &DurationEstimate{MinSec: sec * s.MinFactor, MaxSec: sec * s.MaxFactor}
+	est.Label = fmt.Sprintf("%s - %s (rows: ~%s, size: ~%s)",
+		FormatDuration(est.MinSec), FormatDuration(est.MaxSec),
+		formatCount(tableMeta.RowCount), FormatSize(tableMeta.DataLength+tableMeta.IndexLength))
+	return est
+}
+
+func isIndexBuild(t meta.AlterActionType) bool {
+	switch t {
+	case meta.ActionAddIndex, meta.ActionAddUniqueIndex, meta.ActionAddFulltextIndex, meta.ActionAddSpatialIndex:
+		return true
+	default:
+		return false
+	}
+}
+
+// secondaryIndexCount は再構築時に作り直されるセカンダリインデックスの数を返す。
+func secondaryIndexCount(tableMeta *meta.TableMeta) int {
+	n := 0
+	for _, idx := range tableMeta.Indexes {
+		if !idx.IsPrimary && !strings.EqualFold(idx.Name, "PRIMARY") {
+			n++
+		}
+	}
+	return n
+}
+
+// FormatDuration は秒数を "~30s" / "~5m" / "~1.5h" の形式に整形する。
+func FormatDuration(sec float64) string {
+	switch {
+	case sec < 1:
+		return "~0s"
+	case sec < 600:
+		return fmt.Sprintf("~%.0fs", sec)
+	case sec < 7200:
+		return fmt.Sprintf("~%.0fm", sec/60)
+	default:
+		return fmt.Sprintf("~%.1fh", sec/3600)
+	}
+}
+
 // サイズ単位定数
 const (
 	KB int64 = 1024
@@ -45,7 +176,8 @@ const (
 	GB       = MB * 1024
 )
 
-func formatSize(bytes int64) string {
+// FormatSize はバイト数を "512KB" / "120MB" / "1.5GB" の形式に整形する。
+func FormatSize(bytes int64) string {
 	switch {
 	case bytes >= GB:
 		return fmt.Sprintf("%.1fGB", float64(bytes)/float64(GB))
@@ -0,0 +1,88 @@
+package predictor
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func TestEstimateDuration(t *testing.T) {
+	settings := DurationSettings{
+		RebuildBytesPerSec:   float64(100 * MB),
+		CopyBytesPerSec:      float64(50 * MB),
+		IndexScanBytesPerSec: float64(200 * MB),
+		IndexRowsPerSec:      1_000_000,
+		MinFactor:            0.5,
+		MaxFactor:            2,
+	}
+	tm := &meta.TableMeta{
+		Table:       "users",
+		RowCount:    2_000_000,
+		DataLength:  1000 * MB,
+		IndexLength: 200 * MB,
+		Indexes: []meta.IndexMeta{
+			{Name: "PRIMARY", IsPrimary: true},
+			{Name: "idx_a"},
+			{Name: "idx_b"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		pred    Prediction
+		tm      *meta.TableMeta
+		wantNil bool
+		wantMin float64
+		wantMax float64
+	}{
+		// 1000MB / 50MB/s + 2,000,000行 × 2インデックス / 1,000,000行/s = 24s
+		{"COPY", Prediction{Algorithm: meta.AlgorithmCopy, TableRebuild: true}, tm, false, 12, 48},
+		// 1000MB / 100MB/s + 4s = 14s
+		{"INPLACE再構築", Prediction{Algorithm: meta.AlgorithmInplace, TableRebuild: true}, tm, false, 7, 28},
+		// 1000MB / 200MB/s + 2s = 7s
+		{"インデックス作成", Prediction{ActionType: meta.ActionAddIndex, Algorithm: meta.AlgorithmInplace}, tm, false, 3.5, 14},
+		{"INSTANT", Prediction{Algorithm: meta.AlgorithmInstant}, nil, false, 0, 0},
+		{"再構築なしのINPLACE", Prediction{ActionType: meta.ActionRenameIndex, Algorithm: meta.AlgorithmInplace}, tm, false, 0, 0},
+		{"メタデータなし", Prediction{Algorithm: meta.AlgorithmCopy, TableRebuild: true}, nil, true, 0, 0},
+		{"統計なし", Prediction{Algorithm: meta.AlgorithmCopy, TableRebuild: true}, &meta.TableMeta{Table: "users"}, true, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est := EstimateDuration(settings, tt.pred, tt.tm)
+			if tt.wantNil {
+				if est != nil {
+					t.Errorf("推定不可の場合はnilであること: got %+v", est)
+				}
+				return
+			}
+			if est == nil {
+				t.Fatal("推定結果が返ること")
+			}
+			if est.MinSec != tt.wantMin || est.MaxSec != tt.wantMax {
+				t.Errorf("推定時間が%v-%v秒であること: got %v-%v", tt.wantMin, tt.wantMax, est.MinSec, est.MaxSec)
+			}
+		})
+	}
+}
+
+func TestEstimateDurationLabel(t *testing.T) {
+	tm := &meta.TableMeta{Table: "users", RowCount: 1_200_000, DataLength: 480 * MB}
+	est := EstimateDuration(DefaultDurationSettings(), Prediction{Algorithm: meta.AlgorithmCopy, TableRebuild: true}, tm)
+	if est == nil || est.Label != "~12s - ~48s (rows: ~1,200,000, size: ~480MB)" {
+		t.Errorf("ラベルがレンジとテーブル情報を含むこと: got %+v", est)
+	}
+}
+
+func TestPredictSetsEstimatedDuration(t *testing.T) {
+	// WithDurationSettings の係数が予測に反映されることを検証
+	tm := &meta.TableMeta{Table: "users", Engine: "InnoDB", RowCount: 1000, DataLength: 100 * MB}
+	action := meta.AlterAction{Type: meta.ActionForceRebuild}
+	slow := New(WithDurationSettings(DurationSettings{RebuildBytesPerSec: float64(1 * MB)})).Predict(action, tm)
+	fast := New().Predict(action, tm)
+	if slow.EstimatedDuration == nil || fast.EstimatedDuration == nil {
+		t.Fatal("推定時間が設定されること")
+	}
+	if slow.EstimatedDuration.MaxSec <= fast.EstimatedDuration.MaxSec {
+		t.Errorf("スループットが低いほど推定時間が長くなること: slow %v, fast %v", slow.EstimatedDuration.MaxSec, fast.EstimatedDuration.MaxSec)
+	}
+}
@@ -0,0 +1,64 @@
+package predictor
+
+import "github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+
+// Explanation は1つのアクションに対するルールの評価過程を表す。
+type Explanation struct {
+	// Trace は評価した順のルールごとの結果
+	Trace []RuleTrace `json:"trace"`
+	// Adjustments はルールの判定後に予測を変更した処理（行バージョン数の上限など）
+	Adjustments []string `json:"adjustments,omitempty"`
+}
+
+// RuleTrace は1つのルールの評価結果を表す。
+type RuleTrace struct {
+	RuleID      string `json:"rule_id"`
+	Description string `json:"description"`
+	Source      string `json:"source,omitempty"`
+	Matched     bool   `json:"matched"`
+	// Selected は予測に使われたルール（最初に一致したルール）であることを示す
+	Selected bool `json:"selected"`
+	// Reason は一致した・しなかった理由
+	Reason string `json:"reason"`
+}
+
+// add は評価結果を記録する。ex が nil の場合（explain 無効時）は何もしない。
+func (ex *Explanation) add(id, description, source string, matched bool, reason string, selected bool) {
+	if ex == nil {
+		return
+	}
+	ex.Trace = append(ex.Trace, RuleTrace{
+		RuleID:      id,
+		Description: description,
+		Source:      source,
+		Matched:     matched,
+		Selected:    selected,
+		Reason:      reason,
+	})
+}
+
+// engineReason は非InnoDBの判定理由を返す。
+func engineReason(tm *meta.TableMeta) string {
+	switch {
+	case tm == nil:
+		return "no table metadata"
+	case tm.Engine == "":
+		return "engine unknown"
+	default:
+		return "engine " + tm.Engine
+	}
+}
+
+// VersionRange はルールが適用されるMySQLバージョンの範囲を "8.0.12+", "<8.0.29" のように表す。
+func VersionRange(minVersion, maxVersion string) string {
+	switch {
+	case minVersion != "" && maxVersion != "":
+		return minVersion + "–<" + maxVersion
+	case minVersion != "":
+		return minVersion + "+"
+	case maxVersion != "":
+		return "<" + maxVersion
+	default:
+		return "any"
+	}
+}
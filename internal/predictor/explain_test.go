@@ -0,0 +1,92 @@
+package predictor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func TestPredictWithoutExplain(t *testing.T) {
+	pred := New().Predict(meta.AlterAction{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx"}}, nil)
+	if pred.Explain != nil {
+		t.Errorf("WithExplainを指定しない場合は評価過程を記録しないこと: got %+v", pred.Explain)
+	}
+}
+
+func TestExplainVarcharBoundary(t *testing.T) {
+	action := meta.AlterAction{
+		Type:   meta.ActionModifyColumn,
+		Detail: meta.ActionDetail{ColumnName: "name", ColumnType: "VARCHAR(300)", IsNullable: boolPtr(true)},
+	}
+	pred := New(WithExplain()).Predict(action, usersVarcharMeta())
+	if pred.Explain == nil {
+		t.Fatal("評価過程が記録されること")
+	}
+
+	traces := make(map[string]RuleTrace)
+	var selected []string
+	for _, tr := range pred.Explain.Trace {
+		traces[tr.RuleID] = tr
+		if tr.Selected {
+			selected = append(selected, tr.RuleID)
+		}
+	}
+	if len(selected) != 1 || selected[0] != pred.RuleID || pred.RuleID != "COL-MODIFY-TYPE-CHANGE" {
+		t.Errorf("予測に使ったルールだけがselectedになること: got %v (rule %s)", selected, pred.RuleID)
+	}
+	ext := traces["COL-MODIFY-VARCHAR-EXTEND"]
+	if ext.Matched || ext.Reason != "old varchar(200) → new VARCHAR(300) crosses 255 boundary" {
+		t.Errorf("255境界をまたぐ理由が記録されること: got %+v", ext)
+	}
+	if tr := traces[RuleIDNonInnoDB]; tr.Matched || tr.Reason != "engine InnoDB" {
+		t.Errorf("非InnoDBの判定も記録されること: got %+v", tr)
+	}
+	// 選ばれたルールより後のルールも評価して記録する
+	if tr, ok := traces["COL-MODIFY-REBUILD"]; !ok || tr.Selected {
+		t.Errorf("後続のルールも選ばれずに記録されること: got %+v", tr)
+	}
+}
+
+func TestExplainReasons(t *testing.T) {
+	p := New(WithExplain(), WithMySQLVersion("8.0.11"))
+	pred := p.Predict(meta.AlterAction{
+		Type:   meta.ActionAddColumn,
+		Detail: meta.ActionDetail{ColumnName: "nickname", ColumnType: "VARCHAR(50)", IsNullable: boolPtr(true)},
+	}, usersVarcharMeta())
+	var versionReason string
+	for _, tr := range pred.Explain.Trace {
+		if tr.RuleID == "COL-ADD-TRAILING-NULLABLE" {
+			versionReason = tr.Reason
+		}
+	}
+	if !strings.Contains(versionReason, "MySQL 8.0.11 is outside the rule's version range 8.0.12+") {
+		t.Errorf("バージョン範囲外の理由が記録されること: got %q", versionReason)
+	}
+
+	pred = New(WithExplain()).Predict(meta.AlterAction{
+		Type:   meta.ActionChangeColumn,
+		Detail: meta.ActionDetail{OldColumnName: "missing", ColumnName: "renamed", ColumnType: "INT"},
+	}, usersVarcharMeta())
+	found := false
+	for _, tr := range pred.Explain.Trace {
+		if strings.Contains(tr.Reason, "findColumn returned nil: column `missing` not in metadata") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("カラムが見つからない理由が記録されること: got %+v", pred.Explain.Trace)
+	}
+}
+
+func TestExplainFallback(t *testing.T) {
+	f, err := LoadCustomRules(strings.NewReader("mode: replace\n" + testCustomRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pred := New(WithCustomRules(f), WithExplain()).Predict(meta.AlterAction{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx"}}, nil)
+	trace := pred.Explain.Trace
+	if last := trace[len(trace)-1]; last.RuleID != RuleIDUnknown || !last.Selected || last.Reason != "no rule matched" {
+		t.Errorf("どのルールにも一致しない場合はフォールバックが記録されること: got %+v", trace)
+	}
+}
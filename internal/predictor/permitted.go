@@ -0,0 +1,103 @@
+package predictor
+
+import "github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+
+// AlgorithmOption は操作に指定できるアルゴリズムと、そのアルゴリズムで指定できる最も弱いロックを表す。
+type AlgorithmOption struct {
+	Algorithm meta.Algorithm `json:"algorithm"`
+	// MinLock は指定できる最も弱いロック。INSTANT は LOCK 句を指定できないため DEFAULT
+	MinLock      meta.LockLevel `json:"min_lock"`
+	TableRebuild bool           `json:"table_rebuild"`
+}
+
+// allAlgorithms は軽い順のアルゴリズム。
+var allAlgorithms = []meta.Algorithm{meta.AlgorithmInstant, meta.AlgorithmInplace, meta.AlgorithmCopy}
+
+// permitted はルールで指定できるアルゴリズムの一覧を返す。Permitted が空の場合は予測結果から導出する。
+func (r PredictionRule) permitted() []AlgorithmOption {
+	if len(r.Permitted) > 0 {
+		return r.Permitted
+	}
+	return derivePermitted(r.Algorithm, r.Lock, r.TableRebuild)
+}
+
+// derivePermitted は最も軽いアルゴリズムの予測から、指定できるアルゴリズムの一覧を導出する。
+// INSTANT の操作は INPLACE (LOCK=NONE) でも実行でき、どの操作も COPY (LOCK=SHARED 以上) で実行できる。
+func derivePermitted(alg meta.Algorithm, lock meta.LockLevel, rebuild bool) []AlgorithmOption {
+	var opts []AlgorithmOption
+	switch alg {
+	case meta.AlgorithmInstant:
+		opts = append(opts,
+			AlgorithmOption{Algorithm: meta.AlgorithmInstant, MinLock: meta.LockDefault, TableRebuild: rebuild},
+			AlgorithmOption{Algorithm: meta.AlgorithmInplace, MinLock: meta.LockNone, TableRebuild: rebuild})
+	case meta.AlgorithmInplace:
+		opts = append(opts, AlgorithmOption{Algorithm: meta.AlgorithmInplace, MinLock: lock, TableRebuild: rebuild})
+	}
+	copyLock := lock
+	if lockOrd(copyLock) < lockOrd(meta.LockShared) {
+		copyLock = meta.LockShared
+	}
+	return append(opts, AlgorithmOption{Algorithm: meta.AlgorithmCopy, MinLock: copyLock, TableRebuild: true})
+}
+
+// instantOrInplaceRebuild は INSTANT で実行でき、INPLACE ではテーブル再構築になる操作
+// （INSTANT ADD COLUMN など）の指定できるアルゴリズムを返す。
+func instantOrInplaceRebuild() []AlgorithmOption {
+	return []AlgorithmOption{
+		{Algorithm: meta.AlgorithmInstant, MinLock: meta.LockDefault},
+		{Algorithm: meta.AlgorithmInplace, MinLock: meta.LockNone, TableRebuild: true},
+		{Algorithm: meta.AlgorithmCopy, MinLock: meta.LockShared, TableRebuild: true},
+	}
+}
+
+// findOption は一覧から指定したアルゴリズムを探す。
+func findOption(opts []AlgorithmOption, alg meta.Algorithm) (AlgorithmOption, bool) {
+	for _, o := range opts {
+		if o.Algorithm == alg {
+			return o, true
+		}
+	}
+	return AlgorithmOption{}, false
+}
+
+// withoutAlgorithm は一覧から指定したアルゴリズムを除いたものを返す。
+func withoutAlgorithm(opts []AlgorithmOption, alg meta.Algorithm) []AlgorithmOption {
+	var out []AlgorithmOption
+	for _, o := range opts {
+		if o.Algorithm != alg {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// combinePermitted は文中の全アクションに指定できるアルゴリズムを返す。
+// 全アクションが許可するアルゴリズムのみを残し、ロックは最も強い MinLock を採用する。
+// 一覧のない予測を含む場合は nil を返す。
+func combinePermitted(preds []Prediction) []AlgorithmOption {
+	for _, p := range preds {
+		if len(p.Permitted) == 0 {
+			return nil
+		}
+	}
+	var out []AlgorithmOption
+	for _, alg := range allAlgorithms {
+		combined := AlgorithmOption{Algorithm: alg}
+		ok := true
+		for i, p := range preds {
+			o, found := findOption(p.Permitted, alg)
+			if !found {
+				ok = false
+				break
+			}
+			if i == 0 || lockOrd(o.MinLock) > lockOrd(combined.MinLock) {
+				combined.MinLock = o.MinLock
+			}
+			combined.TableRebuild = combined.TableRebuild || o.TableRebuild
+		}
+		if ok {
+			out = append(out, combined)
+		}
+	}
+	return out
+}
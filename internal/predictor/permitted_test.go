@@ -0,0 +1,92 @@
+package predictor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func TestDerivePermitted(t *testing.T) {
+	tests := []struct {
+		name    string
+		alg     meta.Algorithm
+		lock    meta.LockLevel
+		rebuild bool
+		want    []AlgorithmOption
+	}{
+		{"INSTANT", meta.AlgorithmInstant, meta.LockNone, false, []AlgorithmOption{
+			{Algorithm: meta.AlgorithmInstant, MinLock: meta.LockDefault},
+			{Algorithm: meta.AlgorithmInplace, MinLock: meta.LockNone},
+			{Algorithm: meta.AlgorithmCopy, MinLock: meta.LockShared, TableRebuild: true},
+		}},
+		{"INPLACE (SHARED)", meta.AlgorithmInplace, meta.LockShared, false, []AlgorithmOption{
+			{Algorithm: meta.AlgorithmInplace, MinLock: meta.LockShared},
+			{Algorithm: meta.AlgorithmCopy, MinLock: meta.LockShared, TableRebuild: true},
+		}},
+		{"COPY", meta.AlgorithmCopy, meta.LockExclusive, true, []AlgorithmOption{
+			{Algorithm: meta.AlgorithmCopy, MinLock: meta.LockExclusive, TableRebuild: true},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := derivePermitted(tt.alg, tt.lock, tt.rebuild); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredictPermitted(t *testing.T) {
+	p := New(WithMySQLVersion("8.0.35"))
+	pred := p.Predict(meta.AlterAction{
+		Type:   meta.ActionAddColumn,
+		Detail: meta.ActionDetail{ColumnName: "nickname", ColumnType: "VARCHAR(50)", IsNullable: boolPtr(true)},
+	}, nil)
+	if o, ok := findOption(pred.Permitted, meta.AlgorithmInplace); !ok || !o.TableRebuild || o.MinLock != meta.LockNone {
+		t.Errorf("INSTANT ADD COLUMN は INPLACE (LOCK=NONE) では再構築になること: got %+v", pred.Permitted)
+	}
+
+	pred = p.Predict(meta.AlterAction{Type: meta.ActionAddFulltextIndex, Detail: meta.ActionDetail{IndexName: "ft"}}, nil)
+	if _, ok := findOption(pred.Permitted, meta.AlgorithmInstant); ok {
+		t.Errorf("FULLTEXT インデックスは INSTANT を指定できないこと: got %+v", pred.Permitted)
+	}
+	if o, ok := findOption(pred.Permitted, meta.AlgorithmInplace); !ok || o.MinLock != meta.LockShared {
+		t.Errorf("FULLTEXT インデックスの INPLACE は LOCK=SHARED 以上であること: got %+v", pred.Permitted)
+	}
+
+	pred = p.Predict(meta.AlterAction{Type: meta.ActionDropPrimaryKey}, nil)
+	if len(pred.Permitted) != 1 || pred.Permitted[0].Algorithm != meta.AlgorithmCopy {
+		t.Errorf("DROP PRIMARY KEY は COPY のみ指定できること: got %+v", pred.Permitted)
+	}
+}
+
+func TestPermittedRowVersionLimit(t *testing.T) {
+	tm := usersVarcharMeta()
+	tm.TotalRowVersions = meta.MaxRowVersions
+	pred := New(WithMySQLVersion("8.0.35")).Predict(meta.AlterAction{
+		Type:   meta.ActionAddColumn,
+		Detail: meta.ActionDetail{ColumnName: "nickname", ColumnType: "VARCHAR(50)", IsNullable: boolPtr(true)},
+	}, tm)
+	if _, ok := findOption(pred.Permitted, meta.AlgorithmInstant); ok {
+		t.Errorf("行バージョン数の上限に達した場合は INSTANT を指定できないこと: got %+v", pred.Permitted)
+	}
+}
+
+func TestCombinePermitted(t *testing.T) {
+	p := New(WithMySQLVersion("8.0.35"))
+	preds := []Prediction{
+		p.Predict(meta.AlterAction{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "a", ColumnType: "INT", IsNullable: boolPtr(true)}}, nil),
+		p.Predict(meta.AlterAction{Type: meta.ActionAddFulltextIndex, Detail: meta.ActionDetail{IndexName: "ft"}}, nil),
+	}
+	want := []AlgorithmOption{
+		{Algorithm: meta.AlgorithmInplace, MinLock: meta.LockShared, TableRebuild: true},
+		{Algorithm: meta.AlgorithmCopy, MinLock: meta.LockShared, TableRebuild: true},
+	}
+	if got := CombinePredictions(preds).Permitted; !reflect.DeepEqual(got, want) {
+		t.Errorf("全アクションが許可するアルゴリズムと最も強いロックになること: got %+v, want %+v", got, want)
+	}
+	if got := combinePermitted([]Prediction{preds[0], {}}); got != nil {
+		t.Errorf("一覧のない予測を含む場合は nil になること: got %+v", got)
+	}
+}
@@ -1,6 +1,7 @@
 package predictor
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
@@ -8,75 +9,214 @@ import (
 
 // Prediction は単一のALTERアクションに対する予測されたロック動作を表す。
 type Prediction struct {
-	ActionType   meta.AlterActionType `json:"action_type"`
-	Description  string               `json:"description"`
-	Algorithm    meta.Algorithm       `json:"algorithm"`
-	Lock         meta.LockLevel       `json:"lock_level"`
-	TableRebuild bool                 `json:"table_rebuild"`
-	RiskLevel    meta.RiskLevel       `json:"risk_level"`
-	TableInfo    TableInfo            `json:"table_info"`
-	Notes        []string             `json:"notes,omitempty"`
-	Warnings     []string             `json:"warnings,omitempty"`
+	ActionType  meta.AlterActionType `json:"action_type"`
+	Description string               `json:"description"`
+	// RuleID は予測に使ったルールのID
+	RuleID string `json:"rule_id"`
+	// RuleSource はユーザー定義ルールの読み込み元（デフォルトルールの場合は空）
+	RuleSource string `json:"rule_source,omitempty"`
+	// DocURL はルールの根拠となるMySQLリファレンスマニュアルのURL
+	DocURL       string         `json:"doc_url,omitempty"`
+	Algorithm    meta.Algorithm `json:"algorithm"`
+	Lock         meta.LockLevel `json:"lock_level"`
+	TableRebuild bool           `json:"table_rebuild"`
+	// Permitted は指定できるアルゴリズムと、それぞれで指定できる最も弱いロック
+	Permitted []AlgorithmOption `json:"permitted,omitempty"`
+	RiskLevel meta.RiskLevel    `json:"risk_level"`
+	// RiskScore は RiskLevel の元になった数値スコアと要因の内訳
+	RiskScore *RiskScore `json:"risk_score,omitempty"`
+	TableInfo TableInfo  `json:"table_info"`
+	Notes     []string   `json:"notes,omitempty"`
+	Warnings  []string   `json:"warnings,omitempty"`
+	// EstimatedDuration は推定実行時間（テーブル統計がない場合は nil）
+	EstimatedDuration *DurationEstimate `json:"estimated_duration_sec,omitempty"`
+	// Explain はルールの評価過程（WithExplain を指定した場合のみ）
+	Explain *Explanation `json:"explain,omitempty"`
+	// GeneratedType は対象カラムの生成カラム種別（"", "STORED", "VIRTUAL"）。文全体の判定に使う
+	GeneratedType string `json:"-"`
 }
 
 // Predictor はルールに基づいてDDLロック動作を予測する。
 type Predictor struct {
-	rules []PredictionRule
+	// custom はデフォルトルールより先に評価するユーザー定義ルール
+	custom     []PredictionRule
+	rules      []PredictionRule
+	version    Version
+	duration   DurationSettings
+	thresholds RiskThresholds
+	explain    bool
+}
+
+// Option は Predictor の設定を変更する。
+type Option func(*Predictor)
+
+// WithMySQLVersion は予測対象のMySQLサーバーバージョンを設定する。
+// 解析できないバージョンや空文字の場合はバージョンによる絞り込みを行わない。
+func WithMySQLVersion(version string) Option {
+	return func(p *Predictor) {
+		v, err := ParseVersion(version)
+		if err != nil {
+			return
+		}
+		p.version = v
+	}
+}
+
+// WithDurationSettings は推定実行時間の算出に使う係数を設定する。
+func WithDurationSettings(settings DurationSettings) Option {
+	return func(p *Predictor) {
+		p.duration = settings
+	}
+}
+
+// WithRiskThresholds はリスクスコアをリスクレベルに変換する閾値を設定する。
+func WithRiskThresholds(thresholds RiskThresholds) Option {
+	return func(p *Predictor) {
+		p.thresholds = thresholds
+	}
+}
+
+// WithExplain は予測ごとにルールの評価過程（Prediction.Explain）を記録する。
+func WithExplain() Option {
+	return func(p *Predictor) {
+		p.explain = true
+	}
+}
+
+// WithCustomRules はユーザー定義ルールを設定する。
+// ユーザー定義ルールは非InnoDBの判定やデフォルトルールより先に評価し、
+// replace モードの場合はデフォルトルールを使わない。
+func WithCustomRules(f *CustomRuleFile) Option {
+	return func(p *Predictor) {
+		if f == nil {
+			return
+		}
+		p.custom = f.predictionRules()
+		if f.Mode == CustomRulesReplace {
+			p.rules = nil
+		}
+	}
 }
 
 // New はデフォルトルールで新しい Predictor を作成する。
-func New() *Predictor {
-	return &Predictor{rules: defaultRules()}
+func New(opts ...Option) *Predictor {
+	p := &Predictor{rules: defaultRules(), duration: DefaultDurationSettings(), thresholds: DefaultRiskThresholds()}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
-// Predict は指定されたALTERアクションのロック動作を予測する。
+// Predict は指定されたALTERアクションのロック動作・推定実行時間・リスクスコアを予測する。
+// INSTANT ADD/DROP COLUMN はテーブルの行バージョン数の上限も考慮する。
 func (p *Predictor) Predict(action meta.AlterAction, tableMeta *meta.TableMeta) Prediction {
+	var ex *Explanation
+	if p.explain {
+		ex = &Explanation{}
+	}
+	pred := p.predict(action, tableMeta, ex)
+	algorithm := pred.Algorithm
+	p.applyRowVersionLimit(action, &pred, tableMeta)
+	if ex != nil && pred.Algorithm != algorithm {
+		ex.Adjustments = append(ex.Adjustments, fmt.Sprintf("INSTANT row version limit reached — %s changed to %s with table rebuild", algorithm, pred.Algorithm))
+	}
+	pred.EstimatedDuration = EstimateDuration(p.duration, pred, tableMeta)
+	pred.RiskScore = scoreRisk(p.thresholds, pred, tableMeta)
+	pred.RiskLevel = pred.RiskScore.Level()
+	pred.Explain = ex
+	pred.GeneratedType = action.Detail.GeneratedType
+	return pred
+}
+
+// predict はルールを評価して予測を返す。ex が nil でない場合は全ルールの評価過程を記録する。
+func (p *Predictor) predict(action meta.AlterAction, tableMeta *meta.TableMeta, ex *Explanation) Prediction {
+	pred, found := p.matchRules(p.custom, action, tableMeta, ex, false)
+
 	// 非InnoDB: すべて COPY/EXCLUSIVE になる
-	if tableMeta != nil && !strings.EqualFold(tableMeta.Engine, "InnoDB") && tableMeta.Engine != "" {
-		return Prediction{
+	nonInnoDB := tableMeta != nil && !strings.EqualFold(tableMeta.Engine, "InnoDB") && tableMeta.Engine != ""
+	ex.add(RuleIDNonInnoDB, "non-InnoDB engine", "", nonInnoDB, engineReason(tableMeta), nonInnoDB && !found)
+	if nonInnoDB && !found {
+		pred, found = Prediction{
 			ActionType:   action.Type,
 			Description:  string(action.Type) + " (non-InnoDB)",
+			RuleID:       RuleIDNonInnoDB,
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockExclusive,
 			TableRebuild: true,
+			Permitted:    derivePermitted(meta.AlgorithmCopy, meta.LockExclusive, true),
 			RiskLevel:    meta.RiskCritical,
 			TableInfo:    CollectTableInfo(tableMeta),
 			Warnings:     []string{"Non-InnoDB engine — all operations use COPY algorithm with EXCLUSIVE lock"},
-		}
+		}, true
+	}
+
+	if matched, ok := p.matchRules(p.rules, action, tableMeta, ex, found); ok {
+		pred, found = matched, true
+	}
+	if found {
+		return pred
 	}
 
-	for _, rule := range p.rules {
+	// フォールバック: 不明な操作は安全のため COPY/EXCLUSIVE をデフォルトとする
+	ex.add(RuleIDUnknown, "unknown operation", "", true, "no rule matched", true)
+	return Prediction{
+		ActionType:   action.Type,
+		Description:  string(action.Type) + " (unknown)",
+		RuleID:       RuleIDUnknown,
+		Algorithm:    meta.AlgorithmCopy,
+		Lock:         meta.LockExclusive,
+		TableRebuild: true,
+		Permitted:    derivePermitted(meta.AlgorithmCopy, meta.LockExclusive, true),
+		RiskLevel:    meta.RiskCritical,
+		TableInfo:    CollectTableInfo(tableMeta),
+		Warnings:     []string{"Unknown operation — defaulting to COPY/EXCLUSIVE for safety"},
+	}
+}
+
+// matchRules はルールを順に評価し、最初に一致したルールの予測を返す。
+// found が true（既に予測が決まっている）の場合は一致しても選ばず、explain の記録のためだけに評価する。
+func (p *Predictor) matchRules(rules []PredictionRule, action meta.AlterAction, tableMeta *meta.TableMeta, ex *Explanation, found bool) (Prediction, bool) {
+	if found && ex == nil {
+		return Prediction{}, false
+	}
+	var pred Prediction
+	matched := false
+	for _, rule := range rules {
 		if rule.ActionType != action.Type {
 			continue
 		}
-		if !rule.Condition(action, tableMeta) {
+		if !rule.appliesTo(p.version) {
+			ex.add(rule.ID, rule.Description, rule.Source, false,
+				fmt.Sprintf("MySQL %s is outside the rule's version range %s", p.version, VersionRange(rule.MinVersion, rule.MaxVersion)), false)
+			continue
+		}
+		ok, reason := rule.Condition(action, tableMeta)
+		selected := ok && !found && !matched
+		ex.add(rule.ID, rule.Description, rule.Source, ok, reason, selected)
+		if !selected {
 			continue
 		}
-		pred := Prediction{
+		matched = true
+		pred = Prediction{
 			ActionType:   action.Type,
 			Description:  rule.Description,
+			RuleID:       rule.ID,
+			RuleSource:   rule.Source,
+			DocURL:       rule.DocURL(),
 			Algorithm:    rule.Algorithm,
 			Lock:         rule.Lock,
 			TableRebuild: rule.TableRebuild,
+			Permitted:    rule.permitted(),
 			RiskLevel:    calculateRisk(rule.Algorithm, rule.Lock, rule.TableRebuild),
 			TableInfo:    CollectTableInfo(tableMeta),
 			Notes:        rule.Notes,
 			Warnings:     rule.Warnings,
 		}
-		return pred
-	}
-
-	// フォールバック: 不明な操作は安全のため COPY/EXCLUSIVE をデフォルトとする
-	return Prediction{
-		ActionType:   action.Type,
-		Description:  string(action.Type) + " (unknown)",
-		Algorithm:    meta.AlgorithmCopy,
-		Lock:         meta.LockExclusive,
-		TableRebuild: true,
-		RiskLevel:    meta.RiskCritical,
-		TableInfo:    CollectTableInfo(tableMeta),
-		Warnings:     []string{"Unknown operation — defaulting to COPY/EXCLUSIVE for safety"},
+		if ex == nil {
+			break
+		}
 	}
+	return pred, matched
 }
 
 // PredictAll はALTER操作内の全アクションについてロック動作を予測する。
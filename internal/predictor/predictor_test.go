@@ -1330,3 +1330,57 @@ func TestExtractVarcharLength(t *testing.T) {
 		}
 	}
 }
+
+// ============================================================
+// Rule ID / rule table tests
+// ============================================================
+
+func TestDefaultRuleIDs(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, r := range defaultRules() {
+		if r.ID == "" {
+			t.Errorf("ルール %q にIDがあること", r.Description)
+			continue
+		}
+		if seen[r.ID] {
+			t.Errorf("ルールID %s が重複しないこと", r.ID)
+		}
+		seen[r.ID] = true
+		if r.DocAnchor == "" {
+			t.Errorf("ルール %s にドキュメントのアンカーがあること", r.ID)
+		}
+	}
+}
+
+func TestPredictRuleIDAndDocURL(t *testing.T) {
+	pred := New().Predict(meta.AlterAction{
+		Type:   meta.ActionAddColumn,
+		Detail: meta.ActionDetail{ColumnName: "nickname", ColumnType: "VARCHAR(255)", IsNullable: boolPtr(true)},
+	}, nil)
+	if pred.RuleID != "COL-ADD-TRAILING-NULLABLE" {
+		t.Errorf("ルールIDが予測に含まれること: got %s", pred.RuleID)
+	}
+	if pred.DocURL != onlineDDLDocURL+"#online-ddl-column-operations" {
+		t.Errorf("リファレンスマニュアルのURLが予測に含まれること: got %s", pred.DocURL)
+	}
+}
+
+func TestRules(t *testing.T) {
+	infos := New().Rules()
+	if len(infos) != len(defaultRules())+2 {
+		t.Fatalf("デフォルトルールと非InnoDB・フォールバックの判定が含まれること: got %d", len(infos))
+	}
+	if infos[0].ID != RuleIDNonInnoDB || infos[len(infos)-1].ID != RuleIDUnknown {
+		t.Errorf("評価順に並ぶこと: got %s ... %s", infos[0].ID, infos[len(infos)-1].ID)
+	}
+	whens := make(map[string]string)
+	for _, info := range infos {
+		whens[info.ID] = info.When
+	}
+	if whens["IDX-ADD"] != "always" {
+		t.Errorf("先行ルールがない条件なしのルールはalwaysになること: got %s", whens["IDX-ADD"])
+	}
+	if whens["COL-MODIFY-REBUILD"] != "otherwise" {
+		t.Errorf("先行ルールがある条件なしのルールはotherwiseになること: got %s", whens["COL-MODIFY-REBUILD"])
+	}
+}
@@ -0,0 +1,72 @@
+package predictor
+
+import (
+	"fmt"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// rowVersionMinVersion は行バージョンの上限が導入されたMySQLバージョン。
+var rowVersionMinVersion = Version{Major: 8, Minor: 0, Patch: 29}
+
+// rowVersionWarnRemaining は残りの行バージョン数がこの値以下になったら警告する閾値。
+const rowVersionWarnRemaining = 8
+
+// consumesRowVersion は予測が INSTANT ADD/DROP COLUMN として行バージョンを消費するかを返す。
+// VIRTUAL 生成カラムの追加は行フォーマットを変えないため消費しない。
+func consumesRowVersion(action meta.AlterAction, pred Prediction) bool {
+	if pred.Algorithm != meta.AlgorithmInstant {
+		return false
+	}
+	switch action.Type {
+	case meta.ActionAddColumn:
+		return action.Detail.GeneratedType != "VIRTUAL"
+	case meta.ActionDropColumn:
+		return true
+	default:
+		return false
+	}
+}
+
+// applyRowVersionLimit は INSTANT ADD/DROP COLUMN の行バージョン上限（64）を予測に反映する。
+// 上限を超える場合は INPLACE によるテーブル再構築に切り替え、残りが少ない場合は警告を追加する。
+func (p *Predictor) applyRowVersionLimit(action meta.AlterAction, pred *Prediction, tableMeta *meta.TableMeta) {
+	if tableMeta == nil || !consumesRowVersion(action, *pred) {
+		return
+	}
+	if !p.version.IsZero() && p.version.Compare(rowVersionMinVersion) < 0 {
+		return
+	}
+	used := tableMeta.TotalRowVersions
+	remaining := meta.MaxRowVersions - used - 1
+	if remaining < 0 {
+		pred.Description += " (row version limit reached)"
+		pred.Algorithm = meta.AlgorithmInplace
+		pred.Lock = meta.LockNone
+		pred.TableRebuild = true
+		pred.Permitted = withoutAlgorithm(pred.Permitted, meta.AlgorithmInstant)
+		pred.Notes = []string{
+			fmt.Sprintf("Table has %d INSTANT row versions; MySQL allows at most %d", used, meta.MaxRowVersions),
+			"The rebuild resets the row version counter",
+		}
+		pred.Warnings = append(append([]string(nil), pred.Warnings...),
+			fmt.Sprintf("INSTANT row version limit reached (%d/%d) — falls back to INPLACE with table rebuild", used, meta.MaxRowVersions))
+		return
+	}
+	if used == 0 {
+		return
+	}
+	pred.Notes = append(append([]string(nil), pred.Notes...),
+		fmt.Sprintf("INSTANT row versions: %d/%d used, %d remaining after this ALTER", used, meta.MaxRowVersions, remaining))
+	var warning string
+	switch {
+	case remaining == 0:
+		warning = "This ALTER uses the last INSTANT row version — further ADD/DROP COLUMN will require a table rebuild"
+	case remaining <= rowVersionWarnRemaining:
+		warning = fmt.Sprintf("Only %d INSTANT row versions remaining — further ADD/DROP COLUMN will soon require a table rebuild", remaining)
+	default:
+		return
+	}
+	pred.Warnings = append(append([]string(nil), pred.Warnings...),
+		warning+" (OPTIMIZE TABLE during a quiet period resets the counter)")
+}
@@ -0,0 +1,82 @@
+package predictor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func TestPredictRowVersionLimit(t *testing.T) {
+	addColumn := meta.AlterAction{
+		Type:   meta.ActionAddColumn,
+		Detail: meta.ActionDetail{ColumnName: "nickname", ColumnType: "VARCHAR(255)", IsNullable: boolPtr(true)},
+	}
+	dropColumn := meta.AlterAction{
+		Type:   meta.ActionDropColumn,
+		Detail: meta.ActionDetail{ColumnName: "nickname"},
+	}
+	addVirtual := meta.AlterAction{
+		Type:   meta.ActionAddColumn,
+		Detail: meta.ActionDetail{ColumnName: "v", ColumnType: "INT", IsNullable: boolPtr(true), GeneratedType: "VIRTUAL"},
+	}
+
+	tests := []struct {
+		name          string
+		version       string
+		action        meta.AlterAction
+		rowVersions   int
+		wantAlgorithm meta.Algorithm
+		wantRebuild   bool
+		wantWarning   string
+	}{
+		{"行バージョンなし", "8.0.32", addColumn, 0, meta.AlgorithmInstant, false, ""},
+		{"残りに余裕がある", "8.0.32", addColumn, 10, meta.AlgorithmInstant, false, ""},
+		{"残りが少ない", "8.0.32", addColumn, 60, meta.AlgorithmInstant, false, "Only 3 INSTANT row versions remaining"},
+		{"最後の1バージョン", "8.0.32", addColumn, 63, meta.AlgorithmInstant, false, "uses the last INSTANT row version"},
+		{"上限到達でADD COLUMNは再構築", "8.0.32", addColumn, 64, meta.AlgorithmInplace, true, "row version limit reached (64/64)"},
+		{"上限到達でDROP COLUMNは再構築", "8.0.32", dropColumn, 64, meta.AlgorithmInplace, true, "row version limit reached (64/64)"},
+		{"バージョン不明でも上限を考慮", "", addColumn, 64, meta.AlgorithmInplace, true, "row version limit reached"},
+		{"VIRTUAL生成カラムは消費しない", "8.0.32", addVirtual, 64, meta.AlgorithmInstant, false, ""},
+		{"8.0.29未満は上限なし", "8.0.28", addColumn, 64, meta.AlgorithmInstant, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(WithMySQLVersion(tt.version))
+			tm := &meta.TableMeta{Engine: "InnoDB", TotalRowVersions: tt.rowVersions}
+			pred := p.Predict(tt.action, tm)
+			if pred.Algorithm != tt.wantAlgorithm {
+				t.Errorf("アルゴリズムが %s であること: got %s", tt.wantAlgorithm, pred.Algorithm)
+			}
+			if pred.TableRebuild != tt.wantRebuild {
+				t.Errorf("再構築が %v であること: got %v", tt.wantRebuild, pred.TableRebuild)
+			}
+			if pred.Lock != meta.LockNone {
+				t.Errorf("ロックがNONEであること: got %s", pred.Lock)
+			}
+			joined := strings.Join(pred.Warnings, "\n")
+			if tt.wantWarning == "" && strings.Contains(joined, "row version") {
+				t.Errorf("行バージョンの警告が出ないこと: got %v", pred.Warnings)
+			}
+			if tt.wantWarning != "" && !strings.Contains(joined, tt.wantWarning) {
+				t.Errorf("警告に %q を含むこと: got %v", tt.wantWarning, pred.Warnings)
+			}
+		})
+	}
+}
+
+func TestPredictRowVersionLimitDoesNotMutateRules(t *testing.T) {
+	// 警告の追加がルール定義のスライスに影響しないことを検証
+	p := New()
+	action := meta.AlterAction{
+		Type:   meta.ActionAddColumn,
+		Detail: meta.ActionDetail{ColumnName: "c", ColumnType: "INT", IsNullable: boolPtr(true)},
+	}
+	_ = p.Predict(action, &meta.TableMeta{Engine: "InnoDB", TotalRowVersions: 63})
+	pred := p.Predict(action, &meta.TableMeta{Engine: "InnoDB"})
+	for _, w := range pred.Warnings {
+		if strings.Contains(w, "row version") {
+			t.Errorf("前の予測の警告が残らないこと: got %v", pred.Warnings)
+		}
+	}
+}
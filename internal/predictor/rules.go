@@ -7,16 +7,162 @@ import (
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
 )
 
+// onlineDDLDocURL はMySQLリファレンスマニュアル「Online DDL Operations」のURL。
+const onlineDDLDocURL = "https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html"
+
+// Online DDL Operations の各節のアンカー。
+const (
+	docIndex           = "online-ddl-index-operations"
+	docPrimaryKey      = "online-ddl-primary-key-operations"
+	docColumn          = "online-ddl-column-operations"
+	docGeneratedColumn = "online-ddl-generated-column-operations"
+	docForeignKey      = "online-ddl-foreign-key-operations"
+	docTable           = "online-ddl-table-operations"
+	docTablespace      = "online-ddl-tablespace-operations"
+	docPartition       = "online-ddl-partitioning-operations"
+)
+
+// ルールテーブル外の判定に使うルールID。
+const (
+	// RuleIDNonInnoDB は非InnoDBテーブルの判定（すべて COPY/EXCLUSIVE）。
+	RuleIDNonInnoDB = "ENGINE-NON-INNODB"
+	// RuleIDUnknown はどのルールにも一致しなかった操作の判定。
+	RuleIDUnknown = "UNKNOWN-ACTION"
+)
+
+// Condition はアクションとテーブルがルールの条件を満たすかを判定し、判定の理由を返す。
+// 理由は explain モードでルールの評価過程として表示する。
+type Condition func(action meta.AlterAction, tableMeta *meta.TableMeta) (bool, string)
+
 // PredictionRule はDDLロック動作を予測するためのルールを定義する。
 type PredictionRule struct {
-	ActionType   meta.AlterActionType
-	Description  string
-	Condition    func(action meta.AlterAction, tableMeta *meta.TableMeta) bool
+	// ID はルールを識別する安定したID（例: COL-ADD-TRAILING-NULLABLE）。出力の抑制や追跡に使う
+	ID string
+	// Source はユーザー定義ルールの読み込み元（デフォルトルールは空）
+	Source      string
+	ActionType  meta.AlterActionType
+	Description string
+	// When は Condition の説明（rules サブコマンドで表示する）。空なら常に一致する
+	When string
+	// DocAnchor は Online DDL Operations の該当節のアンカー
+	DocAnchor    string
+	Condition    Condition
 	Algorithm    meta.Algorithm
 	Lock         meta.LockLevel
 	TableRebuild bool
-	Notes        []string
-	Warnings     []string
+	// Permitted は指定できるアルゴリズムと最も弱いロックの一覧。空なら Algorithm/Lock から導出する
+	Permitted []AlgorithmOption
+	Notes     []string
+	Warnings  []string
+	// MinVersion はルールが適用される最小のMySQLバージョン（この版を含む）。空なら下限なし。
+	MinVersion string
+	// MaxVersion はルールが適用されなくなるMySQLバージョン（この版を含まない）。空なら上限なし。
+	MaxVersion string
+}
+
+// DocURL はルールの根拠となるMySQLリファレンスマニュアルのURLを返す。アンカーがない場合は空文字。
+func (r PredictionRule) DocURL() string {
+	if r.DocAnchor == "" {
+		return ""
+	}
+	return onlineDDLDocURL + "#" + r.DocAnchor
+}
+
+// appliesTo はルールが指定バージョンのサーバーに適用されるかを返す。
+// バージョンが不明（ゼロ値）の場合は常に適用する。
+func (r PredictionRule) appliesTo(v Version) bool {
+	if v.IsZero() {
+		return true
+	}
+	if r.MinVersion != "" {
+		if minV, err := ParseVersion(r.MinVersion); err == nil && v.Compare(minV) < 0 {
+			return false
+		}
+	}
+	if r.MaxVersion != "" {
+		if maxV, err := ParseVersion(r.MaxVersion); err == nil && v.Compare(maxV) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// RuleInfo はルールテーブルの1行（rules サブコマンドの出力）を表す。
+type RuleInfo struct {
+	ID           string               `json:"id"`
+	Source       string               `json:"source,omitempty"`
+	ActionType   meta.AlterActionType `json:"action_type"`
+	Description  string               `json:"description"`
+	When         string               `json:"when"`
+	Algorithm    meta.Algorithm       `json:"algorithm"`
+	Lock         meta.LockLevel       `json:"lock_level"`
+	TableRebuild bool                 `json:"table_rebuild"`
+	Permitted    []AlgorithmOption    `json:"permitted"`
+	MinVersion   string               `json:"min_version,omitempty"`
+	MaxVersion   string               `json:"max_version,omitempty"`
+	DocURL       string               `json:"doc_url,omitempty"`
+}
+
+// AnyAction は全ての操作に適用される判定（非InnoDB、フォールバック）の ActionType。
+const AnyAction meta.AlterActionType = "*"
+
+// Rules は評価順のルールテーブル（ユーザー定義ルール、非InnoDBの判定、デフォルトルール、フォールバック）を返す。
+// 条件のないルールは、同じ操作の先行ルールがあれば "otherwise"、なければ "always" と表示する。
+func (p *Predictor) Rules() []RuleInfo {
+	infos := make([]RuleInfo, 0, len(p.custom)+len(p.rules)+2)
+	seen := make(map[meta.AlterActionType]bool)
+	add := func(r PredictionRule) {
+		when := r.When
+		switch {
+		case when != "":
+		case seen[r.ActionType]:
+			when = "otherwise"
+		default:
+			when = "always"
+		}
+		seen[r.ActionType] = true
+		infos = append(infos, RuleInfo{
+			ID:           r.ID,
+			Source:       r.Source,
+			ActionType:   r.ActionType,
+			Description:  r.Description,
+			When:         when,
+			Algorithm:    r.Algorithm,
+			Lock:         r.Lock,
+			TableRebuild: r.TableRebuild,
+			Permitted:    r.permitted(),
+			MinVersion:   r.MinVersion,
+			MaxVersion:   r.MaxVersion,
+			DocURL:       r.DocURL(),
+		})
+	}
+	for _, r := range p.custom {
+		add(r)
+	}
+	infos = append(infos, RuleInfo{
+		ID:           RuleIDNonInnoDB,
+		ActionType:   AnyAction,
+		Description:  "non-InnoDB engine",
+		When:         "table engine in metadata is not InnoDB",
+		Algorithm:    meta.AlgorithmCopy,
+		Lock:         meta.LockExclusive,
+		TableRebuild: true,
+		Permitted:    derivePermitted(meta.AlgorithmCopy, meta.LockExclusive, true),
+	})
+	for _, r := range p.rules {
+		add(r)
+	}
+	infos = append(infos, RuleInfo{
+		ID:           RuleIDUnknown,
+		ActionType:   AnyAction,
+		Description:  "unknown operation",
+		When:         "no rule matched",
+		Algorithm:    meta.AlgorithmCopy,
+		Lock:         meta.LockExclusive,
+		TableRebuild: true,
+		Permitted:    derivePermitted(meta.AlgorithmCopy, meta.LockExclusive, true),
+	})
+	return infos
 }
 
 // defaultRules はカテゴリ別ファイルのルールを正しい順序で結合して返す。
@@ -30,8 +176,8 @@ func defaultRules() []PredictionRule {
 	return rules
 }
 
-func alwaysMatch(_ meta.AlterAction, _ *meta.TableMeta) bool {
-	return true
+func alwaysMatch(_ meta.AlterAction, _ *meta.TableMeta) (bool, string) {
+	return true, "no condition"
 }
 
 // varcharLenRegex extracts the length from VARCHAR(N) type strings.
@@ -15,10 +15,13 @@ func columnRules() []PredictionRule {
 		// MySQL docs: concurrent DML is NOT permitted for auto-increment columns
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-column-operations
 		{
+			ID:          "COL-ADD-AUTO-INCREMENT",
 			ActionType:  meta.ActionAddColumn,
 			Description: "ADD COLUMN (auto-increment)",
-			Condition: func(a meta.AlterAction, _ *meta.TableMeta) bool {
-				return a.Detail.IsAutoIncrement
+			When:        "new column is AUTO_INCREMENT",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, _ *meta.TableMeta) (bool, string) {
+				return explain(a.Detail.IsAutoIncrement, "new column is AUTO_INCREMENT", "new column is not AUTO_INCREMENT")
 			},
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockShared,
@@ -29,10 +32,13 @@ func columnRules() []PredictionRule {
 		// ADD COLUMN (STORED generated)
 		// MySQL docs: only ALGORITHM=COPY, no concurrent DML
 		{
+			ID:          "COL-ADD-STORED-GENERATED",
 			ActionType:  meta.ActionAddColumn,
 			Description: "ADD COLUMN (STORED generated)",
-			Condition: func(a meta.AlterAction, _ *meta.TableMeta) bool {
-				return a.Detail.GeneratedType == "STORED"
+			When:        "new column is STORED generated",
+			DocAnchor:   docGeneratedColumn,
+			Condition: func(a meta.AlterAction, _ *meta.TableMeta) (bool, string) {
+				return explain(a.Detail.GeneratedType == "STORED", "new column is STORED generated", "new column is not STORED generated")
 			},
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -48,10 +54,19 @@ func columnRules() []PredictionRule {
 		// Neither INSTANT nor INPLACE is available — falls back to COPY.
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-generated-column-operations
 		{
+			ID:          "COL-ADD-VIRTUAL-GENERATED-PARTITIONED",
 			ActionType:  meta.ActionAddColumn,
 			Description: "ADD COLUMN (VIRTUAL generated, partitioned table)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
-				return a.Detail.GeneratedType == "VIRTUAL" && tm != nil && tm.IsPartitioned
+			When:        "new column is VIRTUAL generated and the table is partitioned",
+			DocAnchor:   docGeneratedColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
+				if a.Detail.GeneratedType != "VIRTUAL" {
+					return false, "new column is not VIRTUAL generated"
+				}
+				if tm == nil {
+					return noTableMeta()
+				}
+				return explain(tm.IsPartitioned, "VIRTUAL generated column on a partitioned table", "table is not partitioned")
 			},
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -66,10 +81,13 @@ func columnRules() []PredictionRule {
 		// MySQL docs: INSTANT by default for non-partitioned tables
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-generated-column-operations
 		{
+			ID:          "COL-ADD-VIRTUAL-GENERATED",
 			ActionType:  meta.ActionAddColumn,
 			Description: "ADD COLUMN (VIRTUAL generated)",
-			Condition: func(a meta.AlterAction, _ *meta.TableMeta) bool {
-				return a.Detail.GeneratedType == "VIRTUAL"
+			When:        "new column is VIRTUAL generated",
+			DocAnchor:   docGeneratedColumn,
+			Condition: func(a meta.AlterAction, _ *meta.TableMeta) (bool, string) {
+				return explain(a.Detail.GeneratedType == "VIRTUAL", "new column is VIRTUAL generated", "new column is not VIRTUAL generated")
 			},
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
@@ -78,59 +96,116 @@ func columnRules() []PredictionRule {
 		},
 		// ADD COLUMN (trailing, NULLABLE)
 		{
+			ID:          "COL-ADD-TRAILING-NULLABLE",
 			ActionType:  meta.ActionAddColumn,
 			Description: "ADD COLUMN (trailing, NULLABLE)",
-			Condition: func(a meta.AlterAction, _ *meta.TableMeta) bool {
-				return a.Detail.Position == "" && isNullablePtr(a.Detail.IsNullable)
+			When:        "no FIRST/AFTER and column is nullable",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, _ *meta.TableMeta) (bool, string) {
+				return a.Detail.Position == "" && isNullablePtr(a.Detail.IsNullable), describeAddColumn(a)
 			},
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
 			TableRebuild: false,
+			Permitted:    instantOrInplaceRebuild(),
 			Notes:        []string{"INSTANT algorithm available (MySQL 8.0.12+)", "No table rebuild required", "DML operations are not blocked"},
+			MinVersion:   "8.0.12",
 		},
 		// ADD COLUMN (non-trailing, NULLABLE) — MySQL 8.0.29+
 		{
+			ID:          "COL-ADD-NONTRAILING-NULLABLE",
 			ActionType:  meta.ActionAddColumn,
 			Description: "ADD COLUMN (non-trailing, NULLABLE)",
-			Condition: func(a meta.AlterAction, _ *meta.TableMeta) bool {
-				return a.Detail.Position != "" && isNullablePtr(a.Detail.IsNullable)
+			When:        "FIRST/AFTER given and column is nullable",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, _ *meta.TableMeta) (bool, string) {
+				return a.Detail.Position != "" && isNullablePtr(a.Detail.IsNullable), describeAddColumn(a)
 			},
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
 			TableRebuild: false,
+			Permitted:    instantOrInplaceRebuild(),
 			Notes:        []string{"INSTANT algorithm available (MySQL 8.0.29+)", "No table rebuild required"},
+			MinVersion:   "8.0.29",
 		},
 		// ADD COLUMN (trailing, NOT NULL)
 		// MySQL 8.0.12+: INSTANT is available for NOT NULL columns with DEFAULT value
 		{
+			ID:          "COL-ADD-TRAILING-NOT-NULL",
 			ActionType:  meta.ActionAddColumn,
 			Description: "ADD COLUMN (trailing, NOT NULL)",
-			Condition: func(a meta.AlterAction, _ *meta.TableMeta) bool {
-				return a.Detail.Position == "" && !isNullablePtr(a.Detail.IsNullable)
+			When:        "no FIRST/AFTER and column is NOT NULL",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, _ *meta.TableMeta) (bool, string) {
+				return a.Detail.Position == "" && !isNullablePtr(a.Detail.IsNullable), describeAddColumn(a)
 			},
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
 			TableRebuild: false,
+			Permitted:    instantOrInplaceRebuild(),
 			Notes: []string{
 				"INSTANT algorithm available (MySQL 8.0.12+)",
 				"NOT NULL column requires a DEFAULT value (explicit or implicit)",
 			},
+			MinVersion: "8.0.12",
 		},
 		// ADD COLUMN (non-trailing, NOT NULL)
 		// MySQL 8.0.29+: INSTANT supports any position
 		{
+			ID:          "COL-ADD-NONTRAILING-NOT-NULL",
 			ActionType:  meta.ActionAddColumn,
 			Description: "ADD COLUMN (non-trailing, NOT NULL)",
-			Condition: func(a meta.AlterAction, _ *meta.TableMeta) bool {
-				return a.Detail.Position != "" && !isNullablePtr(a.Detail.IsNullable)
+			When:        "FIRST/AFTER given and column is NOT NULL",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, _ *meta.TableMeta) (bool, string) {
+				return a.Detail.Position != "" && !isNullablePtr(a.Detail.IsNullable), describeAddColumn(a)
 			},
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
 			TableRebuild: false,
+			Permitted:    instantOrInplaceRebuild(),
 			Notes: []string{
 				"INSTANT algorithm available (MySQL 8.0.29+)",
 				"NOT NULL column requires a DEFAULT value (explicit or implicit)",
 			},
+			MinVersion: "8.0.29",
+		},
+		// ADD COLUMN (trailing) — before MySQL 8.0.12
+		// INSTANT ADD COLUMN is not available; INPLACE rebuilds the table
+		// https://dev.mysql.com/doc/refman/5.7/en/innodb-online-ddl-operations.html#online-ddl-column-operations
+		{
+			ID:          "COL-ADD-TRAILING-LEGACY",
+			ActionType:  meta.ActionAddColumn,
+			Description: "ADD COLUMN (trailing, before 8.0.12)",
+			When:        "no FIRST/AFTER",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, _ *meta.TableMeta) (bool, string) {
+				return a.Detail.Position == "", describeAddColumn(a)
+			},
+			Algorithm:    meta.AlgorithmInplace,
+			Lock:         meta.LockNone,
+			TableRebuild: true,
+			Notes:        []string{"INSTANT ADD COLUMN requires MySQL 8.0.12+ — INPLACE with table rebuild"},
+			Warnings:     []string{"Table rebuild required — may take significant time for large tables"},
+			MaxVersion:   "8.0.12",
+		},
+		// ADD COLUMN (non-trailing) — before MySQL 8.0.29
+		// INSTANT ADD COLUMN at an arbitrary position was added in 8.0.29
+		{
+			ID:          "COL-ADD-NONTRAILING-LEGACY",
+			ActionType:  meta.ActionAddColumn,
+			Description: "ADD COLUMN (non-trailing, before 8.0.29)",
+			When:        "FIRST/AFTER given",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, _ *meta.TableMeta) (bool, string) {
+				return a.Detail.Position != "", describeAddColumn(a)
+			},
+			Algorithm:    meta.AlgorithmInplace,
+			Lock:         meta.LockNone,
+			TableRebuild: true,
+			Notes:        []string{"INSTANT ADD COLUMN with FIRST/AFTER requires MySQL 8.0.29+ — INPLACE with table rebuild"},
+			Warnings:     []string{"Table rebuild required — may take significant time for large tables"},
+			MaxVersion:   "8.0.29",
 		},
 
 		// ============================================================
@@ -139,11 +214,17 @@ func columnRules() []PredictionRule {
 
 		// DROP COLUMN (STORED generated — detected from metadata)
 		{
+			ID:          "COL-DROP-STORED-GENERATED",
 			ActionType:  meta.ActionDropColumn,
 			Description: "DROP COLUMN (STORED generated)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "column is STORED generated in metadata",
+			DocAnchor:   docGeneratedColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				col := findColumn(tm, a.Detail.ColumnName)
-				return col != nil && isStoredGenerated(col)
+				if col == nil {
+					return columnNotFound(a.Detail.ColumnName)
+				}
+				return isStoredGenerated(col), columnKind(col)
 			},
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -156,14 +237,23 @@ func columnRules() []PredictionRule {
 		// For partitioned tables, neither INSTANT nor INPLACE is available — falls back to COPY.
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-generated-column-operations
 		{
+			ID:          "COL-DROP-VIRTUAL-GENERATED-PARTITIONED",
 			ActionType:  meta.ActionDropColumn,
 			Description: "DROP COLUMN (VIRTUAL generated, partitioned table)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
-				if tm == nil || !tm.IsPartitioned {
-					return false
+			When:        "column is VIRTUAL generated in metadata and the table is partitioned",
+			DocAnchor:   docGeneratedColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
+				if tm == nil {
+					return noTableMeta()
+				}
+				if !tm.IsPartitioned {
+					return false, "table is not partitioned"
 				}
 				col := findColumn(tm, a.Detail.ColumnName)
-				return col != nil && isVirtualGenerated(col)
+				if col == nil {
+					return columnNotFound(a.Detail.ColumnName)
+				}
+				return isVirtualGenerated(col), "partitioned table, " + columnKind(col)
 			},
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -178,11 +268,17 @@ func columnRules() []PredictionRule {
 		// MySQL docs: INSTANT for non-partitioned tables
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-generated-column-operations
 		{
+			ID:          "COL-DROP-VIRTUAL-GENERATED",
 			ActionType:  meta.ActionDropColumn,
 			Description: "DROP COLUMN (VIRTUAL generated)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "column is VIRTUAL generated in metadata",
+			DocAnchor:   docGeneratedColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				col := findColumn(tm, a.Detail.ColumnName)
-				return col != nil && isVirtualGenerated(col)
+				if col == nil {
+					return columnNotFound(a.Detail.ColumnName)
+				}
+				return isVirtualGenerated(col), columnKind(col)
 			},
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
@@ -193,13 +289,30 @@ func columnRules() []PredictionRule {
 		// MySQL docs: INSTANT available (8.0.29+), rebuilds table
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-column-operations
 		{
+			ID:           "COL-DROP",
 			ActionType:   meta.ActionDropColumn,
 			Description:  "DROP COLUMN",
+			DocAnchor:    docColumn,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
 			TableRebuild: true,
 			Notes:        []string{"INSTANT algorithm available (MySQL 8.0.29+)", "Existing rows retain dropped column data until rewritten"},
+			MinVersion:   "8.0.29",
+		},
+		// DROP COLUMN (regular) — before MySQL 8.0.29
+		{
+			ID:           "COL-DROP-LEGACY",
+			ActionType:   meta.ActionDropColumn,
+			Description:  "DROP COLUMN (before 8.0.29)",
+			DocAnchor:    docColumn,
+			Condition:    alwaysMatch,
+			Algorithm:    meta.AlgorithmInplace,
+			Lock:         meta.LockNone,
+			TableRebuild: true,
+			Notes:        []string{"INSTANT DROP COLUMN requires MySQL 8.0.29+ — INPLACE with table rebuild"},
+			Warnings:     []string{"Table rebuild required — may take significant time for large tables"},
+			MaxVersion:   "8.0.29",
 		},
 
 		// ============================================================
@@ -210,14 +323,22 @@ func columnRules() []PredictionRule {
 		// MySQL docs: renaming a column referenced by FK requires INPLACE, not INSTANT
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-column-operations
 		{
+			ID:          "COL-RENAME-FK-REFERENCED",
 			ActionType:  meta.ActionRenameColumn,
 			Description: "RENAME COLUMN (referenced by foreign key)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "column is referenced by a foreign key of another table",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				colName := a.Detail.OldColumnName
 				if colName == "" {
 					colName = a.Detail.ColumnName
 				}
-				return isColumnReferencedByFK(colName, tm)
+				if tm == nil {
+					return noTableMeta()
+				}
+				return explain(isColumnReferencedByFK(colName, tm),
+					"column `"+colName+"` is referenced by a foreign key",
+					"no foreign key references column `"+colName+"`")
 			},
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -227,21 +348,39 @@ func columnRules() []PredictionRule {
 		// RENAME COLUMN (regular)
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-column-operations
 		{
+			ID:           "COL-RENAME",
 			ActionType:   meta.ActionRenameColumn,
 			Description:  "RENAME COLUMN",
+			DocAnchor:    docColumn,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
 			TableRebuild: false,
 			Notes:        []string{"INSTANT algorithm available (MySQL 8.0.28+)"},
+			MinVersion:   "8.0.28",
+		},
+		// RENAME COLUMN (regular) — before MySQL 8.0.28
+		{
+			ID:           "COL-RENAME-LEGACY",
+			ActionType:   meta.ActionRenameColumn,
+			Description:  "RENAME COLUMN (before 8.0.28)",
+			DocAnchor:    docColumn,
+			Condition:    alwaysMatch,
+			Algorithm:    meta.AlgorithmInplace,
+			Lock:         meta.LockNone,
+			TableRebuild: false,
+			Notes:        []string{"INSTANT RENAME COLUMN requires MySQL 8.0.28+ — INPLACE metadata change"},
+			MaxVersion:   "8.0.28",
 		},
 
 		// ============================================================
 		// ALTER COLUMN SET/DROP DEFAULT
 		// ============================================================
 		{
+			ID:           "COL-SET-DEFAULT",
 			ActionType:   meta.ActionSetDefault,
 			Description:  "ALTER COLUMN SET DEFAULT",
+			DocAnchor:    docColumn,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
@@ -249,8 +388,10 @@ func columnRules() []PredictionRule {
 			Notes:        []string{"Metadata-only change"},
 		},
 		{
+			ID:           "COL-DROP-DEFAULT",
 			ActionType:   meta.ActionDropDefault,
 			Description:  "ALTER COLUMN DROP DEFAULT",
+			DocAnchor:    docColumn,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
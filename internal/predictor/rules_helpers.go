@@ -1,6 +1,7 @@
 package predictor
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
@@ -47,6 +48,19 @@ func isHashOrKeyPartition(partitionType string) bool {
 	return pt == "HASH" || pt == "KEY" || pt == "LINEAR HASH" || pt == "LINEAR KEY"
 }
 
+// hashOrKeyPartitioned はテーブルがHASH/KEY系でパーティション分割されているかを判定する。
+func hashOrKeyPartitioned(_ meta.AlterAction, tm *meta.TableMeta) (bool, string) {
+	if tm == nil {
+		return noTableMeta()
+	}
+	if !tm.IsPartitioned && tm.PartitionType == "" {
+		return false, "table is not partitioned"
+	}
+	return explain(isHashOrKeyPartition(tm.PartitionType),
+		"partition type "+tm.PartitionType,
+		"partition type "+tm.PartitionType+" is not HASH/KEY")
+}
+
 // hasFulltextIndex はテーブルにFULLTEXTインデックスが存在するかを判定する。
 func hasFulltextIndex(tm *meta.TableMeta) bool {
 	if tm == nil {
@@ -80,3 +94,66 @@ func isEnumOrSetType(colType string) bool {
 	upper := strings.ToUpper(colType)
 	return strings.HasPrefix(upper, "ENUM") || strings.HasPrefix(upper, "SET")
 }
+
+// explain は条件の判定結果に応じた理由を返す。
+func explain(ok bool, ifTrue, ifFalse string) (bool, string) {
+	if ok {
+		return true, ifTrue
+	}
+	return false, ifFalse
+}
+
+// noTableMeta はテーブルのメタデータがないため条件を判定できない場合の結果を返す。
+func noTableMeta() (bool, string) {
+	return false, "no table metadata"
+}
+
+// columnNotFound は findColumn がカラムを見つけられなかった場合の結果を返す。
+func columnNotFound(name string) (bool, string) {
+	return false, fmt.Sprintf("findColumn returned nil: column `%s` not in metadata", name)
+}
+
+// columnKind はカラムの種類（通常・STORED/VIRTUAL 生成列）を説明する。
+func columnKind(col *meta.ColumnMeta) string {
+	switch {
+	case isStoredGenerated(col):
+		return "`" + col.Name + "` is a STORED generated column"
+	case isVirtualGenerated(col):
+		return "`" + col.Name + "` is a VIRTUAL generated column"
+	default:
+		return "`" + col.Name + "` is a regular column"
+	}
+}
+
+// nullLabel は NULL 許可の有無を表示用の文字列にする。
+func nullLabel(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+// describeAddColumn は ADD COLUMN の位置と NULL 許可を説明する。
+func describeAddColumn(a meta.AlterAction) string {
+	pos := "trailing (no FIRST/AFTER)"
+	if a.Detail.Position != "" {
+		pos = a.Detail.Position
+	}
+	return pos + ", " + nullLabel(isNullablePtr(a.Detail.IsNullable))
+}
+
+// typeChange は MODIFY/CHANGE COLUMN の型の変化を説明する。
+func typeChange(col *meta.ColumnMeta, newType string) string {
+	return fmt.Sprintf("old %s → new %s", col.ColumnType, newType)
+}
+
+// renameOnly は CHANGE COLUMN が型を変えずに名前だけを変更するかを判定する。
+func renameOnly(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
+	col := findColumn(tm, a.Detail.OldColumnName)
+	if col == nil {
+		return columnNotFound(a.Detail.OldColumnName)
+	}
+	return explain(strings.EqualFold(col.ColumnType, a.Detail.ColumnType),
+		fmt.Sprintf("same type %s — rename only", col.ColumnType),
+		"type changes: "+typeChange(col, a.Detail.ColumnType))
+}
@@ -13,8 +13,10 @@ func indexRules() []PredictionRule {
 
 		// ADD INDEX (secondary)
 		{
+			ID:           "IDX-ADD",
 			ActionType:   meta.ActionAddIndex,
 			Description:  "ADD INDEX",
+			DocAnchor:    docIndex,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -23,8 +25,10 @@ func indexRules() []PredictionRule {
 		},
 		// ADD UNIQUE INDEX
 		{
+			ID:           "IDX-ADD-UNIQUE",
 			ActionType:   meta.ActionAddUniqueIndex,
 			Description:  "ADD UNIQUE INDEX",
+			DocAnchor:    docIndex,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -33,10 +37,16 @@ func indexRules() []PredictionRule {
 		},
 		// ADD FULLTEXT INDEX (first FULLTEXT on table — may require rebuild)
 		{
+			ID:          "IDX-ADD-FULLTEXT-FIRST",
 			ActionType:  meta.ActionAddFulltextIndex,
 			Description: "ADD FULLTEXT INDEX (first on table)",
-			Condition: func(_ meta.AlterAction, tm *meta.TableMeta) bool {
-				return tm != nil && !hasFulltextIndex(tm)
+			When:        "table has no FULLTEXT index in metadata",
+			DocAnchor:   docIndex,
+			Condition: func(_ meta.AlterAction, tm *meta.TableMeta) (bool, string) {
+				if tm == nil {
+					return noTableMeta()
+				}
+				return explain(!hasFulltextIndex(tm), "table has no FULLTEXT index yet", "table already has a FULLTEXT index")
 			},
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockShared,
@@ -46,8 +56,10 @@ func indexRules() []PredictionRule {
 		},
 		// ADD FULLTEXT INDEX (subsequent — no rebuild)
 		{
+			ID:           "IDX-ADD-FULLTEXT",
 			ActionType:   meta.ActionAddFulltextIndex,
 			Description:  "ADD FULLTEXT INDEX",
+			DocAnchor:    docIndex,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockShared,
@@ -58,8 +70,10 @@ func indexRules() []PredictionRule {
 		// ADD SPATIAL INDEX
 		// MySQL docs: INPLACE, requires at minimum LOCK=SHARED, no concurrent DML
 		{
+			ID:           "IDX-ADD-SPATIAL",
 			ActionType:   meta.ActionAddSpatialIndex,
 			Description:  "ADD SPATIAL INDEX",
+			DocAnchor:    docIndex,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockShared,
@@ -69,8 +83,10 @@ func indexRules() []PredictionRule {
 		},
 		// DROP INDEX
 		{
+			ID:           "IDX-DROP",
 			ActionType:   meta.ActionDropIndex,
 			Description:  "DROP INDEX",
+			DocAnchor:    docIndex,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -79,8 +95,10 @@ func indexRules() []PredictionRule {
 		},
 		// RENAME INDEX
 		{
+			ID:           "IDX-RENAME",
 			ActionType:   meta.ActionRenameIndex,
 			Description:  "RENAME INDEX",
+			DocAnchor:    docIndex,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -96,8 +114,10 @@ func indexRules() []PredictionRule {
 		// MySQL docs: INPLACE, rebuilds table, concurrent DML permitted
 		// Note: INPLACE not permitted if columns need NULL→NOT NULL conversion
 		{
+			ID:           "PK-ADD",
 			ActionType:   meta.ActionAddPrimaryKey,
 			Description:  "ADD PRIMARY KEY",
+			DocAnchor:    docPrimaryKey,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -111,8 +131,10 @@ func indexRules() []PredictionRule {
 		// DROP PRIMARY KEY
 		// MySQL docs: only ALGORITHM=COPY, no concurrent DML (LOCK=NONE not permitted)
 		{
+			ID:           "PK-DROP",
 			ActionType:   meta.ActionDropPrimaryKey,
 			Description:  "DROP PRIMARY KEY",
+			DocAnchor:    docPrimaryKey,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -132,8 +154,10 @@ func indexRules() []PredictionRule {
 		// MySQL docs: INPLACE only when foreign_key_checks=OFF
 		// When foreign_key_checks=ON (default), only ALGORITHM=COPY
 		{
+			ID:           "FK-ADD",
 			ActionType:   meta.ActionAddForeignKey,
 			Description:  "ADD FOREIGN KEY",
+			DocAnchor:    docForeignKey,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -146,8 +170,10 @@ func indexRules() []PredictionRule {
 		},
 		// DROP FOREIGN KEY
 		{
+			ID:           "FK-DROP",
 			ActionType:   meta.ActionDropForeignKey,
 			Description:  "DROP FOREIGN KEY",
+			DocAnchor:    docForeignKey,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
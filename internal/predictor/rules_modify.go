@@ -16,14 +16,20 @@ func modifyRules() []PredictionRule {
 		// MODIFY COLUMN (generated column reorder — STORED or VIRTUAL)
 		// MySQL docs: modifying stored/virtual column order requires COPY, no concurrent DML
 		{
+			ID:          "COL-MODIFY-GENERATED-REORDER",
 			ActionType:  meta.ActionModifyColumn,
 			Description: "MODIFY COLUMN (generated column reorder)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "FIRST/AFTER given and column is generated in metadata",
+			DocAnchor:   docGeneratedColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				if a.Detail.Position == "" {
-					return false
+					return false, "no FIRST/AFTER"
 				}
 				col := findColumn(tm, a.Detail.ColumnName)
-				return col != nil && isGeneratedColumn(col)
+				if col == nil {
+					return columnNotFound(a.Detail.ColumnName)
+				}
+				return isGeneratedColumn(col), a.Detail.Position + ", " + columnKind(col)
 			},
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -35,26 +41,27 @@ func modifyRules() []PredictionRule {
 		},
 		// MODIFY COLUMN (ENUM/SET extension — add values at end, same storage size)
 		{
+			ID:          "COL-MODIFY-ENUM-SET-EXTEND",
 			ActionType:  meta.ActionModifyColumn,
 			Description: "MODIFY COLUMN (ENUM/SET extension)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "old and new types are both ENUM or both SET",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				newType := strings.ToUpper(a.Detail.ColumnType)
 				if !isEnumOrSetType(newType) {
-					return false
+					return false, "new type " + a.Detail.ColumnType + " is not ENUM/SET"
 				}
 				col := findColumn(tm, a.Detail.ColumnName)
 				if col == nil {
-					return false
+					return columnNotFound(a.Detail.ColumnName)
 				}
 				oldType := strings.ToUpper(col.ColumnType)
 				// Both must be same base type (ENUM or SET)
-				if strings.HasPrefix(newType, "ENUM") != strings.HasPrefix(oldType, "ENUM") {
-					return false
+				if strings.HasPrefix(newType, "ENUM") != strings.HasPrefix(oldType, "ENUM") ||
+					strings.HasPrefix(newType, "SET") != strings.HasPrefix(oldType, "SET") {
+					return false, typeChange(col, a.Detail.ColumnType) + " is not an ENUM → ENUM or SET → SET change"
 				}
-				if strings.HasPrefix(newType, "SET") != strings.HasPrefix(oldType, "SET") {
-					return false
-				}
-				return true
+				return true, typeChange(col, a.Detail.ColumnType)
 			},
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
@@ -67,23 +74,32 @@ func modifyRules() []PredictionRule {
 		// MODIFY COLUMN (VARCHAR extension — same length-byte boundary)
 		// MySQL docs: in-place when staying within same length-byte boundary (0-255 vs 256+)
 		{
+			ID:          "COL-MODIFY-VARCHAR-EXTEND",
 			ActionType:  meta.ActionModifyColumn,
 			Description: "MODIFY COLUMN (VARCHAR extension)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "VARCHAR length grows without crossing the 255/256 length-byte boundary",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				newLen := extractVarcharLength(a.Detail.ColumnType)
 				if newLen <= 0 {
-					return false
+					return false, "new type " + a.Detail.ColumnType + " is not VARCHAR"
 				}
 				col := findColumn(tm, a.Detail.ColumnName)
 				if col == nil {
-					return false
+					return columnNotFound(a.Detail.ColumnName)
 				}
 				oldLen := extractVarcharLength(col.ColumnType)
-				if oldLen <= 0 || newLen <= oldLen {
-					return false
+				if oldLen <= 0 {
+					return false, "old type " + col.ColumnType + " is not VARCHAR"
+				}
+				change := typeChange(col, a.Detail.ColumnType)
+				if newLen <= oldLen {
+					return false, change + " does not extend the length"
 				}
 				// Both within 0-255 or both within 256+
-				return (oldLen <= 255 && newLen <= 255) || (oldLen >= 256 && newLen >= 256)
+				return explain((oldLen <= 255 && newLen <= 255) || (oldLen >= 256 && newLen >= 256),
+					change+" stays within the same length-byte boundary",
+					change+" crosses 255 boundary")
 			},
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -95,15 +111,21 @@ func modifyRules() []PredictionRule {
 		},
 		// MODIFY COLUMN (NULL → NOT NULL, same type)
 		{
+			ID:          "COL-MODIFY-SET-NOT-NULL",
 			ActionType:  meta.ActionModifyColumn,
 			Description: "MODIFY COLUMN (NULL → NOT NULL)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "same type, nullable in metadata and NOT NULL in the new definition",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				col := findColumn(tm, a.Detail.ColumnName)
 				if col == nil {
-					return false
+					return columnNotFound(a.Detail.ColumnName)
 				}
-				sameType := strings.EqualFold(col.ColumnType, a.Detail.ColumnType)
-				return sameType && col.IsNullable && !isNullablePtr(a.Detail.IsNullable)
+				if !strings.EqualFold(col.ColumnType, a.Detail.ColumnType) {
+					return false, "type changes: " + typeChange(col, a.Detail.ColumnType)
+				}
+				newNullable := isNullablePtr(a.Detail.IsNullable)
+				return col.IsNullable && !newNullable, "same type, " + nullLabel(col.IsNullable) + " → " + nullLabel(newNullable)
 			},
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -117,15 +139,21 @@ func modifyRules() []PredictionRule {
 		// MODIFY COLUMN (NOT NULL → NULL, same type)
 		// MySQL docs: INPLACE, rebuilds table, concurrent DML permitted
 		{
+			ID:          "COL-MODIFY-SET-NULL",
 			ActionType:  meta.ActionModifyColumn,
 			Description: "MODIFY COLUMN (NOT NULL → NULL)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "same type, NOT NULL in metadata and nullable in the new definition",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				col := findColumn(tm, a.Detail.ColumnName)
 				if col == nil {
-					return false
+					return columnNotFound(a.Detail.ColumnName)
+				}
+				if !strings.EqualFold(col.ColumnType, a.Detail.ColumnType) {
+					return false, "type changes: " + typeChange(col, a.Detail.ColumnType)
 				}
-				sameType := strings.EqualFold(col.ColumnType, a.Detail.ColumnType)
-				return sameType && !col.IsNullable && isNullablePtr(a.Detail.IsNullable)
+				newNullable := isNullablePtr(a.Detail.IsNullable)
+				return !col.IsNullable && newNullable, "same type, " + nullLabel(col.IsNullable) + " → " + nullLabel(newNullable)
 			},
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -136,20 +164,24 @@ func modifyRules() []PredictionRule {
 		// MODIFY COLUMN (reorder only — same type, same nullability, position change)
 		// MySQL docs: INPLACE, rebuilds table, concurrent DML permitted
 		{
+			ID:          "COL-MODIFY-REORDER",
 			ActionType:  meta.ActionModifyColumn,
 			Description: "MODIFY COLUMN (reorder columns)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "FIRST/AFTER given with the same type and nullability",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				if a.Detail.Position == "" {
-					return false
+					return false, "no FIRST/AFTER"
 				}
 				col := findColumn(tm, a.Detail.ColumnName)
 				if col == nil {
-					return false
+					return columnNotFound(a.Detail.ColumnName)
 				}
 				if !strings.EqualFold(col.ColumnType, a.Detail.ColumnType) {
-					return false
+					return false, "type changes: " + typeChange(col, a.Detail.ColumnType)
 				}
-				return col.IsNullable == isNullablePtr(a.Detail.IsNullable)
+				newNullable := isNullablePtr(a.Detail.IsNullable)
+				return col.IsNullable == newNullable, a.Detail.Position + ", same type, " + nullLabel(col.IsNullable) + " → " + nullLabel(newNullable)
 			},
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -160,14 +192,20 @@ func modifyRules() []PredictionRule {
 		// MODIFY COLUMN (type change — with metadata confirmation)
 		// MySQL docs: only ALGORITHM=COPY, no concurrent DML
 		{
+			ID:          "COL-MODIFY-TYPE-CHANGE",
 			ActionType:  meta.ActionModifyColumn,
 			Description: "MODIFY COLUMN (type change)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "type differs from metadata, or column not in metadata",
+			DocAnchor:   docColumn,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				col := findColumn(tm, a.Detail.ColumnName)
 				if col == nil {
-					return true // no metadata — assume type change (conservative)
+					// no metadata — assume type change (conservative)
+					return true, "findColumn returned nil: column `" + a.Detail.ColumnName + "` not in metadata — assuming a type change"
 				}
-				return !strings.EqualFold(col.ColumnType, a.Detail.ColumnType)
+				return explain(!strings.EqualFold(col.ColumnType, a.Detail.ColumnType),
+					"type changes: "+typeChange(col, a.Detail.ColumnType),
+					"same type "+col.ColumnType)
 			},
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -181,8 +219,10 @@ func modifyRules() []PredictionRule {
 		// MODIFY COLUMN (fallback — same type, no specific sub-case matched)
 		// Treats as null rebuild (same type re-specification)
 		{
+			ID:           "COL-MODIFY-REBUILD",
 			ActionType:   meta.ActionModifyColumn,
 			Description:  "MODIFY COLUMN (rebuild)",
+			DocAnchor:    docColumn,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -198,22 +238,39 @@ func modifyRules() []PredictionRule {
 		// CHANGE COLUMN (rename only — same type, detected from metadata)
 		// MySQL docs: INSTANT (8.0.28+) when keeping same data type and only changing column name
 		{
-			ActionType:  meta.ActionChangeColumn,
-			Description: "CHANGE COLUMN (rename only)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
-				col := findColumn(tm, a.Detail.OldColumnName)
-				return col != nil && strings.EqualFold(col.ColumnType, a.Detail.ColumnType)
-			},
+			ID:           "COL-CHANGE-RENAME",
+			ActionType:   meta.ActionChangeColumn,
+			Description:  "CHANGE COLUMN (rename only)",
+			When:         "old column exists in metadata with the same type",
+			DocAnchor:    docColumn,
+			Condition:    renameOnly,
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
 			TableRebuild: false,
 			Notes:        []string{"INSTANT algorithm available (MySQL 8.0.28+) — rename only, same data type"},
+			MinVersion:   "8.0.28",
+		},
+		// CHANGE COLUMN (rename only) — before MySQL 8.0.28
+		{
+			ID:           "COL-CHANGE-RENAME-LEGACY",
+			ActionType:   meta.ActionChangeColumn,
+			Description:  "CHANGE COLUMN (rename only, before 8.0.28)",
+			When:         "old column exists in metadata with the same type",
+			DocAnchor:    docColumn,
+			Condition:    renameOnly,
+			Algorithm:    meta.AlgorithmInplace,
+			Lock:         meta.LockNone,
+			TableRebuild: false,
+			Notes:        []string{"INSTANT rename requires MySQL 8.0.28+ — INPLACE metadata change"},
+			MaxVersion:   "8.0.28",
 		},
 		// CHANGE COLUMN (type change — fallback)
 		// MySQL docs: only ALGORITHM=COPY, no concurrent DML
 		{
+			ID:           "COL-CHANGE-TYPE-CHANGE",
 			ActionType:   meta.ActionChangeColumn,
 			Description:  "CHANGE COLUMN (type change)",
+			DocAnchor:    docColumn,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -15,11 +15,12 @@ func partitionRules() []PredictionRule {
 		// MySQL docs: INPLACE, no concurrent DML, LOCK=SHARED minimum
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-partitioning-operations
 		{
-			ActionType:  meta.ActionAddPartition,
-			Description: "ADD PARTITION (HASH/KEY)",
-			Condition: func(_ meta.AlterAction, tm *meta.TableMeta) bool {
-				return tm != nil && isHashOrKeyPartition(tm.PartitionType)
-			},
+			ID:           "PART-ADD-HASH-KEY",
+			ActionType:   meta.ActionAddPartition,
+			Description:  "ADD PARTITION (HASH/KEY)",
+			When:         "table is HASH/KEY partitioned in metadata",
+			DocAnchor:    docPartition,
+			Condition:    hashOrKeyPartitioned,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockShared,
 			TableRebuild: false,
@@ -30,8 +31,10 @@ func partitionRules() []PredictionRule {
 		// MySQL docs: INPLACE, concurrent DML permitted, LOCK=NONE allowed
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-partitioning-operations
 		{
+			ID:           "PART-ADD",
 			ActionType:   meta.ActionAddPartition,
 			Description:  "ADD PARTITION",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -45,11 +48,12 @@ func partitionRules() []PredictionRule {
 		// MySQL docs: INPLACE, no concurrent DML, LOCK=SHARED minimum
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-partitioning-operations
 		{
-			ActionType:  meta.ActionDropPartition,
-			Description: "DROP PARTITION (HASH/KEY)",
-			Condition: func(_ meta.AlterAction, tm *meta.TableMeta) bool {
-				return tm != nil && isHashOrKeyPartition(tm.PartitionType)
-			},
+			ID:           "PART-DROP-HASH-KEY",
+			ActionType:   meta.ActionDropPartition,
+			Description:  "DROP PARTITION (HASH/KEY)",
+			When:         "table is HASH/KEY partitioned in metadata",
+			DocAnchor:    docPartition,
+			Condition:    hashOrKeyPartitioned,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockShared,
 			TableRebuild: false,
@@ -60,8 +64,10 @@ func partitionRules() []PredictionRule {
 		// MySQL docs: INPLACE, concurrent DML permitted
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-partitioning-operations
 		{
+			ID:           "PART-DROP",
 			ActionType:   meta.ActionDropPartition,
 			Description:  "DROP PARTITION",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -72,8 +78,10 @@ func partitionRules() []PredictionRule {
 		// TRUNCATE PARTITION
 		// MySQL docs: INPLACE, concurrent DML permitted
 		{
+			ID:           "PART-TRUNCATE",
 			ActionType:   meta.ActionTruncatePartition,
 			Description:  "TRUNCATE PARTITION",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -83,8 +91,10 @@ func partitionRules() []PredictionRule {
 		// EXCHANGE PARTITION
 		// MySQL docs: INPLACE, concurrent DML permitted
 		{
+			ID:           "PART-EXCHANGE",
 			ActionType:   meta.ActionExchangePartition,
 			Description:  "EXCHANGE PARTITION",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -94,8 +104,10 @@ func partitionRules() []PredictionRule {
 		// COALESCE PARTITION
 		// MySQL docs: INPLACE, no concurrent DML (LOCK=SHARED minimum)
 		{
+			ID:           "PART-COALESCE",
 			ActionType:   meta.ActionCoalescePartition,
 			Description:  "COALESCE PARTITION",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockShared,
@@ -106,8 +118,10 @@ func partitionRules() []PredictionRule {
 		// REORGANIZE PARTITION
 		// MySQL docs: INPLACE, no concurrent DML (LOCK=SHARED minimum)
 		{
+			ID:           "PART-REORGANIZE",
 			ActionType:   meta.ActionReorganizePartition,
 			Description:  "REORGANIZE PARTITION",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockShared,
@@ -118,8 +132,10 @@ func partitionRules() []PredictionRule {
 		// REBUILD PARTITION
 		// MySQL docs: INPLACE, no concurrent DML (LOCK=SHARED minimum)
 		{
+			ID:           "PART-REBUILD",
 			ActionType:   meta.ActionRebuildPartition,
 			Description:  "REBUILD PARTITION",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockShared,
@@ -129,8 +145,10 @@ func partitionRules() []PredictionRule {
 		// PARTITION BY
 		// MySQL docs: only ALGORITHM=COPY, no concurrent DML
 		{
+			ID:           "PART-PARTITION-BY",
 			ActionType:   meta.ActionPartitionBy,
 			Description:  "PARTITION BY",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -144,8 +162,10 @@ func partitionRules() []PredictionRule {
 		// MySQL docs: only ALGORITHM=COPY, no concurrent DML
 		// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-partitioning-operations
 		{
+			ID:           "PART-REMOVE",
 			ActionType:   meta.ActionRemovePartitioning,
 			Description:  "REMOVE PARTITIONING",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -162,8 +182,10 @@ func partitionRules() []PredictionRule {
 
 		// CHECK PARTITION
 		{
+			ID:           "PART-CHECK",
 			ActionType:   meta.ActionCheckPartition,
 			Description:  "CHECK PARTITION",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -173,8 +195,10 @@ func partitionRules() []PredictionRule {
 		// OPTIMIZE PARTITION
 		// MySQL docs: ALGORITHM and LOCK clauses ignored, rebuilds entire table
 		{
+			ID:           "PART-OPTIMIZE",
 			ActionType:   meta.ActionOptimizePartition,
 			Description:  "OPTIMIZE PARTITION",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -187,8 +211,10 @@ func partitionRules() []PredictionRule {
 		},
 		// REPAIR PARTITION
 		{
+			ID:           "PART-REPAIR",
 			ActionType:   meta.ActionRepairPartition,
 			Description:  "REPAIR PARTITION",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -197,8 +223,10 @@ func partitionRules() []PredictionRule {
 		},
 		// DISCARD PARTITION TABLESPACE
 		{
+			ID:           "PART-DISCARD-TABLESPACE",
 			ActionType:   meta.ActionDiscardPartitionTablespace,
 			Description:  "DISCARD PARTITION TABLESPACE",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockExclusive,
@@ -208,8 +236,10 @@ func partitionRules() []PredictionRule {
 		},
 		// IMPORT PARTITION TABLESPACE
 		{
+			ID:           "PART-IMPORT-TABLESPACE",
 			ActionType:   meta.ActionImportPartitionTablespace,
 			Description:  "IMPORT PARTITION TABLESPACE",
+			DocAnchor:    docPartition,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockExclusive,
@@ -15,8 +15,10 @@ func tableRules() []PredictionRule {
 
 		// RENAME TABLE
 		{
+			ID:           "TBL-RENAME",
 			ActionType:   meta.ActionRenameTable,
 			Description:  "RENAME TABLE",
+			DocAnchor:    docTable,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInstant,
 			Lock:         meta.LockNone,
@@ -26,13 +28,18 @@ func tableRules() []PredictionRule {
 		// CHANGE ENGINE (same engine — null rebuild)
 		// MySQL docs: INPLACE, rebuilds table, concurrent DML permitted
 		{
+			ID:          "TBL-ENGINE-SAME",
 			ActionType:  meta.ActionChangeEngine,
 			Description: "CHANGE ENGINE (same engine — null rebuild)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "target engine equals the current engine in metadata",
+			DocAnchor:   docTable,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				if tm == nil {
-					return false
+					return noTableMeta()
 				}
-				return strings.EqualFold(a.Detail.Engine, tm.Engine)
+				return explain(strings.EqualFold(a.Detail.Engine, tm.Engine),
+					"target engine "+a.Detail.Engine+" equals current engine "+tm.Engine,
+					"target engine "+a.Detail.Engine+" differs from current engine "+tm.Engine)
 			},
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -42,13 +49,18 @@ func tableRules() []PredictionRule {
 		// CHANGE ENGINE (different engine)
 		// Full table copy to new engine format
 		{
+			ID:          "TBL-ENGINE-CHANGE",
 			ActionType:  meta.ActionChangeEngine,
 			Description: "CHANGE ENGINE (different engine)",
-			Condition: func(a meta.AlterAction, tm *meta.TableMeta) bool {
+			When:        "target engine differs from metadata, or no metadata",
+			DocAnchor:   docTable,
+			Condition: func(a meta.AlterAction, tm *meta.TableMeta) (bool, string) {
 				if tm == nil {
-					return true
+					return true, "no table metadata — assuming a different engine"
 				}
-				return !strings.EqualFold(a.Detail.Engine, tm.Engine)
+				return explain(!strings.EqualFold(a.Detail.Engine, tm.Engine),
+					"target engine "+a.Detail.Engine+" differs from current engine "+tm.Engine,
+					"target engine "+a.Detail.Engine+" equals current engine "+tm.Engine)
 			},
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
@@ -61,8 +73,10 @@ func tableRules() []PredictionRule {
 		// CONVERT CHARACTER SET
 		// MySQL docs: INPLACE, rebuilds table, concurrent DML NOT permitted
 		{
+			ID:           "TBL-CONVERT-CHARSET",
 			ActionType:   meta.ActionConvertCharset,
 			Description:  "CONVERT CHARACTER SET",
+			DocAnchor:    docTable,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockShared,
@@ -76,8 +90,10 @@ func tableRules() []PredictionRule {
 		// CHANGE ROW_FORMAT
 		// MySQL docs: INPLACE, rebuilds table, concurrent DML permitted
 		{
+			ID:           "TBL-ROW-FORMAT",
 			ActionType:   meta.ActionChangeRowFormat,
 			Description:  "CHANGE ROW_FORMAT",
+			DocAnchor:    docTable,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -87,8 +103,10 @@ func tableRules() []PredictionRule {
 		// CHANGE KEY_BLOCK_SIZE
 		// MySQL docs: INPLACE, rebuilds table, concurrent DML permitted
 		{
+			ID:           "TBL-KEY-BLOCK-SIZE",
 			ActionType:   meta.ActionChangeKeyBlockSize,
 			Description:  "CHANGE KEY_BLOCK_SIZE",
+			DocAnchor:    docTable,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -98,8 +116,10 @@ func tableRules() []PredictionRule {
 		// CHANGE AUTO_INCREMENT value
 		// MySQL docs: INPLACE, no rebuild, concurrent DML permitted
 		{
+			ID:           "TBL-AUTO-INCREMENT",
 			ActionType:   meta.ActionChangeAutoIncrement,
 			Description:  "CHANGE AUTO_INCREMENT value",
+			DocAnchor:    docTable,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -109,8 +129,10 @@ func tableRules() []PredictionRule {
 		// FORCE REBUILD (ALTER TABLE ... FORCE)
 		// MySQL docs: INPLACE, rebuilds table, concurrent DML permitted
 		{
+			ID:           "TBL-FORCE",
 			ActionType:   meta.ActionForceRebuild,
 			Description:  "ALTER TABLE ... FORCE (rebuild)",
+			DocAnchor:    docTable,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -124,8 +146,10 @@ func tableRules() []PredictionRule {
 		// Different from CONVERT TO CHARACTER SET
 		// ============================================================
 		{
+			ID:           "TBL-DEFAULT-CHARSET",
 			ActionType:   meta.ActionSpecifyCharset,
 			Description:  "SPECIFY CHARACTER SET",
+			DocAnchor:    docTable,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -141,8 +165,10 @@ func tableRules() []PredictionRule {
 		// SET TABLE STATISTICS
 		// ============================================================
 		{
+			ID:           "TBL-STATS",
 			ActionType:   meta.ActionSetTableStats,
 			Description:  "SET TABLE STATISTICS",
+			DocAnchor:    docTable,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmInplace,
 			Lock:         meta.LockNone,
@@ -154,8 +180,10 @@ func tableRules() []PredictionRule {
 		// TABLE ENCRYPTION
 		// ============================================================
 		{
+			ID:           "TBL-ENCRYPTION",
 			ActionType:   meta.ActionTableEncryption,
 			Description:  "TABLE ENCRYPTION",
+			DocAnchor:    docTablespace,
 			Condition:    alwaysMatch,
 			Algorithm:    meta.AlgorithmCopy,
 			Lock:         meta.LockShared,
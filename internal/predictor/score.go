@@ -0,0 +1,248 @@
+package predictor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// リスクスコアの要因名。
+const (
+	FactorAlgorithm     = "algorithm"
+	FactorTableRebuild  = "table_rebuild"
+	FactorLock          = "lock"
+	FactorTableSize     = "table_size"
+	FactorRowCount      = "row_count"
+	FactorDuration      = "duration"
+	FactorFKPropagation = "fk_propagation"
+)
+
+// maxRiskScore はリスクスコアの上限。
+const maxRiskScore = 100
+
+// fkPointsPerTable はMDLが伝播するテーブル1つあたりの加点、fkMaxPoints はその上限。
+const (
+	fkPointsPerTable = 3
+	fkMaxPoints      = 15
+)
+
+// RiskThresholds はリスクスコアを RiskLevel に変換する閾値（各レベルの下限スコア）。
+type RiskThresholds struct {
+	Medium   int `json:"medium"`
+	High     int `json:"high"`
+	Critical int `json:"critical"`
+}
+
+// DefaultRiskThresholds はデフォルトの閾値を返す。
+// テーブル統計がない場合、アルゴリズム・ロック・再構築のみで従来と同じレベルになる値にしている。
+func DefaultRiskThresholds() RiskThresholds {
+	return RiskThresholds{Medium: 20, High: 45, Critical: 70}
+}
+
+// ParseRiskThresholds は "20,45,70" 形式（MEDIUM,HIGH,CRITICAL の下限スコア）の閾値を解析する。
+func ParseRiskThresholds(s string) (RiskThresholds, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return RiskThresholds{}, fmt.Errorf("expected MEDIUM,HIGH,CRITICAL scores (e.g. 20,45,70), got %q", s)
+	}
+	var values [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return RiskThresholds{}, fmt.Errorf("invalid score %q: %w", part, err)
+		}
+		values[i] = n
+	}
+	t := RiskThresholds{Medium: values[0], High: values[1], Critical: values[2]}
+	if err := t.Validate(); err != nil {
+		return RiskThresholds{}, err
+	}
+	return t, nil
+}
+
+// Validate は閾値が 0 < Medium < High < Critical を満たすかを検証する。
+func (t RiskThresholds) Validate() error {
+	if t.Medium <= 0 || t.Medium >= t.High || t.High >= t.Critical {
+		return fmt.Errorf("risk thresholds must satisfy 0 < medium < high < critical (got %d,%d,%d)", t.Medium, t.High, t.Critical)
+	}
+	return nil
+}
+
+// Level はスコアに対応するリスクレベルを返す。
+func (t RiskThresholds) Level(score int) meta.RiskLevel {
+	switch {
+	case score >= t.Critical:
+		return meta.RiskCritical
+	case score >= t.High:
+		return meta.RiskHigh
+	case score >= t.Medium:
+		return meta.RiskMedium
+	default:
+		return meta.RiskLow
+	}
+}
+
+// RiskFactor はリスクスコアの要因と加点（負の値は減点）を表す。
+type RiskFactor struct {
+	Name   string `json:"name"`
+	Points int    `json:"points"`
+	Detail string `json:"detail"`
+}
+
+// RiskScore は要因ごとの内訳を持つ数値のリスクスコアを表す。
+type RiskScore struct {
+	Score   int          `json:"score"`
+	Factors []RiskFactor `json:"factors"`
+	// 以下はアルゴリズム等が変わった場合（FK伝播の反映や文全体の判定）の再計算に使う
+	thresholds RiskThresholds
+	size       []RiskFactor // テーブル統計による要因（データに触れない操作ではスコアに含めない）
+	fk         *RiskFactor
+}
+
+// Level はスコアに対応するリスクレベルを返す。
+func (s *RiskScore) Level() meta.RiskLevel {
+	return s.thresholds.Level(s.Score)
+}
+
+// scoreRisk は予測とテーブル統計からリスクスコアを計算する。
+func scoreRisk(thresholds RiskThresholds, pred Prediction, tableMeta *meta.TableMeta) *RiskScore {
+	s := &RiskScore{thresholds: thresholds, size: sizeFactors(tableMeta, pred.EstimatedDuration)}
+	s.build(pred.Algorithm, pred.Lock, pred.TableRebuild, touchesData(pred))
+	return s
+}
+
+// build は要因を組み立ててスコアを計算する（0〜maxRiskScore に丸める）。
+// テーブルサイズ・行数・推定時間は、テーブルのデータに触れる操作（再構築・インデックス作成）のみで考慮する。
+func (s *RiskScore) build(algorithm meta.Algorithm, lock meta.LockLevel, rebuild, touchesData bool) {
+	s.Factors = operationFactors(algorithm, lock, rebuild)
+	if touchesData {
+		s.Factors = append(s.Factors, s.size...)
+	}
+	if s.fk != nil {
+		s.Factors = append(s.Factors, *s.fk)
+	}
+	score := 0
+	for _, f := range s.Factors {
+		score += f.Points
+	}
+	s.Score = min(max(score, 0), maxRiskScore)
+}
+
+// sizePoints はテーブル統計による要因の合計を返す。
+func (s *RiskScore) sizePoints() int {
+	points := 0
+	for _, f := range s.size {
+		points += f.Points
+	}
+	return points
+}
+
+// operationFactors はアルゴリズム・ロック・再構築の有無による要因を返す。
+func operationFactors(algorithm meta.Algorithm, lock meta.LockLevel, rebuild bool) []RiskFactor {
+	var factors []RiskFactor
+	switch algorithm {
+	case meta.AlgorithmInplace:
+		factors = append(factors, RiskFactor{FactorAlgorithm, 25, "ALGORITHM=INPLACE"})
+	case meta.AlgorithmCopy:
+		factors = append(factors, RiskFactor{FactorAlgorithm, 70, "ALGORITHM=COPY — table is copied row by row"})
+	default:
+		factors = append(factors, RiskFactor{FactorAlgorithm, 0, "ALGORITHM=" + string(algorithm)})
+	}
+	if rebuild && algorithm == meta.AlgorithmInplace {
+		factors = append(factors, RiskFactor{FactorTableRebuild, 20, "Table rebuild"})
+	}
+	switch lock {
+	case meta.LockShared:
+		factors = append(factors, RiskFactor{FactorLock, 10, "LOCK=SHARED — DML writes blocked"})
+	case meta.LockExclusive:
+		factors = append(factors, RiskFactor{FactorLock, 45, "LOCK=EXCLUSIVE — all DML blocked"})
+	}
+	return factors
+}
+
+// sizeFactors はテーブルサイズ・行数・推定時間による要因を返す。統計がない場合は何も返さない。
+func sizeFactors(tableMeta *meta.TableMeta, duration *DurationEstimate) []RiskFactor {
+	if tableMeta == nil || (tableMeta.DataLength == 0 && tableMeta.IndexLength == 0 && tableMeta.RowCount == 0) {
+		return nil
+	}
+	var factors []RiskFactor
+
+	size := tableMeta.DataLength + tableMeta.IndexLength
+	detail := "data+index " + FormatSize(size)
+	switch {
+	case size < MB:
+		factors = append(factors, RiskFactor{FactorTableSize, -25, detail})
+	case size < 100*MB:
+		factors = append(factors, RiskFactor{FactorTableSize, -10, detail})
+	case size < GB:
+		factors = append(factors, RiskFactor{FactorTableSize, 0, detail})
+	case size < 10*GB:
+		factors = append(factors, RiskFactor{FactorTableSize, 10, detail})
+	case size < 100*GB:
+		factors = append(factors, RiskFactor{FactorTableSize, 20, detail})
+	default:
+		factors = append(factors, RiskFactor{FactorTableSize, 30, detail})
+	}
+
+	rows := "~" + formatCount(tableMeta.RowCount) + " rows"
+	switch {
+	case tableMeta.RowCount >= 100_000_000:
+		factors = append(factors, RiskFactor{FactorRowCount, 10, rows})
+	case tableMeta.RowCount >= 10_000_000:
+		factors = append(factors, RiskFactor{FactorRowCount, 5, rows})
+	}
+
+	if duration != nil {
+		switch {
+		case duration.MaxSec >= 3600:
+			factors = append(factors, RiskFactor{FactorDuration, 10, "up to " + FormatDuration(duration.MaxSec)})
+		case duration.MaxSec >= 600:
+			factors = append(factors, RiskFactor{FactorDuration, 5, "up to " + FormatDuration(duration.MaxSec)})
+		}
+	}
+	return factors
+}
+
+// touchesData は操作がテーブルのデータを読み書きするか（再構築・インデックス作成）を返す。
+func touchesData(pred Prediction) bool {
+	if pred.Algorithm == meta.AlgorithmInstant {
+		return false
+	}
+	return pred.TableRebuild || pred.Algorithm == meta.AlgorithmCopy || isIndexBuild(pred.ActionType)
+}
+
+// rescore は予測のアルゴリズム・ロック・再構築が変更された後にスコアとリスクレベルを再計算する。
+func rescore(pred *Prediction) {
+	if pred.RiskScore == nil {
+		pred.RiskLevel = calculateRisk(pred.Algorithm, pred.Lock, pred.TableRebuild)
+		return
+	}
+	score := *pred.RiskScore
+	score.build(pred.Algorithm, pred.Lock, pred.TableRebuild, touchesData(*pred))
+	pred.RiskScore = &score
+	pred.RiskLevel = score.Level()
+}
+
+// ApplyFKPropagation はFK依存関係の解決結果（MDLが伝播するテーブル数）をリスクスコアに反映する。
+func ApplyFKPropagation(predictions []Prediction, affectedTables int) {
+	if affectedTables <= 0 {
+		return
+	}
+	factor := RiskFactor{
+		Name:   FactorFKPropagation,
+		Points: min(affectedTables*fkPointsPerTable, fkMaxPoints),
+		Detail: fmt.Sprintf("MDL propagates to %d related table(s)", affectedTables),
+	}
+	for i := range predictions {
+		pred := &predictions[i]
+		if pred.RiskScore == nil {
+			continue
+		}
+		score := *pred.RiskScore
+		score.fk = &factor
+		pred.RiskScore = &score
+		rescore(pred)
+	}
+}
@@ -0,0 +1,111 @@
+package predictor
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func TestRiskScoreTableSize(t *testing.T) {
+	// 同じ INPLACE + 再構築でもテーブルサイズによってリスクレベルが変わることを検証
+	force := meta.AlterAction{Type: meta.ActionForceRebuild}
+	tests := []struct {
+		name      string
+		tableMeta *meta.TableMeta
+		want      meta.RiskLevel
+	}{
+		{"統計なし", nil, meta.RiskHigh},
+		{"2KBの参照テーブル", &meta.TableMeta{Engine: "InnoDB", RowCount: 20, DataLength: 2 * KB}, meta.RiskMedium},
+		{"500MBのテーブル", &meta.TableMeta{Engine: "InnoDB", RowCount: 1_000_000, DataLength: 500 * MB}, meta.RiskHigh},
+		{"400GBのテーブル", &meta.TableMeta{Engine: "InnoDB", RowCount: 2_000_000_000, DataLength: 400 * GB}, meta.RiskCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pred := New().Predict(force, tt.tableMeta)
+			if pred.RiskLevel != tt.want {
+				t.Errorf("リスクレベルが %s であること: got %s (score %d, factors %+v)", tt.want, pred.RiskLevel, pred.RiskScore.Score, pred.RiskScore.Factors)
+			}
+		})
+	}
+}
+
+func TestRiskScoreMetadataOnlyIgnoresSize(t *testing.T) {
+	// データに触れない操作はテーブルサイズを考慮しないことを検証
+	action := meta.AlterAction{
+		Type:   meta.ActionAddColumn,
+		Detail: meta.ActionDetail{ColumnName: "c", ColumnType: "INT", IsNullable: boolPtr(true)},
+	}
+	pred := New().Predict(action, &meta.TableMeta{Engine: "InnoDB", RowCount: 2_000_000_000, DataLength: 400 * GB})
+	if pred.RiskScore.Score != 0 || pred.RiskLevel != meta.RiskLow {
+		t.Errorf("INSTANTはスコア0のLOWであること: got %d %s", pred.RiskScore.Score, pred.RiskLevel)
+	}
+}
+
+func TestRiskScoreThresholds(t *testing.T) {
+	// 閾値を変更するとリスクレベルが変わることを検証
+	force := meta.AlterAction{Type: meta.ActionForceRebuild}
+	p := New(WithRiskThresholds(RiskThresholds{Medium: 10, High: 30, Critical: 40}))
+	pred := p.Predict(force, nil)
+	if pred.RiskScore.Score != 45 {
+		t.Errorf("INPLACE + 再構築のスコアが45であること: got %d", pred.RiskScore.Score)
+	}
+	if pred.RiskLevel != meta.RiskCritical {
+		t.Errorf("閾値40以上はCRITICALであること: got %s", pred.RiskLevel)
+	}
+}
+
+func TestApplyFKPropagation(t *testing.T) {
+	// FK伝播先のテーブル数が加点されることを検証
+	force := meta.AlterAction{Type: meta.ActionForceRebuild}
+	preds := []Prediction{New().Predict(force, &meta.TableMeta{Engine: "InnoDB", RowCount: 1_000_000, DataLength: 5 * GB})}
+	before := preds[0].RiskScore.Score
+	ApplyFKPropagation(preds, 10)
+	if got := preds[0].RiskScore.Score - before; got != fkMaxPoints {
+		t.Errorf("FK伝播の加点は上限 %d であること: got %d", fkMaxPoints, got)
+	}
+	if preds[0].RiskLevel != meta.RiskCritical {
+		t.Errorf("加点後のリスクレベルが再判定されること: got %s", preds[0].RiskLevel)
+	}
+}
+
+func TestCombineRiskScore(t *testing.T) {
+	// INSTANT と INPLACE の組み合わせで再構築になる場合、文全体のスコアにテーブルサイズが反映されることを検証
+	p := New()
+	tm := &meta.TableMeta{Engine: "InnoDB", RowCount: 20, DataLength: 2 * KB}
+	preds := []Prediction{
+		p.Predict(meta.AlterAction{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "c", ColumnType: "INT", IsNullable: boolPtr(true)}}, tm),
+		p.Predict(meta.AlterAction{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "idx_c", IndexColumns: []string{"c"}}}, tm),
+	}
+	v := CombinePredictions(preds)
+	if !v.TableRebuild || v.RiskScore == nil {
+		t.Fatalf("文全体は再構築になりスコアを持つこと: got %+v", v)
+	}
+	if v.RiskScore.Score != 20 || v.RiskLevel != meta.RiskMedium {
+		t.Errorf("小さいテーブルの再構築はMEDIUMであること: got %d %s", v.RiskScore.Score, v.RiskLevel)
+	}
+}
+
+func TestParseRiskThresholds(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    RiskThresholds
+		wantErr bool
+	}{
+		{"20,45,70", RiskThresholds{Medium: 20, High: 45, Critical: 70}, false},
+		{" 10, 30 ,60", RiskThresholds{Medium: 10, High: 30, Critical: 60}, false},
+		{"20,45", RiskThresholds{}, true},
+		{"20,x,70", RiskThresholds{}, true},
+		{"45,20,70", RiskThresholds{}, true},
+		{"0,45,70", RiskThresholds{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRiskThresholds(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRiskThresholds(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRiskThresholds(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
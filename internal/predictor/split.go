@@ -0,0 +1,94 @@
+package predictor
+
+import (
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// SplitStatement は分割を推奨する ALTER TABLE 文の1つを表す。
+type SplitStatement struct {
+	SQL     string                 `json:"sql"`
+	Actions []meta.AlterActionType `json:"actions"`
+	Verdict StatementVerdict       `json:"verdict"`
+}
+
+// RecommendSplit は INSTANT で実行できるアクションが再構築を伴うアクションに
+// 巻き込まれないよう、ALTER文の分割案を返す。
+// INSTANT のアクションを先の文に出し、残りのアクションは1回の再構築にまとめる
+// （再構築なしの INPLACE も別の文にするとテーブルの走査が増えるため、再構築の文に含める）。
+// predictions は PredictAll の結果で、op.Actions と同じ順序であること。
+// 文全体が再構築を伴わない、INSTANT のアクションがないなど分割しても改善しない場合や、
+// アクションのSQLが得られない場合は nil を返す。
+func RecommendSplit(op meta.AlterOperation, predictions []Prediction) []SplitStatement {
+	if len(op.Actions) < 2 || len(op.Actions) != len(predictions) {
+		return nil
+	}
+	for _, a := range op.Actions {
+		if a.SQL == "" {
+			return nil
+		}
+	}
+
+	// 主キーの置き換えは同じ文でないと INPLACE にならないため、まとめて再構築グループに入れる
+	hasPKReplace := hasActionType(op.Actions, meta.ActionDropPrimaryKey) && hasActionType(op.Actions, meta.ActionAddPrimaryKey)
+
+	var instant, rebuild, renames []int
+	for i, p := range predictions {
+		switch {
+		case op.Actions[i].Type == meta.ActionRenameTable:
+			// テーブル名の変更は後続の文が古い名前を参照できるよう最後に行う
+			renames = append(renames, i)
+		case hasPKReplace && (p.ActionType == meta.ActionDropPrimaryKey || p.ActionType == meta.ActionAddPrimaryKey):
+			rebuild = append(rebuild, i)
+		case p.Algorithm == meta.AlgorithmInstant:
+			instant = append(instant, i)
+		default:
+			rebuild = append(rebuild, i)
+		}
+	}
+	// INSTANT のアクションを再構築から切り出せる場合だけ分割する
+	if len(instant) == 0 || len(rebuild) == 0 || !CombinePredictions(predictions).TableRebuild {
+		return nil
+	}
+	indexes := [][]int{instant, append(rebuild, renames...)}
+
+	table := op.TableRef
+	if table == "" {
+		table = quoteIdent(op.Table)
+		if op.Schema != "" {
+			table = quoteIdent(op.Schema) + "." + table
+		}
+	}
+
+	splits := make([]SplitStatement, 0, len(indexes))
+	for _, idx := range indexes {
+		clauses := make([]string, 0, len(idx))
+		actions := make([]meta.AlterActionType, 0, len(idx))
+		preds := make([]Prediction, 0, len(idx))
+		for _, i := range idx {
+			clauses = append(clauses, op.Actions[i].SQL)
+			actions = append(actions, op.Actions[i].Type)
+			preds = append(preds, predictions[i])
+		}
+		splits = append(splits, SplitStatement{
+			SQL:     "ALTER TABLE " + table + " " + strings.Join(clauses, ", ") + ";",
+			Actions: actions,
+			Verdict: CombinePredictions(preds),
+		})
+	}
+	return splits
+}
+
+func hasActionType(actions []meta.AlterAction, t meta.AlterActionType) bool {
+	for _, a := range actions {
+		if a.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
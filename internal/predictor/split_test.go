@@ -0,0 +1,110 @@
+package predictor
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func TestRecommendSplit(t *testing.T) {
+	tm := &meta.TableMeta{
+		Schema: "mydb",
+		Table:  "users",
+		Engine: "InnoDB",
+		Columns: []meta.ColumnMeta{
+			{Name: "id", DataType: "int", ColumnType: "int"},
+			{Name: "y", DataType: "int", ColumnType: "int", IsNullable: true},
+		},
+	}
+	addCol := meta.AlterAction{Type: meta.ActionAddColumn, SQL: "ADD COLUMN `x` INT",
+		Detail: meta.ActionDetail{ColumnName: "x", ColumnType: "INT", IsNullable: boolPtr(true)}}
+	addIndex := meta.AlterAction{Type: meta.ActionAddIndex, SQL: "ADD INDEX `idx_x`(`x`)",
+		Detail: meta.ActionDetail{IndexName: "idx_x", IndexColumns: []string{"x"}}}
+	addIndexY := meta.AlterAction{Type: meta.ActionAddIndex, SQL: "ADD INDEX `idx_y`(`y`)",
+		Detail: meta.ActionDetail{IndexName: "idx_y", IndexColumns: []string{"y"}}}
+	notNull := meta.AlterAction{Type: meta.ActionModifyColumn, SQL: "MODIFY COLUMN `y` INT NOT NULL",
+		Detail: meta.ActionDetail{ColumnName: "y", ColumnType: "INT", IsNullable: boolPtr(false)}}
+	modifyType := meta.AlterAction{Type: meta.ActionModifyColumn, SQL: "MODIFY COLUMN `y` BIGINT",
+		Detail: meta.ActionDetail{ColumnName: "y", ColumnType: "BIGINT", IsNullable: boolPtr(true)}}
+	forceRebuild := meta.AlterAction{Type: meta.ActionForceRebuild, SQL: "FORCE"}
+	renameTable := meta.AlterAction{Type: meta.ActionRenameTable, SQL: "RENAME AS `members`",
+		Detail: meta.ActionDetail{ColumnName: "members"}}
+	dropPK := meta.AlterAction{Type: meta.ActionDropPrimaryKey, SQL: "DROP PRIMARY KEY"}
+	addPK := meta.AlterAction{Type: meta.ActionAddPrimaryKey, SQL: "ADD PRIMARY KEY(`id`, `y`)",
+		Detail: meta.ActionDetail{IndexColumns: []string{"id", "y"}}}
+
+	tests := []struct {
+		name    string
+		actions []meta.AlterAction
+		wantSQL []string
+		wantAlg []meta.Algorithm
+	}{
+		{
+			"INSTANTと再構築に分割",
+			[]meta.AlterAction{modifyType, addIndex, addCol, forceRebuild},
+			[]string{
+				"ALTER TABLE `mydb`.`users` ADD COLUMN `x` INT;",
+				"ALTER TABLE `mydb`.`users` MODIFY COLUMN `y` BIGINT, ADD INDEX `idx_x`(`x`), FORCE;",
+			},
+			[]meta.Algorithm{meta.AlgorithmInstant, meta.AlgorithmCopy},
+		},
+		{
+			"再構築なしのINPLACEは再構築の文にまとめる",
+			[]meta.AlterAction{addIndexY, notNull, addCol},
+			[]string{
+				"ALTER TABLE `mydb`.`users` ADD COLUMN `x` INT;",
+				"ALTER TABLE `mydb`.`users` ADD INDEX `idx_y`(`y`), MODIFY COLUMN `y` INT NOT NULL;",
+			},
+			[]meta.Algorithm{meta.AlgorithmInstant, meta.AlgorithmInplace},
+		},
+		{
+			"テーブル名変更は最後の文に含める",
+			[]meta.AlterAction{renameTable, addCol, modifyType},
+			[]string{
+				"ALTER TABLE `mydb`.`users` ADD COLUMN `x` INT;",
+				"ALTER TABLE `mydb`.`users` MODIFY COLUMN `y` BIGINT, RENAME AS `members`;",
+			},
+			[]meta.Algorithm{meta.AlgorithmInstant, meta.AlgorithmCopy},
+		},
+		{
+			"主キーの置き換えは分割しない",
+			[]meta.AlterAction{addCol, dropPK, addPK},
+			[]string{
+				"ALTER TABLE `mydb`.`users` ADD COLUMN `x` INT;",
+				"ALTER TABLE `mydb`.`users` DROP PRIMARY KEY, ADD PRIMARY KEY(`id`, `y`);",
+			},
+			[]meta.Algorithm{meta.AlgorithmInstant, meta.AlgorithmInplace},
+		},
+		{"同じ種類のみは分割不要", []meta.AlterAction{modifyType, forceRebuild}, nil, nil},
+		{"INSTANTがなければ分割不要", []meta.AlterAction{addIndexY, modifyType}, nil, nil},
+		{"単一アクションは分割不要", []meta.AlterAction{addCol}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := meta.AlterOperation{Schema: "mydb", Table: "users", Actions: tt.actions}
+			splits := RecommendSplit(op, New().PredictAll(op, tm))
+			if len(splits) != len(tt.wantSQL) {
+				t.Fatalf("分割数が%dであること: got %+v", len(tt.wantSQL), splits)
+			}
+			for i, s := range splits {
+				if s.SQL != tt.wantSQL[i] {
+					t.Errorf("%d番目のSQLが%qであること: got %q", i+1, tt.wantSQL[i], s.SQL)
+				}
+				if s.Verdict.Algorithm != tt.wantAlg[i] {
+					t.Errorf("%d番目のアルゴリズムが%sであること: got %s", i+1, tt.wantAlg[i], s.Verdict.Algorithm)
+				}
+			}
+		})
+	}
+}
+
+func TestRecommendSplitWithoutSQL(t *testing.T) {
+	// アクションのSQLがない場合は分割案を作れない
+	op := meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{
+		{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "x", IsNullable: boolPtr(true)}},
+		{Type: meta.ActionForceRebuild},
+	}}
+	if splits := RecommendSplit(op, New().PredictAll(op, nil)); splits != nil {
+		t.Errorf("nilであること: got %+v", splits)
+	}
+}
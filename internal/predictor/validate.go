@@ -0,0 +1,407 @@
+package predictor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// FindingSourceValidation はテーブル定義に対する事前検証による検出事項の発生元。
+const FindingSourceValidation = "validation"
+
+// 事前検証で報告する検出事項のコード。
+const (
+	CodeUnknownColumn      = "UNKNOWN_COLUMN"
+	CodeUnknownIndex       = "UNKNOWN_INDEX"
+	CodeUnknownForeignKey  = "UNKNOWN_FOREIGN_KEY"
+	CodeDuplicateColumn    = "DUPLICATE_COLUMN"
+	CodeDuplicateIndex     = "DUPLICATE_INDEX"
+	CodeMultiplePrimaryKey = "MULTIPLE_PRIMARY_KEY"
+	CodeIndexNeededByFK    = "INDEX_NEEDED_BY_FOREIGN_KEY"
+	CodeAutoIncrementKey   = "AUTO_INCREMENT_WITHOUT_KEY"
+)
+
+// 事前検証で報告するMySQLのエラー番号。
+const (
+	// ER_BAD_FIELD_ERROR
+	errBadField = 1054
+	// ER_DUP_FIELDNAME
+	errDupFieldName = 1060
+	// ER_DUP_KEYNAME
+	errDupKeyName = 1061
+	// ER_MULTIPLE_PRI_KEY
+	errMultiplePriKey = 1068
+	// ER_KEY_COLUMN_DOES_NOT_EXITS
+	errKeyColumnDoesNotExist = 1072
+	// ER_WRONG_AUTO_KEY
+	errWrongAutoKey = 1075
+	// ER_CANT_DROP_FIELD_OR_KEY
+	errCantDropFieldOrKey = 1091
+	// ER_KEY_DOES_NOT_EXITS
+	errKeyDoesNotExist = 1176
+	// ER_DROP_INDEX_FK
+	errDropIndexFK = 1553
+)
+
+// primaryKeyName は PRIMARY KEY のインデックス名（小文字）。
+const primaryKeyName = "primary"
+
+// Validate はALTER文の各アクションをテーブル定義と照合し、MySQLが即座にエラーにする変更をERRORとして返す。
+// アクションは記述順に適用しながら検証する（同じ文で削除したカラムを追加し直す場合などを許可する）。
+// テーブル定義がない、またはカラム情報を含まない場合は検証しない。
+func Validate(op meta.AlterOperation, tm *meta.TableMeta) []meta.Finding {
+	if tm == nil || len(tm.Columns) == 0 {
+		return nil
+	}
+	v := newValidator(tm)
+	for _, a := range op.Actions {
+		v.check(a)
+	}
+	v.checkForeignKeyIndexes()
+	v.checkAutoIncrementKey()
+	return v.findings
+}
+
+// validator はアクションを適用した後のカラム・インデックス・外部キーの名前を追跡する。
+// 名前はMySQLと同様に大文字小文字を区別しないため小文字で保持する。
+type validator struct {
+	tm          *meta.TableMeta
+	columns     map[string]bool
+	indexes     map[string][]string
+	foreignKeys map[string]bool
+	// droppedIndexes は DROP INDEX / DROP PRIMARY KEY で削除したインデックス名（表示用の元の名前）
+	droppedIndexes []string
+	// emptiedIndexes は DROP COLUMN ですべてのカラムを失い、暗黙に削除されたインデックス名
+	emptiedIndexes map[string]bool
+	// expressionIndexes は関数インデックスの式を含むインデックス名（カラムがなくなっても残る）
+	expressionIndexes map[string]bool
+	// autoIncrement は元のテーブルの AUTO_INCREMENT カラム（削除・属性の除去で消える）
+	autoIncrement  string
+	primaryKeyDrop bool
+	findings       []meta.Finding
+}
+
+func newValidator(tm *meta.TableMeta) *validator {
+	v := &validator{
+		tm:                tm,
+		columns:           make(map[string]bool),
+		indexes:           make(map[string][]string),
+		foreignKeys:       make(map[string]bool),
+		emptiedIndexes:    make(map[string]bool),
+		expressionIndexes: make(map[string]bool),
+	}
+	for _, col := range tm.Columns {
+		v.columns[strings.ToLower(col.Name)] = true
+		if strings.Contains(strings.ToLower(col.Extra), "auto_increment") {
+			v.autoIncrement = strings.ToLower(col.Name)
+		}
+	}
+	for _, idx := range tm.Indexes {
+		name := strings.ToLower(idx.Name)
+		if idx.IsPrimary {
+			name = primaryKeyName
+		}
+		v.indexes[name] = lowerAll(idx.Columns)
+		if idx.HasExpression {
+			v.expressionIndexes[name] = true
+		}
+	}
+	for _, fk := range tm.ForeignKeys {
+		v.foreignKeys[strings.ToLower(fk.ConstraintName)] = true
+	}
+	return v
+}
+
+// HasRejection は検出事項に MySQL が文を拒否する ERROR（事前検証、ALGORITHM/LOCK 句の検証）が含まれるかを返す。
+func HasRejection(findings []meta.Finding) bool {
+	return slices.ContainsFunc(findings, func(f meta.Finding) bool {
+		return (f.Source == FindingSourceValidation || f.Source == FindingSourceClause) && f.Severity == meta.SeverityError
+	})
+}
+
+func (v *validator) add(code string, errCode int, format string, args ...any) {
+	v.report(meta.SeverityError, code, errCode, format, args...)
+}
+
+// warn はテーブル定義だけでは MySQL が拒否すると断定できない検出事項を WARNING として記録する。
+func (v *validator) warn(code string, errCode int, format string, args ...any) {
+	v.report(meta.SeverityWarning, code, errCode, format, args...)
+}
+
+func (v *validator) report(severity meta.Severity, code string, errCode int, format string, args ...any) {
+	v.findings = append(v.findings, meta.Finding{
+		Severity:  severity,
+		Code:      code,
+		ErrorCode: errCode,
+		Source:    FindingSourceValidation,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) check(a meta.AlterAction) {
+	d := a.Detail
+	switch a.Type {
+	case meta.ActionAddColumn:
+		v.checkPosition(d.Position)
+		if v.columns[strings.ToLower(d.ColumnName)] {
+			v.add(CodeDuplicateColumn, errDupFieldName, "Duplicate column name '%s'", d.ColumnName)
+			return
+		}
+		v.columns[strings.ToLower(d.ColumnName)] = true
+	case meta.ActionDropColumn:
+		name := strings.ToLower(d.ColumnName)
+		if !v.columns[name] {
+			v.add(CodeUnknownColumn, errCantDropFieldOrKey, "Can't DROP '%s'; check that column/key exists", d.ColumnName)
+			return
+		}
+		delete(v.columns, name)
+		if name == v.autoIncrement {
+			v.autoIncrement = ""
+		}
+		v.dropIndexColumn(name)
+	case meta.ActionModifyColumn, meta.ActionChangeColumn, meta.ActionRenameColumn:
+		v.checkColumnChange(a)
+	case meta.ActionSetDefault, meta.ActionDropDefault:
+		v.requireColumn(d.ColumnName)
+	case meta.ActionAddIndex, meta.ActionAddUniqueIndex, meta.ActionAddFulltextIndex, meta.ActionAddSpatialIndex:
+		v.checkKeyColumns(d.IndexColumns)
+		if d.IndexName == "" {
+			// 名前のないインデックスは重複しない名前が付けられる
+			return
+		}
+		name := strings.ToLower(d.IndexName)
+		if _, ok := v.indexes[name]; ok || name == primaryKeyName {
+			v.add(CodeDuplicateIndex, errDupKeyName, "Duplicate key name '%s'", d.IndexName)
+			return
+		}
+		v.indexes[name] = lowerAll(d.IndexColumns)
+	case meta.ActionAddPrimaryKey:
+		v.checkKeyColumns(d.IndexColumns)
+		if _, ok := v.indexes[primaryKeyName]; ok {
+			v.add(CodeMultiplePrimaryKey, errMultiplePriKey, "Multiple primary key defined")
+			return
+		}
+		v.indexes[primaryKeyName] = lowerAll(d.IndexColumns)
+	case meta.ActionDropIndex:
+		v.dropIndex(d.IndexName)
+	case meta.ActionDropPrimaryKey:
+		v.primaryKeyDrop = true
+		v.dropIndex("PRIMARY")
+	case meta.ActionRenameIndex:
+		oldName, newName := strings.ToLower(d.OldIndexName), strings.ToLower(d.IndexName)
+		cols, ok := v.indexes[oldName]
+		if !ok {
+			v.warn(CodeUnknownIndex, errKeyDoesNotExist, "Key '%s' doesn't exist in table '%s' (not in the table metadata)", d.OldIndexName, v.tm.Table)
+			return
+		}
+		if oldName == newName {
+			return
+		}
+		if _, dup := v.indexes[newName]; dup {
+			v.add(CodeDuplicateIndex, errDupKeyName, "Duplicate key name '%s'", d.IndexName)
+			return
+		}
+		delete(v.indexes, oldName)
+		v.indexes[newName] = cols
+		if v.expressionIndexes[oldName] {
+			delete(v.expressionIndexes, oldName)
+			v.expressionIndexes[newName] = true
+		}
+	case meta.ActionDropForeignKey:
+		name := strings.ToLower(d.ConstraintName)
+		if !v.foreignKeys[name] {
+			v.add(CodeUnknownForeignKey, errCantDropFieldOrKey, "Can't DROP '%s'; check that column/key exists", d.ConstraintName)
+			return
+		}
+		delete(v.foreignKeys, name)
+	}
+}
+
+// checkColumnChange は MODIFY / CHANGE / RENAME COLUMN の変更元カラムの存在と、変更後の名前の重複を検証する。
+func (v *validator) checkColumnChange(a meta.AlterAction) {
+	d := a.Detail
+	oldName := d.OldColumnName
+	if oldName == "" {
+		oldName = d.ColumnName
+	}
+	v.checkPosition(d.Position)
+	if !v.requireColumn(oldName) {
+		return
+	}
+	oldKey, newKey := strings.ToLower(oldName), strings.ToLower(d.ColumnName)
+	if a.Type != meta.ActionRenameColumn && oldKey == v.autoIncrement && !d.IsAutoIncrement {
+		v.autoIncrement = ""
+	}
+	if oldKey == newKey {
+		return
+	}
+	if v.columns[newKey] {
+		v.add(CodeDuplicateColumn, errDupFieldName, "Duplicate column name '%s'", d.ColumnName)
+		return
+	}
+	delete(v.columns, oldKey)
+	v.columns[newKey] = true
+	if oldKey == v.autoIncrement {
+		v.autoIncrement = newKey
+	}
+	for name, cols := range v.indexes {
+		v.indexes[name] = replaceName(cols, oldKey, newKey)
+	}
+}
+
+// requireColumn はカラムが存在しない場合にエラーを記録し、存在するかを返す。
+func (v *validator) requireColumn(name string) bool {
+	if v.columns[strings.ToLower(name)] {
+		return true
+	}
+	v.add(CodeUnknownColumn, errBadField, "Unknown column '%s' in '%s'", name, v.tm.Table)
+	return false
+}
+
+// checkPosition は "AFTER <col>" で参照するカラムの存在を検証する。
+func (v *validator) checkPosition(position string) {
+	fields := strings.Fields(position)
+	if len(fields) == 2 && strings.EqualFold(fields[0], "AFTER") {
+		v.requireColumn(strings.Trim(fields[1], "`"))
+	}
+}
+
+func (v *validator) checkKeyColumns(columns []string) {
+	for _, col := range columns {
+		if !v.columns[strings.ToLower(col)] {
+			v.add(CodeUnknownColumn, errKeyColumnDoesNotExist, "Key column '%s' doesn't exist in table", col)
+		}
+	}
+}
+
+// dropIndexColumn は削除したカラムを各インデックスから除き、カラムがなくなったインデックスを削除する。
+// 関数インデックスは式のキー部が残るため削除しない。
+func (v *validator) dropIndexColumn(column string) {
+	for name, cols := range v.indexes {
+		if !slices.Contains(cols, column) {
+			continue
+		}
+		cols = slices.DeleteFunc(slices.Clone(cols), func(c string) bool { return c == column })
+		if len(cols) > 0 || v.expressionIndexes[name] {
+			v.indexes[name] = cols
+			continue
+		}
+		delete(v.indexes, name)
+		v.emptiedIndexes[name] = true
+	}
+}
+
+func (v *validator) dropIndex(name string) {
+	key := strings.ToLower(name)
+	if v.emptiedIndexes[key] {
+		// 同じ文で削除したカラムだけを含むインデックスの DROP INDEX は MySQL も受け付ける
+		delete(v.emptiedIndexes, key)
+		return
+	}
+	if _, ok := v.indexes[key]; !ok {
+		// PRIMARY KEY 以外のインデックスはメタ情報に含まれない場合があるため WARNING にとどめる
+		if key == primaryKeyName {
+			v.add(CodeUnknownIndex, errCantDropFieldOrKey, "Can't DROP '%s'; check that column/key exists", name)
+		} else {
+			v.warn(CodeUnknownIndex, errCantDropFieldOrKey, "Can't DROP '%s'; check that column/key exists (not in the table metadata)", name)
+		}
+		return
+	}
+	delete(v.indexes, key)
+	v.droppedIndexes = append(v.droppedIndexes, name)
+}
+
+// checkForeignKeyIndexes は削除したインデックスが、残る外部キー（このテーブルの外部キーと
+// このテーブルを参照する外部キー）に必要な唯一のインデックスだった場合にエラーを記録する。
+// 同じ文で追加したインデックスが代わりになる場合は削除できる。
+func (v *validator) checkForeignKeyIndexes() {
+	if len(v.droppedIndexes) == 0 {
+		return
+	}
+	var needed [][]string
+	var constraints []string
+	for _, fk := range v.tm.ForeignKeys {
+		if v.foreignKeys[strings.ToLower(fk.ConstraintName)] {
+			needed = append(needed, lowerAll(fk.SourceColumns))
+			constraints = append(constraints, fk.ConstraintName)
+		}
+	}
+	for _, fk := range v.tm.ReferencedBy {
+		needed = append(needed, lowerAll(fk.ReferencedColumns))
+		constraints = append(constraints, fk.SourceTable+"."+fk.ConstraintName)
+	}
+
+	for i, cols := range needed {
+		if len(cols) == 0 || v.hasIndexPrefix(cols) {
+			continue
+		}
+		for _, dropped := range v.droppedIndexes {
+			if v.originalIndexCovers(dropped, cols) {
+				v.add(CodeIndexNeededByFK, errDropIndexFK, "Cannot drop index '%s': needed in a foreign key constraint %s", dropped, constraints[i])
+				break
+			}
+		}
+	}
+}
+
+// checkAutoIncrementKey は DROP PRIMARY KEY の後、AUTO_INCREMENT カラムを先頭に持つインデックスが残らない場合にエラーを記録する。
+func (v *validator) checkAutoIncrementKey() {
+	if !v.primaryKeyDrop || v.autoIncrement == "" || v.hasIndexPrefix([]string{v.autoIncrement}) {
+		return
+	}
+	v.add(CodeAutoIncrementKey, errWrongAutoKey,
+		"Incorrect table definition; there can be only one auto column and it must be defined as a key — AUTO_INCREMENT column '%s' has no index after DROP PRIMARY KEY", v.autoIncrement)
+}
+
+// hasIndexPrefix は cols を先頭カラムとして持つインデックスが残っているかを返す。
+func (v *validator) hasIndexPrefix(cols []string) bool {
+	for _, idxCols := range v.indexes {
+		if hasPrefix(idxCols, cols) {
+			return true
+		}
+	}
+	return false
+}
+
+// originalIndexCovers は削除前のインデックス name が cols を先頭カラムとして持っていたかを返す。
+func (v *validator) originalIndexCovers(name string, cols []string) bool {
+	for _, idx := range v.tm.Indexes {
+		if strings.EqualFold(idx.Name, name) || (idx.IsPrimary && strings.EqualFold(name, primaryKeyName)) {
+			return hasPrefix(lowerAll(idx.Columns), cols)
+		}
+	}
+	return false
+}
+
+func hasPrefix(columns, prefix []string) bool {
+	if len(columns) < len(prefix) {
+		return false
+	}
+	for i, c := range prefix {
+		if columns[i] != c {
+			return false
+		}
+	}
+	return true
+}
+
+func lowerAll(list []string) []string {
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[i] = strings.ToLower(s)
+	}
+	return out
+}
+
+func replaceName(list []string, oldName, newName string) []string {
+	out := make([]string, len(list))
+	for i, s := range list {
+		if s == oldName {
+			s = newName
+		}
+		out[i] = s
+	}
+	return out
+}
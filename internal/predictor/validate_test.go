@@ -0,0 +1,262 @@
+package predictor
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func ordersValidateMeta() *meta.TableMeta {
+	return &meta.TableMeta{
+		Schema: "mydb",
+		Table:  "orders",
+		Engine: "InnoDB",
+		Columns: []meta.ColumnMeta{
+			{Name: "id", ColumnType: "bigint", Extra: "auto_increment"},
+			{Name: "user_id", ColumnType: "bigint"},
+			{Name: "status", ColumnType: "varchar(20)"},
+		},
+		Indexes: []meta.IndexMeta{
+			{Name: "PRIMARY", Columns: []string{"id"}, IsUnique: true, IsPrimary: true},
+			{Name: "idx_user_id", Columns: []string{"user_id"}},
+			{Name: "idx_status", Columns: []string{"status"}},
+		},
+		ForeignKeys: []meta.ForeignKeyMeta{{
+			ConstraintName: "fk_orders_user", SourceTable: "orders", SourceColumns: []string{"user_id"},
+			ReferencedTable: "users", ReferencedColumns: []string{"id"},
+		}},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		actions     []meta.AlterAction
+		wantCode    string
+		wantErrCode int
+	}{
+		{"存在しないカラムの削除",
+			[]meta.AlterAction{{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "memo"}}},
+			CodeUnknownColumn, 1091},
+		{"存在しないカラムの変更",
+			[]meta.AlterAction{{Type: meta.ActionModifyColumn, Detail: meta.ActionDetail{ColumnName: "memo", ColumnType: "TEXT"}}},
+			CodeUnknownColumn, 1054},
+		{"AFTERで存在しないカラムを参照",
+			[]meta.AlterAction{{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "memo", ColumnType: "TEXT", Position: "AFTER note"}}},
+			CodeUnknownColumn, 1054},
+		{"存在しないカラムのインデックス",
+			[]meta.AlterAction{{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "idx_memo", IndexColumns: []string{"memo"}}}},
+			CodeUnknownColumn, 1072},
+		{"存在しない外部キーの削除",
+			[]meta.AlterAction{{Type: meta.ActionDropForeignKey, Detail: meta.ActionDetail{ConstraintName: "fk_missing"}}},
+			CodeUnknownForeignKey, 1091},
+		{"重複するカラム名",
+			[]meta.AlterAction{{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "Status", ColumnType: "INT"}}},
+			CodeDuplicateColumn, 1060},
+		{"既存のカラム名への名前変更",
+			[]meta.AlterAction{{Type: meta.ActionRenameColumn, Detail: meta.ActionDetail{OldColumnName: "status", ColumnName: "user_id"}}},
+			CodeDuplicateColumn, 1060},
+		{"重複するインデックス名",
+			[]meta.AlterAction{{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "IDX_STATUS", IndexColumns: []string{"status"}}}},
+			CodeDuplicateIndex, 1061},
+		{"既存のインデックス名への名前変更",
+			[]meta.AlterAction{{Type: meta.ActionRenameIndex, Detail: meta.ActionDetail{OldIndexName: "idx_status", IndexName: "idx_user_id"}}},
+			CodeDuplicateIndex, 1061},
+		{"存在しないPRIMARY KEYの削除", []meta.AlterAction{
+			{Type: meta.ActionModifyColumn, Detail: meta.ActionDetail{ColumnName: "id", ColumnType: "BIGINT"}},
+			{Type: meta.ActionDropPrimaryKey},
+			{Type: meta.ActionDropPrimaryKey},
+		}, CodeUnknownIndex, 1091},
+		{"PRIMARY KEYの重複",
+			[]meta.AlterAction{{Type: meta.ActionAddPrimaryKey, Detail: meta.ActionDetail{IndexColumns: []string{"user_id"}}}},
+			CodeMultiplePrimaryKey, 1068},
+		{"外部キーが使うインデックスの削除",
+			[]meta.AlterAction{{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx_user_id"}}},
+			CodeIndexNeededByFK, 1553},
+		{"AUTO_INCREMENTカラムのPRIMARY KEY削除",
+			[]meta.AlterAction{{Type: meta.ActionDropPrimaryKey}},
+			CodeAutoIncrementKey, 1075},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Validate(meta.AlterOperation{Table: "orders", Actions: tt.actions}, ordersValidateMeta())
+			if len(findings) != 1 {
+				t.Fatalf("検出件数が1であること: got %+v", findings)
+			}
+			f := findings[0]
+			if f.Code != tt.wantCode || f.ErrorCode != tt.wantErrCode {
+				t.Errorf("%s (MySQL error %d) であること: got %s (%d) %s", tt.wantCode, tt.wantErrCode, f.Code, f.ErrorCode, f.Message)
+			}
+			if f.Severity != meta.SeverityError || f.Source != FindingSourceValidation {
+				t.Errorf("ERROR / validation であること: got %s / %s", f.Severity, f.Source)
+			}
+		})
+	}
+}
+
+func TestValidateValidStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []meta.AlterAction
+	}{
+		{"カラムの削除と追加し直し", []meta.AlterAction{
+			{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}},
+			{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "status", ColumnType: "INT"}},
+		}},
+		{"名前のないインデックス", []meta.AlterAction{
+			{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexColumns: []string{"status"}}},
+		}},
+		{"外部キーと同時に削除", []meta.AlterAction{
+			{Type: meta.ActionDropForeignKey, Detail: meta.ActionDetail{ConstraintName: "fk_orders_user"}},
+			{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx_user_id"}},
+		}},
+		{"代わりのインデックスを追加", []meta.AlterAction{
+			{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx_user_id"}},
+			{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "idx_user_status", IndexColumns: []string{"user_id", "status"}}},
+		}},
+		{"PRIMARY KEYの付け替え", []meta.AlterAction{
+			{Type: meta.ActionDropPrimaryKey},
+			{Type: meta.ActionAddPrimaryKey, Detail: meta.ActionDetail{IndexColumns: []string{"id", "user_id"}}},
+		}},
+		{"AUTO_INCREMENTを外してPRIMARY KEY削除", []meta.AlterAction{
+			{Type: meta.ActionModifyColumn, Detail: meta.ActionDetail{ColumnName: "id", ColumnType: "BIGINT"}},
+			{Type: meta.ActionDropPrimaryKey},
+		}},
+		{"カラム削除で消えたインデックス名の再利用", []meta.AlterAction{
+			{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}},
+			{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "idx_status", IndexColumns: []string{"user_id"}}},
+		}},
+		{"カラムとそのインデックスの削除", []meta.AlterAction{
+			{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}},
+			{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx_status"}},
+		}},
+		{"カラム名の変更とインデックスの参照", []meta.AlterAction{
+			{Type: meta.ActionChangeColumn, Detail: meta.ActionDetail{OldColumnName: "status", ColumnName: "state", ColumnType: "VARCHAR(20)"}},
+			{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "idx_state_user", IndexColumns: []string{"state", "user_id"}}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if findings := Validate(meta.AlterOperation{Table: "orders", Actions: tt.actions}, ordersValidateMeta()); len(findings) != 0 {
+				t.Errorf("エラーにならないこと: got %+v", findings)
+			}
+		})
+	}
+}
+
+func TestValidateReferencedBy(t *testing.T) {
+	// 子テーブルの外部キーが参照するカラムのインデックスは親テーブルで削除できない
+	tm := ordersValidateMeta()
+	tm.ReferencedBy = []meta.ForeignKeyMeta{{
+		ConstraintName: "fk_items_order", SourceTable: "order_items", SourceColumns: []string{"order_id"},
+		ReferencedTable: "orders", ReferencedColumns: []string{"id"},
+	}}
+	tm.Columns[0].Extra = ""
+	findings := Validate(meta.AlterOperation{Table: "orders", Actions: []meta.AlterAction{{Type: meta.ActionDropPrimaryKey}}}, tm)
+	if len(findings) != 1 || findings[0].ErrorCode != 1553 {
+		t.Errorf("参照されるPRIMARY KEYの削除がエラーになること: got %+v", findings)
+	}
+}
+
+func TestValidateWithoutMeta(t *testing.T) {
+	op := meta.AlterOperation{Table: "orders", Actions: []meta.AlterAction{{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "memo"}}}}
+	if findings := Validate(op, nil); findings != nil {
+		t.Errorf("テーブル定義がない場合は検証しないこと: got %+v", findings)
+	}
+	if findings := Validate(op, &meta.TableMeta{Table: "orders"}); findings != nil {
+		t.Errorf("カラム情報がない場合は検証しないこと: got %+v", findings)
+	}
+}
+
+func TestHasRejection(t *testing.T) {
+	findings := []meta.Finding{
+		{Severity: meta.SeverityError, Source: "policy", RuleID: "a"},
+		{Severity: meta.SeverityWarning, Source: FindingSourceValidation},
+	}
+	if HasRejection(findings) {
+		t.Error("ポリシー違反や WARNING は拒否としないこと")
+	}
+	clause := []meta.Finding{{Severity: meta.SeverityError, Source: FindingSourceClause, ErrorCode: errAlterOperationNotSupported}}
+	if !HasRejection(clause) {
+		t.Error("ALGORITHM/LOCK 句の ERROR は拒否とすること")
+	}
+	findings = Validate(meta.AlterOperation{Schema: "mydb", Table: "orders", Actions: []meta.AlterAction{
+		{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "missing"}},
+	}}, ordersValidateMeta())
+	if !HasRejection(findings) {
+		t.Errorf("事前検証の ERROR は拒否とすること: got %+v", findings)
+	}
+}
+
+func TestValidateDropColumnShrinksIndexes(t *testing.T) {
+	// 複合インデックスからカラムを削除すると残りのカラムのインデックスになる
+	tm := ordersValidateMeta()
+	tm.Indexes = append(tm.Indexes, meta.IndexMeta{Name: "idx_status_user", Columns: []string{"status", "user_id"}})
+	findings := Validate(meta.AlterOperation{Table: "orders", Actions: []meta.AlterAction{
+		{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}},
+		{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx_user_id"}},
+	}}, tm)
+	if len(findings) != 0 {
+		t.Errorf("縮小したインデックスが外部キーのインデックスになること: got %+v", findings)
+	}
+
+	findings = Validate(meta.AlterOperation{Table: "orders", Actions: []meta.AlterAction{
+		{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "user_id"}},
+		{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "idx_status_user", IndexColumns: []string{"status"}}},
+	}}, tm)
+	if len(findings) != 1 || findings[0].ErrorCode != 1061 {
+		t.Errorf("カラムが残るインデックスの名前は重複とすること: got %+v", findings)
+	}
+}
+
+func TestValidateUnknownIndexWarning(t *testing.T) {
+	// メタ情報にないインデックスは MySQL が拒否すると断定できないため WARNING にとどめる
+	tests := []struct {
+		name        string
+		actions     []meta.AlterAction
+		wantErrCode int
+	}{
+		{"存在しないインデックスの削除",
+			[]meta.AlterAction{{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx_memo"}}},
+			1091},
+		{"存在しないインデックスの名前変更",
+			[]meta.AlterAction{{Type: meta.ActionRenameIndex, Detail: meta.ActionDetail{OldIndexName: "idx_memo", IndexName: "idx_note"}}},
+			1176},
+		{"カラム削除で消えたインデックスの名前変更", []meta.AlterAction{
+			{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}},
+			{Type: meta.ActionRenameIndex, Detail: meta.ActionDetail{OldIndexName: "idx_status", IndexName: "idx_state"}},
+		}, 1176},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Validate(meta.AlterOperation{Table: "orders", Actions: tt.actions}, ordersValidateMeta())
+			if len(findings) != 1 || findings[0].Code != CodeUnknownIndex || findings[0].ErrorCode != tt.wantErrCode {
+				t.Fatalf("UNKNOWN_INDEX (MySQL error %d) であること: got %+v", tt.wantErrCode, findings)
+			}
+			if findings[0].Severity != meta.SeverityWarning || HasRejection(findings) {
+				t.Errorf("WARNING であり拒否としないこと: got %s", findings[0].Severity)
+			}
+		})
+	}
+}
+
+func TestValidateFunctionalIndex(t *testing.T) {
+	// 式だけの関数インデックスはカラムの削除で消えず、削除できる
+	tm := ordersValidateMeta()
+	tm.Indexes = append(tm.Indexes, meta.IndexMeta{Name: "idx_lower", HasExpression: true})
+	findings := Validate(meta.AlterOperation{Table: "orders", Actions: []meta.AlterAction{
+		{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}},
+		{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx_lower"}},
+		{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "idx_status", IndexColumns: []string{"user_id"}}},
+	}}, tm)
+	if len(findings) != 0 {
+		t.Errorf("エラーにならないこと: got %+v", findings)
+	}
+	findings = Validate(meta.AlterOperation{Table: "orders", Actions: []meta.AlterAction{
+		{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}},
+		{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "idx_lower", IndexColumns: []string{"user_id"}}},
+	}}, tm)
+	if len(findings) != 1 || findings[0].ErrorCode != 1061 {
+		t.Errorf("カラムの削除後も関数インデックスの名前は重複とすること: got %+v", findings)
+	}
+}
@@ -0,0 +1,84 @@
+package predictor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Version はMySQLサーバーのバージョンを表す。
+// パッチ番号が省略された場合（例: "8.0"）はそのシリーズの最新版として扱う。
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	// partial はパッチ番号が省略されていることを示す
+	partial bool
+}
+
+// versionRegex は "8.0.35-log" や "8.0.36-28"（Percona）のような接尾辞付きの文字列にも一致する。
+var versionRegex = regexp.MustCompile(`^\s*[vV]?(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion は SELECT @@version の結果や --mysql-version の値を解析する。
+// 数字以降の接尾辞（-log, -28, -MariaDB など）は無視する。
+func ParseVersion(s string) (Version, error) {
+	m := versionRegex.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid MySQL version %q", s)
+	}
+	var v Version
+	var err error
+	if v.Major, err = strconv.Atoi(m[1]); err != nil {
+		return Version{}, fmt.Errorf("invalid MySQL version %q: %w", s, err)
+	}
+	if v.Minor, err = strconv.Atoi(m[2]); err != nil {
+		return Version{}, fmt.Errorf("invalid MySQL version %q: %w", s, err)
+	}
+	if m[3] == "" {
+		v.partial = true
+		return v, nil
+	}
+	if v.Patch, err = strconv.Atoi(m[3]); err != nil {
+		return Version{}, fmt.Errorf("invalid MySQL version %q: %w", s, err)
+	}
+	return v, nil
+}
+
+// IsZero はバージョンが未設定かどうかを返す。
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// String はバージョンを "8.0.35" 形式で返す。
+func (v Version) String() string {
+	if v.partial {
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare は v が o より古ければ負、新しければ正、同じなら0を返す。
+// どちらかのパッチ番号が省略されている場合、同じシリーズ同士は同じとみなす。
+func (v Version) Compare(o Version) int {
+	if c := compareInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if v.partial || o.partial {
+		return 0
+	}
+	return compareInt(v.Patch, o.Patch)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
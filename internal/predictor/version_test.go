@@ -0,0 +1,123 @@
+package predictor
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"8.0.32", "8.0.32", false},
+		{"8.0.35-log", "8.0.35", false},
+		{"8.0.36-28", "8.0.36", false},
+		{"5.7.44-log", "5.7.44", false},
+		{"8.0", "8.0", false},
+		{"  8.4.0 ", "8.4.0", false},
+		{"", "", true},
+		{"latest", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := ParseVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("エラー有無が%vであること: got %v", tt.wantErr, err)
+			}
+			if err == nil && v.String() != tt.want {
+				t.Errorf("バージョンが%sであること: got %s", tt.want, v.String())
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"8.0.25", "8.0.29", -1},
+		{"8.0.29", "8.0.29", 0},
+		{"8.4.0", "8.0.29", 1},
+		{"5.7.44", "8.0.12", -1},
+		// パッチ番号省略時は同じシリーズの任意の版と等しい
+		{"8.0", "8.0.29", 0},
+	}
+	for _, tt := range tests {
+		a, _ := ParseVersion(tt.a)
+		b, _ := ParseVersion(tt.b)
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("%s と %s の比較結果が%dであること: got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
+
+func TestDefaultRuleVersionsAreValid(t *testing.T) {
+	for _, r := range defaultRules() {
+		for _, v := range []string{r.MinVersion, r.MaxVersion} {
+			if v == "" {
+				continue
+			}
+			if _, err := ParseVersion(v); err != nil {
+				t.Errorf("%s のバージョン指定が解析できること: %v", r.Description, err)
+			}
+		}
+	}
+}
+
+// TestPredictVersionGating — サーバーバージョンによりINSTANTの可否が変わる操作を検証
+// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html#online-ddl-column-operations
+func TestPredictVersionGating(t *testing.T) {
+	tm := &meta.TableMeta{
+		Schema: "mydb",
+		Table:  "users",
+		Engine: "InnoDB",
+		Columns: []meta.ColumnMeta{
+			{Name: "id", ColumnType: "bigint"},
+			{Name: "name", ColumnType: "varchar(100)"},
+		},
+	}
+	addAfter := meta.AlterAction{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "nick", ColumnType: "VARCHAR(20)", IsNullable: boolPtr(true), Position: "AFTER id"}}
+	addTail := meta.AlterAction{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "nick", ColumnType: "VARCHAR(20)", IsNullable: boolPtr(true)}}
+	dropCol := meta.AlterAction{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "name"}}
+	renameCol := meta.AlterAction{Type: meta.ActionRenameColumn, Detail: meta.ActionDetail{OldColumnName: "name", ColumnName: "full_name"}}
+	changeRename := meta.AlterAction{Type: meta.ActionChangeColumn, Detail: meta.ActionDetail{OldColumnName: "name", ColumnName: "full_name", ColumnType: "VARCHAR(100)"}}
+
+	tests := []struct {
+		name        string
+		version     string
+		action      meta.AlterAction
+		wantAlg     meta.Algorithm
+		wantRebuild bool
+	}{
+		{"非末尾ADD COLUMN 8.0.25", "8.0.25", addAfter, meta.AlgorithmInplace, true},
+		{"非末尾ADD COLUMN 8.0.29", "8.0.29", addAfter, meta.AlgorithmInstant, false},
+		{"非末尾ADD COLUMN バージョン不明", "", addAfter, meta.AlgorithmInstant, false},
+		{"非末尾ADD COLUMN 8.0（パッチ省略）", "8.0", addAfter, meta.AlgorithmInstant, false},
+		{"末尾ADD COLUMN 8.0.25", "8.0.25", addTail, meta.AlgorithmInstant, false},
+		{"末尾ADD COLUMN 8.0.11", "8.0.11", addTail, meta.AlgorithmInplace, true},
+		{"末尾ADD COLUMN 5.7", "5.7.44-log", addTail, meta.AlgorithmInplace, true},
+		{"DROP COLUMN 8.0.28", "8.0.28", dropCol, meta.AlgorithmInplace, true},
+		{"DROP COLUMN Percona 8.0.36", "8.0.36-28", dropCol, meta.AlgorithmInstant, true},
+		{"RENAME COLUMN 8.0.27", "8.0.27", renameCol, meta.AlgorithmInplace, false},
+		{"RENAME COLUMN 8.0.28", "8.0.28", renameCol, meta.AlgorithmInstant, false},
+		{"CHANGE COLUMN 名前のみ 8.0.27", "8.0.27", changeRename, meta.AlgorithmInplace, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pred := New(WithMySQLVersion(tt.version)).Predict(tt.action, tm)
+			if pred.Algorithm != tt.wantAlg {
+				t.Errorf("アルゴリズムが%sであること: got %s (%s)", tt.wantAlg, pred.Algorithm, pred.Description)
+			}
+			if pred.TableRebuild != tt.wantRebuild {
+				t.Errorf("テーブル再構築が%vであること: got %v", tt.wantRebuild, pred.TableRebuild)
+			}
+			if pred.Lock != meta.LockNone {
+				t.Errorf("ロックがNONEであること: got %s", pred.Lock)
+			}
+		})
+	}
+}
@@ -2,9 +2,13 @@ package reporter
 
 import (
 	"encoding/json"
+	"math"
 
 	"github.com/Glider2355/ddl-lock-analyzer/internal/fkresolver"
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/osc"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/rollback"
 )
 
 // JSONReporter はJSON形式で結果を出力する。
@@ -16,21 +20,54 @@ func NewJSONReporter() *JSONReporter {
 }
 
 type jsonOutput struct {
-	Analyses []jsonAnalysis `json:"analyses"`
+	Analyses   []jsonAnalysis  `json:"analyses"`
+	Statements []jsonStatement `json:"statements"`
+	Summary    jsonSummary     `json:"summary"`
+}
+
+type jsonSummary struct {
+	Statements     int            `json:"statements"`
+	WorstRiskLevel meta.RiskLevel `json:"worst_risk_level"`
+}
+
+// jsonStatement はALTER文単位の結果（analyses はアクション単位）。
+type jsonStatement struct {
+	File               string                      `json:"file,omitempty"`
+	Table              string                      `json:"table"`
+	SQL                string                      `json:"sql"`
+	Verdict            *predictor.StatementVerdict `json:"verdict,omitempty"`
+	Split              []predictor.SplitStatement  `json:"split,omitempty"`
+	Findings           []meta.Finding              `json:"findings"`
+	OnlineSchemaChange []osc.Plan                  `json:"online_schema_change,omitempty"`
+	Comparison         *osc.Comparison             `json:"comparison,omitempty"`
+	Rollback           *rollback.Plan              `json:"rollback,omitempty"`
 }
 
 type jsonAnalysis struct {
-	Table         string             `json:"table"`
-	SQL           string             `json:"sql"`
-	Operation     string             `json:"operation"`
-	Algorithm     meta.Algorithm     `json:"algorithm"`
-	LockLevel     meta.LockLevel     `json:"lock_level"`
-	TableRebuild  bool               `json:"table_rebuild"`
-	TableInfo     *jsonTableInfo     `json:"table_info,omitempty"`
-	RiskLevel     meta.RiskLevel     `json:"risk_level"`
-	FKPropagation *jsonFKPropagation `json:"fk_propagation,omitempty"`
-	Notes         []string           `json:"notes,omitempty"`
-	Warnings      []string           `json:"warnings,omitempty"`
+	File              string                      `json:"file,omitempty"`
+	Table             string                      `json:"table"`
+	SQL               string                      `json:"sql"`
+	Operation         string                      `json:"operation"`
+	RuleID            string                      `json:"rule_id"`
+	RuleSource        string                      `json:"rule_source,omitempty"`
+	DocURL            string                      `json:"doc_url,omitempty"`
+	Algorithm         meta.Algorithm              `json:"algorithm"`
+	LockLevel         meta.LockLevel              `json:"lock_level"`
+	TableRebuild      bool                        `json:"table_rebuild"`
+	Permitted         []predictor.AlgorithmOption `json:"permitted,omitempty"`
+	TableInfo         *jsonTableInfo              `json:"table_info,omitempty"`
+	EstimatedDuration *jsonDuration               `json:"estimated_duration_sec,omitempty"`
+	RiskLevel         meta.RiskLevel              `json:"risk_level"`
+	RiskScore         *predictor.RiskScore        `json:"risk_score,omitempty"`
+	FKPropagation     *jsonFKPropagation          `json:"fk_propagation,omitempty"`
+	Notes             []string                    `json:"notes,omitempty"`
+	Warnings          []string                    `json:"warnings,omitempty"`
+	Explain           *predictor.Explanation      `json:"explain,omitempty"`
+}
+
+type jsonDuration struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
 }
 
 type jsonTableInfo struct {
@@ -57,20 +94,55 @@ type jsonFKRelation struct {
 
 // Render はレポートをJSONとしてレンダリングする。
 func (r *JSONReporter) Render(report *Report) (string, error) {
-	output := jsonOutput{}
+	output := jsonOutput{
+		Summary: jsonSummary{
+			Statements:     len(report.Analyses),
+			WorstRiskLevel: report.WorstRiskLevel(),
+		},
+	}
 
 	for _, analysis := range report.Analyses {
+		findings := analysis.Findings
+		if findings == nil {
+			findings = []meta.Finding{}
+		}
+		output.Statements = append(output.Statements, jsonStatement{
+			File:               analysis.File,
+			Table:              analysis.Table,
+			SQL:                analysis.SQL,
+			Verdict:            analysis.Verdict,
+			Split:              analysis.Split,
+			Findings:           findings,
+			OnlineSchemaChange: analysis.OnlineSchemaChange,
+			Comparison:         analysis.Comparison,
+			Rollback:           analysis.Rollback,
+		})
+
 		for _, pred := range analysis.Predictions {
 			ja := jsonAnalysis{
+				File:         analysis.File,
 				Table:        analysis.Table,
 				SQL:          analysis.SQL,
 				Operation:    string(pred.ActionType),
+				RuleID:       pred.RuleID,
+				RuleSource:   pred.RuleSource,
+				DocURL:       pred.DocURL,
 				Algorithm:    pred.Algorithm,
 				LockLevel:    pred.Lock,
 				TableRebuild: pred.TableRebuild,
+				Permitted:    pred.Permitted,
 				RiskLevel:    pred.RiskLevel,
+				RiskScore:    pred.RiskScore,
 				Notes:        pred.Notes,
 				Warnings:     pred.Warnings,
+				Explain:      pred.Explain,
+			}
+
+			if est := pred.EstimatedDuration; est != nil {
+				ja.EstimatedDuration = &jsonDuration{
+					Min: int64(math.Round(est.MinSec)),
+					Max: int64(math.Round(est.MaxSec)),
+				}
 			}
 
 			if pred.TableInfo.Label != "" && pred.TableInfo.Label != "N/A (no table metadata)" {
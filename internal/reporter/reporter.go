@@ -3,16 +3,28 @@ package reporter
 import (
 	"github.com/Glider2355/ddl-lock-analyzer/internal/fkresolver"
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/osc"
 	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/rollback"
 )
 
 // AnalysisResult は1つのALTER文に対する完全な分析結果を保持する。
 type AnalysisResult struct {
-	Table       string                 `json:"table"`
-	SQL         string                 `json:"sql"`
-	Predictions []predictor.Prediction `json:"predictions"`
-	FKGraph     *fkresolver.FKGraph    `json:"fk_propagation,omitempty"`
-	TableMeta   *meta.TableMeta        `json:"-"`
+	File        string                      `json:"file,omitempty"`
+	Table       string                      `json:"table"`
+	SQL         string                      `json:"sql"`
+	Predictions []predictor.Prediction      `json:"predictions"`
+	Verdict     *predictor.StatementVerdict `json:"verdict,omitempty"`
+	Split       []predictor.SplitStatement  `json:"split,omitempty"`
+	FKGraph     *fkresolver.FKGraph         `json:"fk_propagation,omitempty"`
+	Findings    []meta.Finding              `json:"findings,omitempty"`
+	// OnlineSchemaChange は COPY またはブロッキングと予測された文のオンラインスキーマ変更ツールの実行計画
+	OnlineSchemaChange []osc.Plan `json:"online_schema_change,omitempty"`
+	// Comparison はネイティブのオンラインDDLとオンラインスキーマ変更ツールの推定コストの比較
+	Comparison *osc.Comparison `json:"comparison,omitempty"`
+	// Rollback は文を元に戻す DDL とそのロック動作の予測（--rollback 指定時のみ）
+	Rollback  *rollback.Plan  `json:"rollback,omitempty"`
+	TableMeta *meta.TableMeta `json:"-"`
 }
 
 // Report は全分析結果を保持する。
@@ -36,6 +48,27 @@ func WorstRiskLevel(predictions []predictor.Prediction) meta.RiskLevel {
 	return worst
 }
 
+// WorstRiskLevel はレポート内の全ALTER文から最も高いリスクレベルを返す。
+// 文全体の判定がある場合はそれを、ない場合はアクション単位の予測を使う。
+func (r *Report) WorstRiskLevel() meta.RiskLevel {
+	worst := meta.RiskLow
+	for _, a := range r.Analyses {
+		level := WorstRiskLevel(a.Predictions)
+		if a.Verdict != nil && riskOrd(a.Verdict.RiskLevel) > riskOrd(level) {
+			level = a.Verdict.RiskLevel
+		}
+		if riskOrd(level) > riskOrd(worst) {
+			worst = level
+		}
+	}
+	return worst
+}
+
+// RiskAtLeast は level が threshold 以上のリスクかどうかを返す。
+func RiskAtLeast(level, threshold meta.RiskLevel) bool {
+	return riskOrd(level) >= riskOrd(threshold)
+}
+
 func riskOrd(r meta.RiskLevel) int {
 	switch r {
 	case meta.RiskLow:
@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/osc"
 	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/rollback"
 )
 
 func TestTextReporterBasic(t *testing.T) {
@@ -194,3 +196,496 @@ func TestMultipleAnalyses(t *testing.T) {
 		t.Error("分析結果間にセパレータが含まれること")
 	}
 }
+
+func TestReporterFile(t *testing.T) {
+	// 読み込み元ファイルがテキスト・JSON両方に出力されることを検証
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{File: "migrations/001_add_nickname.sql", Table: "mydb.users", SQL: "ALTER TABLE users ADD COLUMN a INT",
+				Predictions: []predictor.Prediction{{Description: "ADD COLUMN", Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone, RiskLevel: meta.RiskLow, TableInfo: predictor.TableInfo{Label: "N/A (no table metadata)"}}}},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "File:  migrations/001_add_nickname.sql") {
+		t.Error("テキスト出力にファイルパスが含まれること")
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if len(result.Analyses) != 1 || result.Analyses[0].File != "migrations/001_add_nickname.sql" {
+		t.Errorf("JSON出力にファイルパスが含まれること: got %+v", result.Analyses)
+	}
+}
+
+func TestReporterFindings(t *testing.T) {
+	// 検出事項がテキスト出力とJSONのstatementsに含まれることを検証
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "mydb.users", SQL: "ALTER TABLE users MODIFY COLUMN a BIGINT, ALGORITHM=INSTANT",
+				Predictions: []predictor.Prediction{{Description: "MODIFY COLUMN (type change)", Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared, RiskLevel: meta.RiskCritical, TableInfo: predictor.TableInfo{Label: "N/A (no table metadata)"}}},
+				Findings: []meta.Finding{{Severity: meta.SeverityError, Code: "ALGORITHM_NOT_SUPPORTED", ErrorCode: 1845, Source: "clause",
+					Message: "ALGORITHM=INSTANT is not supported for this operation"}}},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "[ERROR] ALGORITHM=INSTANT is not supported for this operation (MySQL error 1845)") {
+		t.Errorf("テキスト出力に検出事項が含まれること: got\n%s", text)
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if len(result.Statements) != 1 || len(result.Statements[0].Findings) != 1 {
+		t.Fatalf("statementsに検出事項が1件含まれること: got %+v", result.Statements)
+	}
+	if f := result.Statements[0].Findings[0]; f.ErrorCode != 1845 || f.Severity != meta.SeverityError {
+		t.Errorf("エラー番号と重要度が出力されること: got %+v", f)
+	}
+}
+
+func TestReporterVerdict(t *testing.T) {
+	// 複数アクションの文では文全体の判定が出力されることを検証
+	preds := []predictor.Prediction{
+		{ActionType: meta.ActionAddColumn, Description: "ADD COLUMN (trailing, NULLABLE)", Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone, RiskLevel: meta.RiskLow},
+		{ActionType: meta.ActionModifyColumn, Description: "MODIFY COLUMN (type change)", Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared, TableRebuild: true, RiskLevel: meta.RiskCritical},
+	}
+	verdict := predictor.CombinePredictions(preds)
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "mydb.users", SQL: "ALTER TABLE users ADD COLUMN x INT, MODIFY COLUMN y BIGINT", Predictions: preds, Verdict: &verdict},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "Statement          : 2 actions executed as a single ALTER") {
+		t.Errorf("テキスト出力に文全体の判定が含まれること: got\n%s", text)
+	}
+	if !strings.Contains(text, "Algorithm          : COPY (required by MODIFY COLUMN (type change))") {
+		t.Errorf("文全体のアルゴリズムと決定要因が出力されること: got\n%s", text)
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	v := result.Statements[0].Verdict
+	if v == nil || v.Algorithm != meta.AlgorithmCopy || v.Lock != meta.LockShared || !v.TableRebuild {
+		t.Errorf("statementsに文全体の判定が含まれること: got %+v", v)
+	}
+}
+
+func TestReporterSplit(t *testing.T) {
+	// 分割案がテキスト出力とJSONのstatementsに含まれることを検証
+	split := []predictor.SplitStatement{
+		{SQL: "ALTER TABLE `users` ADD COLUMN `x` INT;", Actions: []meta.AlterActionType{meta.ActionAddColumn},
+			Verdict: predictor.StatementVerdict{Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone, RiskLevel: meta.RiskLow}},
+		{SQL: "ALTER TABLE `users` MODIFY COLUMN `y` BIGINT;", Actions: []meta.AlterActionType{meta.ActionModifyColumn},
+			Verdict: predictor.StatementVerdict{Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared, TableRebuild: true, RiskLevel: meta.RiskCritical}},
+	}
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "users", SQL: "ALTER TABLE `users` ADD COLUMN `x` INT, MODIFY COLUMN `y` BIGINT", Split: split},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "1. [LOW] ALGORITHM=INSTANT, LOCK=NONE, Rebuild=No\n       ALTER TABLE `users` ADD COLUMN `x` INT;") {
+		t.Errorf("テキスト出力に分割案が含まれること: got\n%s", text)
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if got := result.Statements[0].Split; len(got) != 2 || got[1].Verdict.RiskLevel != meta.RiskCritical {
+		t.Errorf("statementsに分割案が含まれること: got %+v", got)
+	}
+}
+
+func TestReportWorstRiskLevel(t *testing.T) {
+	// 文全体の判定を含めて最も高いリスクレベルが集計されることを検証
+	verdict := predictor.StatementVerdict{Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone, TableRebuild: true, RiskLevel: meta.RiskHigh}
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "a", Predictions: []predictor.Prediction{{RiskLevel: meta.RiskLow}}},
+			{Table: "b", Predictions: []predictor.Prediction{{RiskLevel: meta.RiskLow}, {RiskLevel: meta.RiskMedium}}, Verdict: &verdict},
+		},
+	}
+	if got := report.WorstRiskLevel(); got != meta.RiskHigh {
+		t.Errorf("最悪リスクがHIGHであること: got %s", got)
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "Summary: 2 statement(s) analyzed, worst risk level: HIGH") {
+		t.Errorf("テキスト出力にサマリーが含まれること: got\n%s", text)
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if result.Summary.Statements != 2 || result.Summary.WorstRiskLevel != meta.RiskHigh {
+		t.Errorf("JSONにサマリーが含まれること: got %+v", result.Summary)
+	}
+}
+
+func TestRiskAtLeast(t *testing.T) {
+	tests := []struct {
+		level, threshold meta.RiskLevel
+		want             bool
+	}{
+		{meta.RiskCritical, meta.RiskHigh, true},
+		{meta.RiskHigh, meta.RiskHigh, true},
+		{meta.RiskMedium, meta.RiskHigh, false},
+		{meta.RiskLow, meta.RiskLow, true},
+	}
+	for _, tt := range tests {
+		if got := RiskAtLeast(tt.level, tt.threshold); got != tt.want {
+			t.Errorf("RiskAtLeast(%s, %s) が%vであること: got %v", tt.level, tt.threshold, tt.want, got)
+		}
+	}
+}
+
+func TestReporterEstimatedDuration(t *testing.T) {
+	// 推定実行時間がテキストとJSONに出力されることを検証
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "mydb.users", SQL: "ALTER TABLE users FORCE",
+				Predictions: []predictor.Prediction{{Description: "FORCE", Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone, TableRebuild: true, RiskLevel: meta.RiskHigh,
+					EstimatedDuration: &predictor.DurationEstimate{MinSec: 44.6, MaxSec: 180.2, Label: "~45s - ~180s (rows: ~1,200,000, size: ~480MB)"}}}},
+			{Table: "mydb.orders", SQL: "ALTER TABLE orders ADD COLUMN a INT",
+				Predictions: []predictor.Prediction{{Description: "ADD COLUMN", Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone, RiskLevel: meta.RiskLow}}},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "Estimated Duration : ~45s - ~180s (rows: ~1,200,000, size: ~480MB)") {
+		t.Errorf("テキスト出力に推定時間が含まれること: got\n%s", text)
+	}
+	if !strings.Contains(text, "Estimated Duration : N/A (no table statistics)") {
+		t.Errorf("推定できない場合はN/Aと表示されること: got\n%s", text)
+	}
+	if !strings.Contains(text, "Note: estimated durations are rough heuristics") {
+		t.Errorf("推定時間が目安である旨が表示されること: got\n%s", text)
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if d := result.Analyses[0].EstimatedDuration; d == nil || d.Min != 45 || d.Max != 180 {
+		t.Errorf("estimated_duration_secが秒単位で出力されること: got %+v", d)
+	}
+	if result.Analyses[1].EstimatedDuration != nil {
+		t.Errorf("推定できない場合は省略されること: got %+v", result.Analyses[1].EstimatedDuration)
+	}
+}
+
+func TestReporterRiskScore(t *testing.T) {
+	// リスクスコアと要因がテキストとJSONに出力されることを検証
+	score := &predictor.RiskScore{Score: 20, Factors: []predictor.RiskFactor{
+		{Name: predictor.FactorAlgorithm, Points: 25, Detail: "ALGORITHM=INPLACE"},
+		{Name: predictor.FactorTableRebuild, Points: 20, Detail: "Table rebuild"},
+		{Name: predictor.FactorLock, Points: 0, Detail: "LOCK=NONE"},
+		{Name: predictor.FactorTableSize, Points: -25, Detail: "data+index 2KB"},
+	}}
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "mydb.codes", SQL: "ALTER TABLE codes FORCE",
+				Predictions: []predictor.Prediction{{Description: "FORCE", Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone, TableRebuild: true,
+					RiskLevel: meta.RiskMedium, RiskScore: score}}},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "Risk Level         : MEDIUM (score 20)") {
+		t.Errorf("スコアが表示されること: got\n%s", text)
+	}
+	if !strings.Contains(text, "Risk Factors       : +25 ALGORITHM=INPLACE, +20 Table rebuild, -25 data+index 2KB\n") {
+		t.Errorf("0点以外の要因が表示されること: got\n%s", text)
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if s := result.Analyses[0].RiskScore; s == nil || s.Score != 20 || len(s.Factors) != 4 {
+		t.Errorf("risk_scoreが出力されること: got %+v", s)
+	}
+}
+
+func TestReporterExplain(t *testing.T) {
+	// --explain 指定時にルールの評価過程がテキストとJSONに出力されることを検証
+	ex := &predictor.Explanation{
+		Trace: []predictor.RuleTrace{
+			{RuleID: "ENGINE-NON-INNODB", Reason: "engine InnoDB"},
+			{RuleID: "COL-MODIFY-VARCHAR-EXTEND", Reason: "old varchar(200) → new VARCHAR(300) crosses 255 boundary"},
+			{RuleID: "COL-MODIFY-TYPE-CHANGE", Matched: true, Selected: true, Reason: "type changes: old varchar(200) → new VARCHAR(300)"},
+			{RuleID: "COL-MODIFY-REBUILD", Matched: true, Reason: "no condition"},
+		},
+	}
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "mydb.users", SQL: "ALTER TABLE users MODIFY name VARCHAR(300)",
+				Predictions: []predictor.Prediction{{Description: "MODIFY COLUMN (type change)", RuleID: "COL-MODIFY-TYPE-CHANGE",
+					Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared, TableRebuild: true, RiskLevel: meta.RiskCritical, Explain: ex}}},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := []string{
+		"  Rule Trace:\n",
+		"    [no match] COL-MODIFY-VARCHAR-EXTEND: old varchar(200) → new VARCHAR(300) crosses 255 boundary\n",
+		"    [SELECTED] COL-MODIFY-TYPE-CHANGE: type changes",
+		"    [match   ] COL-MODIFY-REBUILD: no condition\n",
+	}
+	for _, check := range checks {
+		if !strings.Contains(text, check) {
+			t.Errorf("出力に%qが含まれること: got\n%s", check, text)
+		}
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if e := result.Analyses[0].Explain; e == nil || len(e.Trace) != 4 || !e.Trace[2].Selected {
+		t.Errorf("explainが出力されること: got %+v", e)
+	}
+}
+
+func TestReporterPermitted(t *testing.T) {
+	// 指定できるアルゴリズムと最も弱いロックがテキストとJSONに出力されることを検証
+	permitted := []predictor.AlgorithmOption{
+		{Algorithm: meta.AlgorithmInplace, MinLock: meta.LockNone},
+		{Algorithm: meta.AlgorithmCopy, MinLock: meta.LockShared, TableRebuild: true},
+	}
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "mydb.users", SQL: "ALTER TABLE users ADD INDEX idx_email (email)",
+				Predictions: []predictor.Prediction{{Description: "ADD INDEX", Algorithm: meta.AlgorithmInplace, Lock: meta.LockNone,
+					RiskLevel: meta.RiskMedium, Permitted: permitted}}},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "  Permitted          : INSTANT — not supported\n" +
+		"                       INPLACE — LOCK=NONE or stricter\n" +
+		"                       COPY    — LOCK=SHARED or stricter, table rebuild\n"
+	if !strings.Contains(text, want) {
+		t.Errorf("アルゴリズムごとの可否が表示されること: got\n%s", text)
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if got := result.Analyses[0].Permitted; len(got) != 2 || got[1].MinLock != meta.LockShared {
+		t.Errorf("permittedが出力されること: got %+v", got)
+	}
+}
+
+func TestReporterOnlineSchemaChange(t *testing.T) {
+	// gh-ost の互換性と実行コマンドがテキストとJSONに出力されることを検証
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "mydb.orders", SQL: "ALTER TABLE orders MODIFY COLUMN note TEXT",
+				Predictions: []predictor.Prediction{{Description: "MODIFY COLUMN", Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared, RiskLevel: meta.RiskCritical}},
+				OnlineSchemaChange: []osc.Plan{{
+					Tool:     osc.ToolGhost,
+					Command:  "gh-ost \\\n  --table='orders'",
+					Blockers: []string{"Table has triggers (trg_orders_audit) — gh-ost does not support triggers"},
+					Notes:    []string{"Dry run by default — review the output, then add --execute"},
+				}}},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "  Online Schema Change:\n" +
+		"    gh-ost: NOT compatible\n" +
+		"      - [blocker] Table has triggers (trg_orders_audit) — gh-ost does not support triggers\n" +
+		"      - [note] Dry run by default — review the output, then add --execute\n" +
+		"\n" +
+		"      gh-ost \\\n" +
+		"        --table='orders'\n"
+	if !strings.Contains(text, want) {
+		t.Errorf("互換性とコマンドが表示されること: got\n%s", text)
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if got := result.Statements[0].OnlineSchemaChange; len(got) != 1 || got[0].Tool != osc.ToolGhost || got[0].Compatible {
+		t.Errorf("online_schema_changeが出力されること: got %+v", got)
+	}
+}
+
+func TestReporterComparison(t *testing.T) {
+	// 実行方法の比較と推奨がテキストとJSONに出力されることを検証
+	extraDisk := 5 * predictor.GB
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "mydb.orders", SQL: "ALTER TABLE orders MODIFY COLUMN note TEXT",
+				Predictions: []predictor.Prediction{{Description: "MODIFY COLUMN", Algorithm: meta.AlgorithmCopy, Lock: meta.LockShared, RiskLevel: meta.RiskCritical}},
+				Comparison: &osc.Comparison{
+					Methods: []osc.MethodEstimate{
+						{Method: osc.ToolNative, Duration: &predictor.DurationEstimate{MaxSec: 600, Label: "~5m - ~20m"},
+							InitialLock: "brief (prepare phase)", FinalLock: "brief (commit phase)", DMLBlocking: "writes blocked for the whole run",
+							ExtraDiskBytes: &extraDisk, Replication: "lag up to ~20m", ForeignKeys: "none", Compatible: true},
+						{Method: osc.ToolGhost, InitialLock: "none", FinalLock: "brief", Replication: "throttled", ForeignKeys: "none",
+							Blockers: []string{"Table has triggers"}},
+					},
+					Recommended: osc.ToolNative,
+					Reasons:     []string{"No online schema change tool can run this statement"},
+				}},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"  Method Comparison:\n    native\n      Duration      : ~5m - ~20m\n",
+		"      DML Blocking  : writes blocked for the whole run\n      Extra Disk    : 5.0GB\n",
+		"    gh-ost (NOT compatible)\n      Duration      : N/A",
+		"      Extra Disk    : N/A (no table statistics)\n",
+		"    Recommended: native\n      - No online schema change tool can run this statement\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("%q が表示されること: got\n%s", want, text)
+		}
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if got := result.Statements[0].Comparison; got == nil || got.Recommended != osc.ToolNative || len(got.Methods) != 2 {
+		t.Errorf("comparisonが出力されること: got %+v", got)
+	}
+}
+
+func TestReporterRollback(t *testing.T) {
+	// 元に戻す DDL とそのロック動作の予測がテキストとJSONに出力されることを検証
+	report := &Report{
+		Analyses: []AnalysisResult{
+			{Table: "mydb.users", SQL: "ALTER TABLE users DROP COLUMN status",
+				Predictions: []predictor.Prediction{{Description: "DROP COLUMN", Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone, RiskLevel: meta.RiskLow}},
+				Rollback: &rollback.Plan{
+					SQL: "ALTER TABLE `mydb`.`users` ADD COLUMN `status` VARCHAR(20) NULL AFTER `email`;",
+					Steps: []rollback.Step{{Action: meta.ActionDropColumn, Status: rollback.StatusLossy,
+						SQL: "ADD COLUMN `status` VARCHAR(20) NULL AFTER `email`", Reason: "column data is lost — the rollback restores the definition only"}},
+					Irreversible: true,
+					Verdict:      &predictor.StatementVerdict{Algorithm: meta.AlgorithmInstant, Lock: meta.LockNone, RiskLevel: meta.RiskLow},
+				}},
+		},
+	}
+
+	text, err := NewTextReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"  Rollback:\n    ALTER TABLE `mydb`.`users` ADD COLUMN `status` VARCHAR(20) NULL AFTER `email`;\n",
+		"    - [LOSSY] DROP_COLUMN: column data is lost — the rollback restores the definition only\n",
+		"    Algorithm     : INSTANT\n",
+		"    Warning: the rollback cannot fully restore the table",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("%q が表示されること: got\n%s", want, text)
+		}
+	}
+
+	output, err := NewJSONReporter().Render(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result jsonOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("不正なJSON出力: %v", err)
+	}
+	if got := result.Statements[0].Rollback; got == nil || !got.Irreversible || got.Steps[0].Status != rollback.StatusLossy {
+		t.Errorf("rollbackが出力されること: got %+v", got)
+	}
+}
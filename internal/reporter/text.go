@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
 )
 
 // TextReporter は人間が読みやすいテキスト形式で結果を出力する。
@@ -28,20 +29,35 @@ func (r *TextReporter) Render(report *Report) (string, error) {
 		r.renderAnalysis(&sb, &analysis)
 	}
 
+	fmt.Fprintf(&sb, "\nSummary: %d statement(s) analyzed, worst risk level: %s\n", len(report.Analyses), report.WorstRiskLevel())
+	if hasDurationEstimate(report) {
+		sb.WriteString("Note: estimated durations are rough heuristics — actual time depends on disk I/O, CPU and concurrent load\n")
+	}
+
 	return sb.String(), nil
 }
 
 func (r *TextReporter) renderAnalysis(sb *strings.Builder, analysis *AnalysisResult) {
-	fmt.Fprintf(sb, "\nTable: %s\n", analysis.Table)
+	sb.WriteString("\n")
+	if analysis.File != "" {
+		fmt.Fprintf(sb, "File:  %s\n", analysis.File)
+	}
+	fmt.Fprintf(sb, "Table: %s\n", analysis.Table)
 	fmt.Fprintf(sb, "SQL:   %s\n", analysis.SQL)
 
 	for _, pred := range analysis.Predictions {
-		fmt.Fprintf(sb, "\n  Operation     : %s\n", pred.Description)
-		fmt.Fprintf(sb, "  Algorithm     : %s\n", pred.Algorithm)
-		fmt.Fprintf(sb, "  Lock Level    : %s%s\n", pred.Lock, lockDescription(pred.Lock))
-		fmt.Fprintf(sb, "  Table Rebuild : %s\n", boolYesNo(pred.TableRebuild))
-		fmt.Fprintf(sb, "  Table Info    : %s\n", pred.TableInfo.Label)
-		fmt.Fprintf(sb, "  Risk Level    : %s\n", pred.RiskLevel)
+		fmt.Fprintf(sb, "\n  Operation          : %s\n", pred.Description)
+		if pred.RuleID != "" {
+			fmt.Fprintf(sb, "  Rule               : %s%s\n", pred.RuleID, ruleSourceSuffix(pred.RuleSource))
+		}
+		fmt.Fprintf(sb, "  Algorithm          : %s\n", pred.Algorithm)
+		fmt.Fprintf(sb, "  Lock Level         : %s%s\n", pred.Lock, lockDescription(pred.Lock))
+		fmt.Fprintf(sb, "  Table Rebuild      : %s\n", boolYesNo(pred.TableRebuild))
+		renderPermitted(sb, pred.Permitted)
+		fmt.Fprintf(sb, "  Estimated Duration : %s\n", durationLabel(pred.EstimatedDuration))
+		fmt.Fprintf(sb, "  Table Info         : %s\n", pred.TableInfo.Label)
+		fmt.Fprintf(sb, "  Risk Level         : %s%s\n", pred.RiskLevel, scoreSuffix(pred.RiskScore))
+		renderRiskFactors(sb, pred.RiskScore)
 
 		if len(pred.Notes) > 0 {
 			sb.WriteString("\n  Note:\n")
@@ -56,11 +72,160 @@ func (r *TextReporter) renderAnalysis(sb *strings.Builder, analysis *AnalysisRes
 				fmt.Fprintf(sb, "    - %s\n", w)
 			}
 		}
+
+		if pred.DocURL != "" {
+			fmt.Fprintf(sb, "\n  Reference: %s\n", pred.DocURL)
+		}
+		renderExplanation(sb, pred.Explain)
 	}
 
+	r.renderVerdict(sb, analysis)
+	r.renderSplit(sb, analysis)
+	r.renderFindings(sb, analysis)
+	r.renderOnlineSchemaChange(sb, analysis)
+	r.renderComparison(sb, analysis)
+	r.renderRollback(sb, analysis)
 	r.renderFKPropagation(sb, analysis)
 }
 
+// renderVerdict は複数アクションを含む文について、文全体として実行される判定を出力する。
+func (r *TextReporter) renderVerdict(sb *strings.Builder, analysis *AnalysisResult) {
+	v := analysis.Verdict
+	if v == nil || len(analysis.Predictions) < 2 {
+		return
+	}
+	fmt.Fprintf(sb, "\n  Statement          : %d actions executed as a single ALTER\n", len(analysis.Predictions))
+	fmt.Fprintf(sb, "  Algorithm          : %s%s\n", v.Algorithm, reasonSuffix(v.AlgorithmReason))
+	fmt.Fprintf(sb, "  Lock Level         : %s%s\n", v.Lock, lockDescription(v.Lock))
+	fmt.Fprintf(sb, "  Table Rebuild      : %s\n", boolYesNo(v.TableRebuild))
+	renderPermitted(sb, v.Permitted)
+	fmt.Fprintf(sb, "  Risk Level         : %s%s\n", v.RiskLevel, scoreSuffix(v.RiskScore))
+	renderRiskFactors(sb, v.RiskScore)
+
+	if len(v.Notes) > 0 {
+		sb.WriteString("\n  Note:\n")
+		for _, note := range v.Notes {
+			fmt.Fprintf(sb, "    - %s\n", note)
+		}
+	}
+}
+
+// renderSplit は INSTANT の操作を再構築から切り離すための分割案を出力する。
+func (r *TextReporter) renderSplit(sb *strings.Builder, analysis *AnalysisResult) {
+	if len(analysis.Split) == 0 {
+		return
+	}
+	sb.WriteString("\n  Suggested Split:\n")
+	for i, s := range analysis.Split {
+		v := s.Verdict
+		fmt.Fprintf(sb, "    %d. [%s] ALGORITHM=%s, LOCK=%s, Rebuild=%s\n", i+1, v.RiskLevel, v.Algorithm, v.Lock, boolYesNo(v.TableRebuild))
+		fmt.Fprintf(sb, "       %s\n", s.SQL)
+	}
+}
+
+func (r *TextReporter) renderFindings(sb *strings.Builder, analysis *AnalysisResult) {
+	if len(analysis.Findings) == 0 {
+		return
+	}
+	sb.WriteString("\n  Findings:\n")
+	for _, f := range analysis.Findings {
+		if f.RuleID != "" {
+			fmt.Fprintf(sb, "    - [%s] %s (policy rule %s)\n", f.Severity, f.Message, f.RuleID)
+			continue
+		}
+		if f.ErrorCode != 0 {
+			fmt.Fprintf(sb, "    - [%s] %s (MySQL error %d)\n", f.Severity, f.Message, f.ErrorCode)
+			continue
+		}
+		fmt.Fprintf(sb, "    - [%s] %s\n", f.Severity, f.Message)
+	}
+}
+
+// renderOnlineSchemaChange はオンラインスキーマ変更ツールの互換性と実行コマンドを出力する。
+func (r *TextReporter) renderOnlineSchemaChange(sb *strings.Builder, analysis *AnalysisResult) {
+	if len(analysis.OnlineSchemaChange) == 0 {
+		return
+	}
+	sb.WriteString("\n  Online Schema Change:\n")
+	for _, plan := range analysis.OnlineSchemaChange {
+		status := "compatible"
+		if !plan.Compatible {
+			status = "NOT compatible"
+		}
+		fmt.Fprintf(sb, "    %s: %s\n", plan.Tool, status)
+		for _, b := range plan.Blockers {
+			fmt.Fprintf(sb, "      - [blocker] %s\n", b)
+		}
+		for _, w := range plan.Warnings {
+			fmt.Fprintf(sb, "      - [warning] %s\n", w)
+		}
+		for _, n := range plan.Notes {
+			fmt.Fprintf(sb, "      - [note] %s\n", n)
+		}
+		sb.WriteString("\n")
+		for _, line := range strings.Split(plan.Command, "\n") {
+			fmt.Fprintf(sb, "      %s\n", line)
+		}
+	}
+}
+
+// renderComparison は実行方法ごとの推定コストと推奨する方法を出力する。
+func (r *TextReporter) renderComparison(sb *strings.Builder, analysis *AnalysisResult) {
+	c := analysis.Comparison
+	if c == nil {
+		return
+	}
+	sb.WriteString("\n  Method Comparison:\n")
+	for _, m := range c.Methods {
+		status := ""
+		if !m.Compatible {
+			status = " (NOT compatible)"
+		}
+		fmt.Fprintf(sb, "    %s%s\n", m.Method, status)
+		fmt.Fprintf(sb, "      Duration      : %s\n", durationLabel(m.Duration))
+		fmt.Fprintf(sb, "      Initial MDL X : %s\n", m.InitialLock)
+		fmt.Fprintf(sb, "      Final MDL X   : %s\n", m.FinalLock)
+		if m.DMLBlocking != "" {
+			fmt.Fprintf(sb, "      DML Blocking  : %s\n", m.DMLBlocking)
+		}
+		fmt.Fprintf(sb, "      Extra Disk    : %s\n", diskLabel(m.ExtraDiskBytes))
+		fmt.Fprintf(sb, "      Replication   : %s\n", m.Replication)
+		fmt.Fprintf(sb, "      Foreign Keys  : %s\n", m.ForeignKeys)
+	}
+	fmt.Fprintf(sb, "    Recommended: %s\n", c.Recommended)
+	for _, reason := range c.Reasons {
+		fmt.Fprintf(sb, "      - %s\n", reason)
+	}
+}
+
+// renderRollback は文を元に戻す DDL と、アクションごとに元に戻せるか、元に戻す文のロック動作を出力する。
+func (r *TextReporter) renderRollback(sb *strings.Builder, analysis *AnalysisResult) {
+	rb := analysis.Rollback
+	if rb == nil {
+		return
+	}
+	sb.WriteString("\n  Rollback:\n")
+	if rb.SQL != "" {
+		fmt.Fprintf(sb, "    %s\n", rb.SQL)
+	}
+	for _, step := range rb.Steps {
+		if step.Reason != "" {
+			fmt.Fprintf(sb, "    - [%s] %s: %s\n", step.Status, step.Action, step.Reason)
+			continue
+		}
+		fmt.Fprintf(sb, "    - [%s] %s\n", step.Status, step.Action)
+	}
+	if v := rb.Verdict; v != nil {
+		fmt.Fprintf(sb, "    Algorithm     : %s\n", v.Algorithm)
+		fmt.Fprintf(sb, "    Lock Level    : %s%s\n", v.Lock, lockDescription(v.Lock))
+		fmt.Fprintf(sb, "    Table Rebuild : %s\n", boolYesNo(v.TableRebuild))
+		fmt.Fprintf(sb, "    Risk Level    : %s%s\n", v.RiskLevel, scoreSuffix(v.RiskScore))
+	}
+	if rb.Irreversible {
+		sb.WriteString("    Warning: the rollback cannot fully restore the table — back up affected data before running the forward DDL\n")
+	}
+}
+
 func (r *TextReporter) renderFKPropagation(sb *strings.Builder, analysis *AnalysisResult) {
 	graph := analysis.FKGraph
 	if graph == nil || graph.TotalAffectedTables() == 0 {
@@ -121,6 +286,117 @@ func lockDescription(lock meta.LockLevel) string {
 	}
 }
 
+func diskLabel(bytes *int64) string {
+	if bytes == nil {
+		return "N/A (no table statistics)"
+	}
+	return predictor.FormatSize(*bytes)
+}
+
+func durationLabel(est *predictor.DurationEstimate) string {
+	if est == nil {
+		return "N/A (no table statistics)"
+	}
+	return est.Label
+}
+
+// hasDurationEstimate はテーブル統計から算出した（0秒でない）推定時間があるかを返す。
+func hasDurationEstimate(report *Report) bool {
+	for _, a := range report.Analyses {
+		for _, p := range a.Predictions {
+			if p.EstimatedDuration != nil && p.EstimatedDuration.MaxSec > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func scoreSuffix(score *predictor.RiskScore) string {
+	if score == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (score %d)", score.Score)
+}
+
+// renderRiskFactors はリスクスコアに寄与した（0点でない）要因を出力する。
+func renderRiskFactors(sb *strings.Builder, score *predictor.RiskScore) {
+	if score == nil {
+		return
+	}
+	var parts []string
+	for _, f := range score.Factors {
+		if f.Points != 0 {
+			parts = append(parts, fmt.Sprintf("%+d %s", f.Points, f.Detail))
+		}
+	}
+	if len(parts) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "  Risk Factors       : %s\n", strings.Join(parts, ", "))
+}
+
+// renderPermitted は各アルゴリズムを指定できるか、指定できる場合の最も弱いロックを出力する。
+func renderPermitted(sb *strings.Builder, opts []predictor.AlgorithmOption) {
+	if len(opts) == 0 {
+		return
+	}
+	label := "  Permitted          : "
+	for _, alg := range []meta.Algorithm{meta.AlgorithmInstant, meta.AlgorithmInplace, meta.AlgorithmCopy} {
+		desc := "not supported"
+		for _, o := range opts {
+			if o.Algorithm != alg {
+				continue
+			}
+			if o.MinLock == meta.LockDefault {
+				desc = "LOCK=DEFAULT only"
+			} else {
+				desc = fmt.Sprintf("LOCK=%s or stricter", o.MinLock)
+			}
+			if o.TableRebuild {
+				desc += ", table rebuild"
+			}
+		}
+		fmt.Fprintf(sb, "%s%-7s — %s\n", label, alg, desc)
+		label = strings.Repeat(" ", len(label))
+	}
+}
+
+// renderExplanation は --explain 指定時にルールの評価過程を評価順に出力する。
+func renderExplanation(sb *strings.Builder, ex *predictor.Explanation) {
+	if ex == nil {
+		return
+	}
+	sb.WriteString("\n  Rule Trace:\n")
+	for _, t := range ex.Trace {
+		mark := "no match"
+		switch {
+		case t.Selected:
+			mark = "SELECTED"
+		case t.Matched:
+			mark = "match"
+		}
+		fmt.Fprintf(sb, "    [%-8s] %s%s: %s\n", mark, t.RuleID, ruleSourceSuffix(t.Source), t.Reason)
+	}
+	for _, a := range ex.Adjustments {
+		fmt.Fprintf(sb, "    [adjusted] %s\n", a)
+	}
+}
+
+func ruleSourceSuffix(source string) string {
+	if source == "" {
+		return ""
+	}
+	return " (custom rule, " + source + ")"
+}
+
+func reasonSuffix(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return " (required by " + reason + ")"
+}
+
 func boolYesNo(b bool) string {
 	if b {
 		return "Yes"
@@ -0,0 +1,271 @@
+package rollback
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// columnDefinition は ColumnMeta から ADD/MODIFY COLUMN のカラム定義を作成する。
+// 生成カラムは式がメタデータにないため false を返す。
+func columnDefinition(col meta.ColumnMeta) (string, bool) {
+	extra := strings.ToLower(col.Extra)
+	if strings.Contains(extra, "generated") && !strings.Contains(extra, "default_generated") {
+		return "", false
+	}
+
+	var sb strings.Builder
+	sb.WriteString(quoteIdent(col.Name) + " " + upperOutsideQuotes(col.ColumnType))
+	if col.CharacterSet != "" {
+		sb.WriteString(" CHARACTER SET " + col.CharacterSet)
+		if col.Collation != "" {
+			sb.WriteString(" COLLATE " + col.Collation)
+		}
+	}
+	if col.IsNullable {
+		sb.WriteString(" NULL")
+	} else {
+		sb.WriteString(" NOT NULL")
+	}
+	if hasDefault(col) {
+		sb.WriteString(" DEFAULT " + defaultLiteral(col))
+	}
+	if strings.Contains(extra, "auto_increment") {
+		sb.WriteString(" AUTO_INCREMENT")
+	}
+	if i := strings.Index(extra, "on update "); i >= 0 {
+		sb.WriteString(" ON UPDATE " + strings.ToUpper(col.Extra[i+len("on update "):]))
+	}
+	return sb.String(), true
+}
+
+// columnAction はカラムを ColumnMeta の定義に戻すアクション（SQL を除く）を返す。予測に使う。
+func columnAction(t meta.AlterActionType, col meta.ColumnMeta, position string) meta.AlterAction {
+	nullable := col.IsNullable
+	def := ""
+	if hasDefault(col) {
+		def = defaultLiteral(col)
+	}
+	return meta.AlterAction{
+		Type: t,
+		Detail: meta.ActionDetail{
+			ColumnName:      col.Name,
+			ColumnType:      upperOutsideQuotes(col.ColumnType),
+			IsNullable:      &nullable,
+			DefaultValue:    def,
+			Position:        position,
+			IsAutoIncrement: strings.Contains(strings.ToLower(col.Extra), "auto_increment"),
+		},
+	}
+}
+
+// hasDefault はカラムに DEFAULT があるかを返す。has_default を含まない古いメタ情報は値の有無で判定する。
+func hasDefault(col meta.ColumnMeta) bool {
+	return col.HasDefault || col.DefaultValue != ""
+}
+
+// defaultLiteral は COLUMN_DEFAULT の値を DEFAULT 句に書ける形式にする。
+// 数値型と式（CURRENT_TIMESTAMP など）はそのまま、それ以外は文字列リテラルにする。
+func defaultLiteral(col meta.ColumnMeta) string {
+	v := col.DefaultValue
+	if strings.Contains(strings.ToLower(col.Extra), "default_generated") || strings.HasPrefix(strings.ToUpper(v), "CURRENT_TIMESTAMP") {
+		return v
+	}
+	switch typeFamily(baseType(col.ColumnType)) {
+	case familyInteger, familyDecimal, familyFloat:
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return v
+		}
+	}
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
+
+// 型の系統。系統をまたぐ変更は値が変換できない可能性があるためデータ損失とみなす。
+const (
+	familyInteger = "integer"
+	familyDecimal = "decimal"
+	familyFloat   = "float"
+	familyString  = "string"
+	familyBinary  = "binary"
+	familyTime    = "temporal"
+	familyEnum    = "enum"
+	familySet     = "set"
+	familyOther   = "other"
+)
+
+// integerBytes は整数型のバイト数。
+var integerBytes = map[string]int{"tinyint": 1, "smallint": 2, "mediumint": 3, "int": 4, "integer": 4, "bigint": 8}
+
+// lobCapacity は TEXT/BLOB 型の最大長。
+var lobCapacity = map[string]int64{
+	"tinytext": 255, "text": 65535, "mediumtext": 16777215, "longtext": 4294967295,
+	"tinyblob": 255, "blob": 65535, "mediumblob": 16777215, "longblob": 4294967295,
+}
+
+func typeFamily(base string) string {
+	switch {
+	case integerBytes[base] > 0:
+		return familyInteger
+	case base == "decimal" || base == "numeric":
+		return familyDecimal
+	case base == "float" || base == "double" || base == "real":
+		return familyFloat
+	case base == "char" || base == "varchar" || strings.HasSuffix(base, "text"):
+		return familyString
+	case base == "binary" || base == "varbinary" || strings.HasSuffix(base, "blob"):
+		return familyBinary
+	case base == "date" || base == "datetime" || base == "timestamp" || base == "time" || base == "year":
+		return familyTime
+	case base == "enum":
+		return familyEnum
+	case base == "set":
+		return familySet
+	default:
+		return familyOther
+	}
+}
+
+// narrowing は oldType から newType への変更で値が失われるか（型の縮小）を判定し、理由を返す。
+func narrowing(oldType, newType string) (bool, string) {
+	oldT, newT := strings.ToLower(strings.TrimSpace(oldType)), strings.ToLower(strings.TrimSpace(newType))
+	if oldT == "" || newT == "" || oldT == newT {
+		return false, ""
+	}
+	lossy := func(detail string) (bool, string) {
+		return true, fmt.Sprintf("%s → %s %s — values changed by the narrowing cannot be restored", oldT, newT, detail)
+	}
+
+	oldBase, newBase := baseType(oldT), baseType(newT)
+	oldFamily, newFamily := typeFamily(oldBase), typeFamily(newBase)
+	if oldFamily != newFamily {
+		return lossy("changes the type family")
+	}
+	switch oldFamily {
+	case familyInteger:
+		oldUnsigned, newUnsigned := strings.Contains(oldT, "unsigned"), strings.Contains(newT, "unsigned")
+		switch {
+		case integerBytes[newBase] < integerBytes[oldBase]:
+			return lossy("narrows the integer range")
+		case !oldUnsigned && newUnsigned:
+			return lossy("drops negative values")
+		case oldUnsigned && !newUnsigned && integerBytes[newBase] == integerBytes[oldBase]:
+			return lossy("halves the maximum value")
+		}
+	case familyDecimal:
+		oldP, oldS := precisionScale(oldT)
+		newP, newS := precisionScale(newT)
+		if newP-newS < oldP-oldS || newS < oldS {
+			return lossy("reduces precision or scale")
+		}
+	case familyFloat:
+		if oldBase != "float" && newBase == "float" {
+			return lossy("reduces floating point precision")
+		}
+	case familyString, familyBinary:
+		if capacity(newT) < capacity(oldT) {
+			return lossy("shortens the maximum length")
+		}
+	case familyTime:
+		if oldBase != newBase {
+			if oldBase == "date" && (newBase == "datetime" || newBase == "timestamp") {
+				return false, ""
+			}
+			return lossy("changes the temporal type")
+		}
+		if fsp(newT) < fsp(oldT) {
+			return lossy("reduces fractional seconds precision")
+		}
+	case familyEnum, familySet:
+		newValues := make(map[string]bool)
+		for _, v := range enumValues(newT) {
+			newValues[v] = true
+		}
+		for _, v := range enumValues(oldT) {
+			if !newValues[v] {
+				return lossy("removes the value " + v)
+			}
+		}
+	default:
+		return lossy("changes the type")
+	}
+	return false, ""
+}
+
+// baseType は "varchar(255)" や "int unsigned" から型名を返す。
+func baseType(t string) string {
+	t = strings.ToLower(strings.TrimSpace(t))
+	if i := strings.IndexAny(t, "( "); i >= 0 {
+		return t[:i]
+	}
+	return t
+}
+
+// typeArgs は括弧内の引数を返す。
+func typeArgs(t string) []string {
+	open := strings.Index(t, "(")
+	closing := strings.LastIndex(t, ")")
+	if open < 0 || closing < open {
+		return nil
+	}
+	return strings.Split(t[open+1:closing], ",")
+}
+
+func intArg(args []string, i, def int) int {
+	if i >= len(args) {
+		return def
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(args[i]))
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// precisionScale は DECIMAL の精度と位取りを返す（省略時は DECIMAL(10,0)）。
+func precisionScale(t string) (int, int) {
+	args := typeArgs(t)
+	return intArg(args, 0, 10), intArg(args, 1, 0)
+}
+
+// capacity は文字列・バイナリ型の最大長を返す。
+func capacity(t string) int64 {
+	base := baseType(t)
+	if n, ok := lobCapacity[base]; ok {
+		return n
+	}
+	return int64(intArg(typeArgs(t), 0, 1))
+}
+
+// fsp は時刻型の小数秒の精度を返す。
+func fsp(t string) int {
+	return intArg(typeArgs(t), 0, 0)
+}
+
+// enumValues は ENUM/SET の値を定義順に返す。
+func enumValues(t string) []string {
+	args := typeArgs(t)
+	values := make([]string, 0, len(args))
+	for _, v := range args {
+		values = append(values, strings.Trim(strings.TrimSpace(v), "'"))
+	}
+	return values
+}
+
+// upperOutsideQuotes は ENUM/SET のリテラル以外を大文字にする。
+func upperOutsideQuotes(s string) string {
+	var sb strings.Builder
+	inQuote := false
+	for _, r := range s {
+		if r == '\'' {
+			inQuote = !inQuote
+		}
+		if inQuote {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteString(strings.ToUpper(string(r)))
+		}
+	}
+	return sb.String()
+}
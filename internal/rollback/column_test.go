@@ -0,0 +1,74 @@
+package rollback
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+func TestNarrowing(t *testing.T) {
+	tests := []struct {
+		old, new string
+		want     bool
+	}{
+		{"varchar(255)", "VARCHAR(100)", true},
+		{"varchar(100)", "VARCHAR(255)", false},
+		{"varchar(255)", "TEXT", false},
+		{"text", "VARCHAR(255)", true},
+		{"bigint", "INT", true},
+		{"int", "BIGINT", false},
+		{"int", "INT UNSIGNED", true},
+		{"int unsigned", "BIGINT", false},
+		{"int unsigned", "INT", true},
+		{"decimal(10,2)", "DECIMAL(12,2)", false},
+		{"decimal(10,2)", "DECIMAL(10,1)", true},
+		{"double", "FLOAT", true},
+		{"datetime(6)", "DATETIME", true},
+		{"date", "DATETIME", false},
+		{"enum('a','b','c')", "ENUM('a','b')", true},
+		{"enum('a','b')", "ENUM('a','b','c')", false},
+		{"varchar(20)", "INT", true},
+		{"json", "JSON", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.old+"->"+tt.new, func(t *testing.T) {
+			got, reason := narrowing(tt.old, tt.new)
+			if got != tt.want {
+				t.Errorf("got %t (%s), want %t", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnDefinition(t *testing.T) {
+	tests := []struct {
+		name string
+		col  meta.ColumnMeta
+		want string
+	}{
+		{"AUTO_INCREMENT", meta.ColumnMeta{Name: "id", ColumnType: "bigint unsigned", Extra: "auto_increment"},
+			"`id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT"},
+		{"文字列のデフォルト", meta.ColumnMeta{Name: "memo", ColumnType: "varchar(10)", IsNullable: true, DefaultValue: "it's"},
+			"`memo` VARCHAR(10) NULL DEFAULT 'it''s'"},
+		{"空文字のデフォルト", meta.ColumnMeta{Name: "memo", ColumnType: "varchar(10)", HasDefault: true},
+			"`memo` VARCHAR(10) NOT NULL DEFAULT ''"},
+		{"デフォルトなし", meta.ColumnMeta{Name: "memo", ColumnType: "varchar(10)"},
+			"`memo` VARCHAR(10) NOT NULL"},
+		{"ENUM", meta.ColumnMeta{Name: "kind", ColumnType: "enum('a','B')", DefaultValue: "a"},
+			"`kind` ENUM('a','B') NOT NULL DEFAULT 'a'"},
+		{"CURRENT_TIMESTAMP", meta.ColumnMeta{Name: "updated_at", ColumnType: "timestamp", DefaultValue: "CURRENT_TIMESTAMP", Extra: "DEFAULT_GENERATED on update CURRENT_TIMESTAMP"},
+			"`updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := columnDefinition(tt.col)
+			if !ok || got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := columnDefinition(meta.ColumnMeta{Name: "total", ColumnType: "int", Extra: "STORED GENERATED"}); ok {
+		t.Error("生成カラムは定義を作成できないこと")
+	}
+}
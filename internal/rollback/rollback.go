@@ -0,0 +1,575 @@
+// Package rollback は解析した ALTER 文を元に戻す（down）DDL を、実行前のテーブル定義から生成する。
+package rollback
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+// Status はアクションを元に戻せるかを表す。
+type Status string
+
+const (
+	// StatusReversible は元に戻す DDL で変更前の状態に戻せる。
+	StatusReversible Status = "REVERSIBLE"
+	// StatusLossy は定義は戻せるが、変更時に失われたデータは戻らない。
+	StatusLossy Status = "LOSSY"
+	// StatusIrreversible は削除された行など、元に戻せない変更。
+	StatusIrreversible Status = "IRREVERSIBLE"
+	// StatusManual は元の定義がメタデータにないため、元に戻す DDL を生成できない。
+	StatusManual Status = "MANUAL"
+	// StatusNone は元に戻す必要がない（最適化やテーブル統計の変更など）。
+	StatusNone Status = "NONE"
+)
+
+// Step は1つのアクションを元に戻す方法を表す。
+type Step struct {
+	// Action は元に戻す対象の（変更時の）アクション
+	Action meta.AlterActionType `json:"action"`
+	Status Status               `json:"status"`
+	// SQL は元に戻す ALTER TABLE 句（生成できない、または不要な場合は空）
+	SQL    string `json:"sql,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// inverse は SQL に対応するアクション（予測に使う）
+	inverse []meta.AlterAction
+}
+
+// Plan は ALTER 文を元に戻す DDL とその予測を表す。
+type Plan struct {
+	// SQL は元に戻す ALTER TABLE 文（元に戻す句がない場合は空）
+	SQL   string `json:"sql,omitempty"`
+	Steps []Step `json:"steps"`
+	// Irreversible は元に戻せない、またはデータが失われるアクションを含むこと
+	Irreversible bool                        `json:"irreversible"`
+	Predictions  []predictor.Prediction      `json:"predictions,omitempty"`
+	Verdict      *predictor.StatementVerdict `json:"verdict,omitempty"`
+	// Operation は元に戻す ALTER 文（RENAME TABLE を含む場合は変更後のテーブル名に対する文）
+	Operation meta.AlterOperation `json:"-"`
+}
+
+// Build は op を元に戻す DDL を、op 実行前のテーブル定義 tm から作成する。
+// アクションは逆順に元に戻す。tm が nil の場合、元の定義が必要なアクションは StatusManual になる。
+func Build(op meta.AlterOperation, tm *meta.TableMeta) *Plan {
+	b := &builder{op: op, tm: tm, addedColumns: make(map[string]int), restoredIndexes: make(map[string]bool)}
+	plan := &Plan{Operation: meta.AlterOperation{Schema: op.Schema, Table: op.Table, TableRef: op.TableRef}}
+
+	for i := len(op.Actions) - 1; i >= 0; i-- {
+		a := op.Actions[i]
+		step := b.invert(a)
+		step.Action = a.Type
+		step.SQL = step.clauses()
+		if a.Type == meta.ActionRenameTable && a.Detail.ColumnName != "" {
+			plan.Operation.Table = a.Detail.ColumnName
+			plan.Operation.TableRef = ""
+		}
+		if step.Status == StatusLossy || step.Status == StatusIrreversible {
+			plan.Irreversible = true
+		}
+		plan.Operation.Actions = append(plan.Operation.Actions, step.inverse...)
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	// 削除したカラムは AFTER で参照できるよう元の順に追加する
+	sort.SliceStable(plan.Operation.Actions, func(i, j int) bool {
+		return b.addOrder(plan.Operation.Actions[i]) < b.addOrder(plan.Operation.Actions[j])
+	})
+
+	if len(plan.Operation.Actions) > 0 {
+		clauses := make([]string, 0, len(plan.Operation.Actions))
+		for _, a := range plan.Operation.Actions {
+			clauses = append(clauses, a.SQL)
+		}
+		plan.SQL = "ALTER TABLE " + tableRef(plan.Operation) + " " + strings.Join(clauses, ", ") + ";"
+		plan.Operation.RawSQL = plan.SQL
+	}
+	return plan
+}
+
+// Predict は元に戻す ALTER 文のロック動作を予測する。
+// tm は変更適用後のテーブル定義（元に戻す文の実行前の定義）。
+func (p *Plan) Predict(pr *predictor.Predictor, tm *meta.TableMeta) {
+	if len(p.Operation.Actions) == 0 {
+		return
+	}
+	p.Predictions = pr.PredictAll(p.Operation, tm)
+	verdict := predictor.CombinePredictions(p.Predictions)
+	p.Verdict = &verdict
+}
+
+type builder struct {
+	op meta.AlterOperation
+	tm *meta.TableMeta
+	// addedColumns は DROP COLUMN を戻すために追加するカラムの元の位置
+	addedColumns map[string]int
+	// restoredIndexes は復元済みのインデックス名（DROP INDEX と DROP COLUMN の重複を避ける）
+	restoredIndexes map[string]bool
+}
+
+func (b *builder) invert(a meta.AlterAction) Step {
+	d := a.Detail
+	switch a.Type {
+	case meta.ActionAddColumn:
+		return reversible("values stored in the new column are dropped", dropColumn(d.ColumnName))
+	case meta.ActionDropColumn:
+		return b.invertDropColumn(d.ColumnName)
+	case meta.ActionModifyColumn, meta.ActionChangeColumn:
+		return b.invertModifyColumn(a)
+	case meta.ActionRenameColumn:
+		return reversible("", meta.AlterAction{
+			Type:   meta.ActionRenameColumn,
+			Detail: meta.ActionDetail{ColumnName: d.OldColumnName, OldColumnName: d.ColumnName},
+			SQL:    "RENAME COLUMN " + quoteIdent(d.ColumnName) + " TO " + quoteIdent(d.OldColumnName),
+		})
+	case meta.ActionSetDefault, meta.ActionDropDefault:
+		return b.invertDefault(a)
+	case meta.ActionAddIndex, meta.ActionAddUniqueIndex, meta.ActionAddFulltextIndex, meta.ActionAddSpatialIndex:
+		name := d.IndexName
+		reason := ""
+		if name == "" && len(d.IndexColumns) > 0 {
+			// 名前のないインデックスは最初のカラム名で作成される
+			name = d.IndexColumns[0]
+			reason = "index name " + name + " assumed from the first column"
+		}
+		return reversible(reason, dropIndex(name))
+	case meta.ActionDropIndex:
+		idx := b.findIndex(d.IndexName)
+		if idx == nil {
+			return manual(b.missing("index " + d.IndexName))
+		}
+		if idx.HasExpression {
+			return manual(expressionIndex(*idx))
+		}
+		return reversible("", b.restoreIndex(*idx)...)
+	case meta.ActionRenameIndex:
+		return reversible("", meta.AlterAction{
+			Type:   meta.ActionRenameIndex,
+			Detail: meta.ActionDetail{IndexName: d.OldIndexName, OldIndexName: d.IndexName},
+			SQL:    "RENAME INDEX " + quoteIdent(d.IndexName) + " TO " + quoteIdent(d.OldIndexName),
+		})
+	case meta.ActionAddPrimaryKey:
+		return reversible("", meta.AlterAction{Type: meta.ActionDropPrimaryKey, SQL: "DROP PRIMARY KEY"})
+	case meta.ActionDropPrimaryKey:
+		idx := b.primaryKey()
+		if idx == nil {
+			return manual(b.missing("PRIMARY KEY"))
+		}
+		return reversible("", addIndex(*idx))
+	case meta.ActionAddForeignKey:
+		return b.invertAddForeignKey(d)
+	case meta.ActionDropForeignKey:
+		return b.invertDropForeignKey(d.ConstraintName)
+	case meta.ActionRenameTable:
+		return reversible("", meta.AlterAction{
+			Type:   meta.ActionRenameTable,
+			Detail: meta.ActionDetail{ColumnName: b.op.Table},
+			SQL:    "RENAME TO " + quoteIdent(b.op.Table),
+		})
+	case meta.ActionChangeEngine:
+		if b.tm == nil || b.tm.Engine == "" {
+			return manual(b.missing("engine"))
+		}
+		return reversible("", meta.AlterAction{
+			Type:   meta.ActionChangeEngine,
+			Detail: meta.ActionDetail{Engine: b.tm.Engine},
+			SQL:    "ENGINE = " + b.tm.Engine,
+		})
+	case meta.ActionConvertCharset:
+		return b.invertConvertCharset(d.Charset)
+	case meta.ActionPartitionBy:
+		if b.tm == nil || b.tm.IsPartitioned {
+			return manual("previous partition definition is not in the table metadata")
+		}
+		return reversible("", meta.AlterAction{Type: meta.ActionRemovePartitioning, SQL: "REMOVE PARTITIONING"})
+	case meta.ActionDropPartition:
+		return irreversible("rows in the dropped partitions are deleted")
+	case meta.ActionTruncatePartition:
+		return irreversible("rows in the truncated partitions are deleted")
+	case meta.ActionForceRebuild, meta.ActionCheckPartition, meta.ActionOptimizePartition, meta.ActionRepairPartition,
+		meta.ActionRebuildPartition, meta.ActionSetTableStats, meta.ActionChangeAutoIncrement:
+		return Step{Status: StatusNone, Reason: "does not change the table definition"}
+	default:
+		return manual(fmt.Sprintf("previous %s setting is not in the table metadata", strings.ToLower(strings.ReplaceAll(string(a.Type), "_", " "))))
+	}
+}
+
+func (b *builder) invertDropColumn(name string) Step {
+	col, pos := b.findColumn(name)
+	if col == nil {
+		return manual(b.missing("column " + name))
+	}
+	def, ok := columnDefinition(*col)
+	if !ok {
+		return manual("generated column expression of " + name + " is not in the table metadata")
+	}
+	add := columnAction(meta.ActionAddColumn, *col, b.position(pos))
+	b.addedColumns[strings.ToLower(col.Name)] = pos
+	add.SQL = "ADD COLUMN " + def + positionClause(add.Detail.Position)
+	inverse := []meta.AlterAction{add}
+
+	// DROP COLUMN で削除・縮小されたインデックスを元の定義に戻す
+	for _, idx := range b.tm.Indexes {
+		if containsFold(idx.Columns, name) {
+			if idx.HasExpression {
+				return manual(expressionIndex(idx))
+			}
+			inverse = append(inverse, b.restoreIndex(idx)...)
+		}
+	}
+	return Step{Status: StatusLossy, Reason: "column data is lost — the rollback restores the definition only", inverse: inverse}
+}
+
+func (b *builder) invertModifyColumn(a meta.AlterAction) Step {
+	d := a.Detail
+	oldName := d.OldColumnName
+	if oldName == "" {
+		oldName = d.ColumnName
+	}
+	col, pos := b.findColumn(oldName)
+	if col == nil {
+		return manual(b.missing("column " + oldName))
+	}
+	def, ok := columnDefinition(*col)
+	if !ok {
+		return manual("generated column expression of " + oldName + " is not in the table metadata")
+	}
+	position := ""
+	if d.Position != "" {
+		position = b.position(pos)
+	}
+
+	inverse := columnAction(a.Type, *col, position)
+	if a.Type == meta.ActionChangeColumn {
+		inverse.Detail.OldColumnName = d.ColumnName
+		inverse.SQL = "CHANGE COLUMN " + quoteIdent(d.ColumnName) + " " + def + positionClause(position)
+	} else {
+		inverse.SQL = "MODIFY COLUMN " + def + positionClause(position)
+	}
+	if lossy, reason := narrowing(col.ColumnType, d.ColumnType); lossy {
+		return Step{Status: StatusLossy, Reason: reason, inverse: []meta.AlterAction{inverse}}
+	}
+	return reversible("", inverse)
+}
+
+func (b *builder) invertDefault(a meta.AlterAction) Step {
+	name := a.Detail.ColumnName
+	col, _ := b.findColumn(name)
+	if col == nil {
+		return manual(b.missing("column " + name))
+	}
+	if !hasDefault(*col) {
+		if a.Type == meta.ActionDropDefault {
+			return Step{Status: StatusNone, Reason: "column had no default"}
+		}
+		return reversible("", meta.AlterAction{
+			Type:   meta.ActionDropDefault,
+			Detail: meta.ActionDetail{ColumnName: name},
+			SQL:    "ALTER COLUMN " + quoteIdent(name) + " DROP DEFAULT",
+		})
+	}
+	return reversible("", meta.AlterAction{
+		Type:   meta.ActionSetDefault,
+		Detail: meta.ActionDetail{ColumnName: name, DefaultValue: defaultLiteral(*col)},
+		SQL:    "ALTER COLUMN " + quoteIdent(name) + " SET DEFAULT " + defaultLiteral(*col),
+	})
+}
+
+func (b *builder) invertAddForeignKey(d meta.ActionDetail) Step {
+	name := d.ConstraintName
+	reason := ""
+	if name == "" {
+		n := 1
+		if b.tm != nil {
+			n = len(b.tm.ForeignKeys) + 1
+		}
+		name = fmt.Sprintf("%s_ibfk_%d", b.op.Table, n)
+		reason = "constraint name " + name + " assumed from MySQL's naming rule"
+	}
+	return reversible(reason, meta.AlterAction{
+		Type:   meta.ActionDropForeignKey,
+		Detail: meta.ActionDetail{ConstraintName: name},
+		SQL:    "DROP FOREIGN KEY " + quoteIdent(name),
+	})
+}
+
+func (b *builder) invertDropForeignKey(name string) Step {
+	if b.tm == nil {
+		return manual(b.missing("foreign key " + name))
+	}
+	for _, fk := range b.tm.ForeignKeys {
+		if !strings.EqualFold(fk.ConstraintName, name) {
+			continue
+		}
+		ref := quoteIdent(fk.ReferencedTable)
+		if fk.ReferencedSchema != "" && !strings.EqualFold(fk.ReferencedSchema, fk.SourceSchema) {
+			ref = quoteIdent(fk.ReferencedSchema) + "." + ref
+		}
+		sql := fmt.Sprintf("ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+			quoteIdent(fk.ConstraintName), quoteList(fk.SourceColumns), ref, quoteList(fk.ReferencedColumns))
+		if fk.OnDelete != "" && !isDefaultRefAction(fk.OnDelete) {
+			sql += " ON DELETE " + fk.OnDelete
+		}
+		if fk.OnUpdate != "" && !isDefaultRefAction(fk.OnUpdate) {
+			sql += " ON UPDATE " + fk.OnUpdate
+		}
+		return reversible("", meta.AlterAction{
+			Type: meta.ActionAddForeignKey,
+			Detail: meta.ActionDetail{
+				ConstraintName: fk.ConstraintName,
+				IndexColumns:   fk.SourceColumns,
+				RefTable:       fk.ReferencedTable,
+				RefColumns:     fk.ReferencedColumns,
+			},
+			SQL: sql,
+		})
+	}
+	return manual(b.missing("foreign key " + name))
+}
+
+func (b *builder) invertConvertCharset(charset string) Step {
+	if b.tm == nil {
+		return manual(b.missing("character set"))
+	}
+	var oldCharset, oldCollation string
+	for _, col := range b.tm.Columns {
+		if col.CharacterSet == "" {
+			continue
+		}
+		if oldCharset != "" && !strings.EqualFold(col.CharacterSet, oldCharset) {
+			return manual("columns use several character sets — convert them back column by column")
+		}
+		oldCharset, oldCollation = col.CharacterSet, col.Collation
+	}
+	if oldCharset == "" {
+		return manual(b.missing("character set"))
+	}
+	sql := "CONVERT TO CHARACTER SET " + oldCharset
+	if oldCollation != "" {
+		sql += " COLLATE " + oldCollation
+	}
+	inverse := meta.AlterAction{Type: meta.ActionConvertCharset, Detail: meta.ActionDetail{Charset: oldCharset}, SQL: sql}
+	if isUnicode(oldCharset) && !isUnicode(charset) {
+		return Step{
+			Status:  StatusLossy,
+			Reason:  fmt.Sprintf("%s → %s replaces characters that %s cannot store", oldCharset, strings.ToLower(charset), strings.ToLower(charset)),
+			inverse: []meta.AlterAction{inverse},
+		}
+	}
+	return reversible("", inverse)
+}
+
+// restoreIndex は変更前のインデックス定義を作り直すアクションを返す。
+// 同じ文で既に復元したインデックスは空を返す。DROP COLUMN でカラムが除かれたインデックスは削除してから作り直す。
+func (b *builder) restoreIndex(idx meta.IndexMeta) []meta.AlterAction {
+	key := strings.ToLower(idx.Name)
+	if b.restoredIndexes[key] {
+		return nil
+	}
+	b.restoredIndexes[key] = true
+
+	var actions []meta.AlterAction
+	if b.indexSurvives(idx) {
+		if idx.IsPrimary {
+			actions = append(actions, meta.AlterAction{Type: meta.ActionDropPrimaryKey, SQL: "DROP PRIMARY KEY"})
+		} else {
+			actions = append(actions, dropIndex(idx.Name))
+		}
+	}
+	return append(actions, addIndex(idx))
+}
+
+// indexSurvives は変更後もインデックスが（カラムが減った状態で）残っているかを返す。
+func (b *builder) indexSurvives(idx meta.IndexMeta) bool {
+	remaining := len(idx.Columns)
+	for _, a := range b.op.Actions {
+		switch a.Type {
+		case meta.ActionDropIndex:
+			if strings.EqualFold(a.Detail.IndexName, idx.Name) {
+				return false
+			}
+		case meta.ActionDropPrimaryKey:
+			if idx.IsPrimary {
+				return false
+			}
+		case meta.ActionDropColumn:
+			if containsFold(idx.Columns, a.Detail.ColumnName) {
+				remaining--
+			}
+		}
+	}
+	return remaining > 0
+}
+
+// addOrder は逆アクションの並び順のキーを返す。追加するカラムを元の位置順に先頭に置く。
+func (b *builder) addOrder(a meta.AlterAction) int {
+	if a.Type != meta.ActionAddColumn {
+		return math.MaxInt
+	}
+	return b.addedColumns[strings.ToLower(a.Detail.ColumnName)]
+}
+
+func (b *builder) findColumn(name string) (*meta.ColumnMeta, int) {
+	if b.tm == nil {
+		return nil, -1
+	}
+	for i := range b.tm.Columns {
+		if strings.EqualFold(b.tm.Columns[i].Name, name) {
+			return &b.tm.Columns[i], i
+		}
+	}
+	return nil, -1
+}
+
+// position は変更前の位置 i にカラムを戻す位置指定を返す。
+func (b *builder) position(i int) string {
+	if i <= 0 {
+		return "FIRST"
+	}
+	return "AFTER " + b.tm.Columns[i-1].Name
+}
+
+func (b *builder) findIndex(name string) *meta.IndexMeta {
+	if b.tm == nil {
+		return nil
+	}
+	for i := range b.tm.Indexes {
+		if strings.EqualFold(b.tm.Indexes[i].Name, name) {
+			return &b.tm.Indexes[i]
+		}
+	}
+	return nil
+}
+
+func (b *builder) primaryKey() *meta.IndexMeta {
+	if b.tm == nil {
+		return nil
+	}
+	for i := range b.tm.Indexes {
+		if b.tm.Indexes[i].IsPrimary {
+			return &b.tm.Indexes[i]
+		}
+	}
+	return nil
+}
+
+// missing は元の定義が見つからない理由を返す。
+func (b *builder) missing(what string) string {
+	if b.tm == nil {
+		return "no table metadata — previous " + what + " is unknown"
+	}
+	return what + " is not in the table metadata"
+}
+
+func reversible(reason string, inverse ...meta.AlterAction) Step {
+	return Step{Status: StatusReversible, Reason: reason, inverse: inverse}
+}
+
+// expressionIndex は式を含むため定義を復元できないインデックスの理由を返す。
+func expressionIndex(idx meta.IndexMeta) string {
+	return "index " + idx.Name + " has an expression key part that is not in the table metadata"
+}
+
+func manual(reason string) Step {
+	return Step{Status: StatusManual, Reason: reason}
+}
+
+func irreversible(reason string) Step {
+	return Step{Status: StatusIrreversible, Reason: reason}
+}
+
+// clauses は逆アクションの句を連結して返す。
+func (s Step) clauses() string {
+	parts := make([]string, 0, len(s.inverse))
+	for _, a := range s.inverse {
+		parts = append(parts, a.SQL)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func dropColumn(name string) meta.AlterAction {
+	return meta.AlterAction{
+		Type:   meta.ActionDropColumn,
+		Detail: meta.ActionDetail{ColumnName: name},
+		SQL:    "DROP COLUMN " + quoteIdent(name),
+	}
+}
+
+func dropIndex(name string) meta.AlterAction {
+	return meta.AlterAction{
+		Type:   meta.ActionDropIndex,
+		Detail: meta.ActionDetail{IndexName: name},
+		SQL:    "DROP INDEX " + quoteIdent(name),
+	}
+}
+
+// addIndex は IndexMeta からインデックスを作成するアクションを返す。
+func addIndex(idx meta.IndexMeta) meta.AlterAction {
+	cols := quoteList(idx.Columns)
+	detail := meta.ActionDetail{IndexName: idx.Name, IndexColumns: idx.Columns}
+	switch {
+	case idx.IsPrimary:
+		return meta.AlterAction{Type: meta.ActionAddPrimaryKey, Detail: meta.ActionDetail{IndexColumns: idx.Columns}, SQL: "ADD PRIMARY KEY (" + cols + ")"}
+	case strings.EqualFold(idx.IndexType, "FULLTEXT"):
+		return meta.AlterAction{Type: meta.ActionAddFulltextIndex, Detail: detail, SQL: "ADD FULLTEXT INDEX " + quoteIdent(idx.Name) + " (" + cols + ")"}
+	case strings.EqualFold(idx.IndexType, "SPATIAL"):
+		return meta.AlterAction{Type: meta.ActionAddSpatialIndex, Detail: detail, SQL: "ADD SPATIAL INDEX " + quoteIdent(idx.Name) + " (" + cols + ")"}
+	case idx.IsUnique:
+		return meta.AlterAction{Type: meta.ActionAddUniqueIndex, Detail: detail, SQL: "ADD UNIQUE INDEX " + quoteIdent(idx.Name) + " (" + cols + ")"}
+	default:
+		return meta.AlterAction{Type: meta.ActionAddIndex, Detail: detail, SQL: "ADD INDEX " + quoteIdent(idx.Name) + " (" + cols + ")"}
+	}
+}
+
+func tableRef(op meta.AlterOperation) string {
+	if op.TableRef != "" {
+		return op.TableRef
+	}
+	if op.Schema != "" {
+		return quoteIdent(op.Schema) + "." + quoteIdent(op.Table)
+	}
+	return quoteIdent(op.Table)
+}
+
+func quoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func quoteList(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, n := range names {
+		quoted = append(quoted, quoteIdent(n))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func positionClause(position string) string {
+	switch {
+	case position == "":
+		return ""
+	case strings.EqualFold(position, "FIRST"):
+		return " FIRST"
+	default:
+		return " AFTER " + quoteIdent(strings.TrimSpace(position[len("AFTER "):]))
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDefaultRefAction(action string) bool {
+	return strings.EqualFold(action, "RESTRICT") || strings.EqualFold(action, "NO ACTION")
+}
+
+func isUnicode(charset string) bool {
+	return strings.HasPrefix(strings.ToLower(charset), "utf")
+}
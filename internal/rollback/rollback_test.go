@@ -0,0 +1,221 @@
+package rollback
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/predictor"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func usersMeta() *meta.TableMeta {
+	return &meta.TableMeta{
+		Schema: "mydb",
+		Table:  "users",
+		Engine: "InnoDB",
+		Columns: []meta.ColumnMeta{
+			{Name: "id", ColumnType: "bigint unsigned", Extra: "auto_increment"},
+			{Name: "email", ColumnType: "varchar(255)", CharacterSet: "utf8mb4", Collation: "utf8mb4_0900_ai_ci"},
+			{Name: "status", ColumnType: "varchar(20)", IsNullable: true, DefaultValue: "active", CharacterSet: "utf8mb4", Collation: "utf8mb4_0900_ai_ci"},
+			{Name: "score", ColumnType: "int", DefaultValue: "0"},
+		},
+		Indexes: []meta.IndexMeta{
+			{Name: "PRIMARY", Columns: []string{"id"}, IsUnique: true, IsPrimary: true},
+			{Name: "uk_email", Columns: []string{"email"}, IsUnique: true},
+			{Name: "idx_status_score", Columns: []string{"status", "score"}},
+		},
+		ForeignKeys: []meta.ForeignKeyMeta{{
+			ConstraintName: "fk_users_team", SourceSchema: "mydb", SourceTable: "users", SourceColumns: []string{"team_id"},
+			ReferencedSchema: "mydb", ReferencedTable: "teams", ReferencedColumns: []string{"id"}, OnDelete: "CASCADE", OnUpdate: "RESTRICT",
+		}},
+	}
+}
+
+func usersOp(actions ...meta.AlterAction) meta.AlterOperation {
+	return meta.AlterOperation{Schema: "mydb", Table: "users", Actions: actions}
+}
+
+func TestBuildAddColumn(t *testing.T) {
+	plan := Build(usersOp(meta.AlterAction{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "nickname", ColumnType: "VARCHAR(50)"}}), usersMeta())
+	if plan.SQL != "ALTER TABLE `mydb`.`users` DROP COLUMN `nickname`;" {
+		t.Errorf("ADD COLUMN は DROP COLUMN で戻すこと: got %s", plan.SQL)
+	}
+	if plan.Irreversible || plan.Steps[0].Status != StatusReversible {
+		t.Errorf("ADD COLUMN は元に戻せること: got %+v", plan.Steps[0])
+	}
+}
+
+func TestBuildDropColumn(t *testing.T) {
+	plan := Build(usersOp(meta.AlterAction{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}}), usersMeta())
+	want := "ALTER TABLE `mydb`.`users` ADD COLUMN `status` VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL DEFAULT 'active' AFTER `email`, " +
+		"DROP INDEX `idx_status_score`, ADD INDEX `idx_status_score` (`status`, `score`);"
+	if plan.SQL != want {
+		t.Errorf("カラム定義と縮小されたインデックスを復元すること:\ngot  %s\nwant %s", plan.SQL, want)
+	}
+	if !plan.Irreversible || plan.Steps[0].Status != StatusLossy {
+		t.Errorf("DROP COLUMN はデータが失われること: got %+v", plan.Steps[0])
+	}
+}
+
+func TestBuildDropColumnsKeepsOrder(t *testing.T) {
+	plan := Build(usersOp(
+		meta.AlterAction{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "email"}},
+		meta.AlterAction{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}},
+	), usersMeta())
+	first := strings.Index(plan.SQL, "ADD COLUMN `email`")
+	second := strings.Index(plan.SQL, "ADD COLUMN `status`")
+	if first < 0 || second < 0 || first > second {
+		t.Errorf("AFTER で参照できるよう元の順にカラムを追加すること: got %s", plan.SQL)
+	}
+	if !strings.Contains(plan.SQL, "ADD UNIQUE INDEX `uk_email` (`email`)") || strings.Contains(plan.SQL, "DROP INDEX `uk_email`") {
+		t.Errorf("カラムとともに削除された単一カラムのインデックスは追加のみで戻すこと: got %s", plan.SQL)
+	}
+}
+
+func TestBuildModifyColumn(t *testing.T) {
+	tests := []struct {
+		name    string
+		detail  meta.ActionDetail
+		status  Status
+		wantSQL string
+	}{
+		{"拡張", meta.ActionDetail{ColumnName: "email", ColumnType: "VARCHAR(500)", IsNullable: boolPtr(false)}, StatusReversible,
+			"MODIFY COLUMN `email` VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL"},
+		{"縮小", meta.ActionDetail{ColumnName: "email", ColumnType: "VARCHAR(100)", IsNullable: boolPtr(false)}, StatusLossy,
+			"MODIFY COLUMN `email` VARCHAR(255)"},
+		{"位置指定", meta.ActionDetail{ColumnName: "score", ColumnType: "BIGINT", Position: "FIRST"}, StatusReversible,
+			"MODIFY COLUMN `score` INT NOT NULL DEFAULT 0 AFTER `status`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := Build(usersOp(meta.AlterAction{Type: meta.ActionModifyColumn, Detail: tt.detail}), usersMeta())
+			if plan.Steps[0].Status != tt.status || !strings.Contains(plan.SQL, tt.wantSQL) {
+				t.Errorf("got %s %s", plan.Steps[0].Status, plan.SQL)
+			}
+		})
+	}
+}
+
+func TestBuildChangeColumn(t *testing.T) {
+	plan := Build(usersOp(meta.AlterAction{Type: meta.ActionChangeColumn, Detail: meta.ActionDetail{
+		ColumnName: "mail", OldColumnName: "email", ColumnType: "VARCHAR(255)", IsNullable: boolPtr(false),
+	}}), usersMeta())
+	if !strings.Contains(plan.SQL, "CHANGE COLUMN `mail` `email` VARCHAR(255)") {
+		t.Errorf("CHANGE COLUMN は元の名前と定義に戻すこと: got %s", plan.SQL)
+	}
+}
+
+func TestBuildDefault(t *testing.T) {
+	tm := usersMeta()
+	tm.Columns = append(tm.Columns, meta.ColumnMeta{Name: "memo", ColumnType: "varchar(10)", HasDefault: true})
+	tests := []struct {
+		name    string
+		action  meta.AlterAction
+		status  Status
+		wantSQL string
+	}{
+		{"SET DEFAULT", meta.AlterAction{Type: meta.ActionSetDefault, Detail: meta.ActionDetail{ColumnName: "status", DefaultValue: "'x'"}},
+			StatusReversible, "ALTER COLUMN `status` SET DEFAULT 'active'"},
+		{"空文字のデフォルトへ戻す", meta.AlterAction{Type: meta.ActionDropDefault, Detail: meta.ActionDetail{ColumnName: "memo"}},
+			StatusReversible, "ALTER COLUMN `memo` SET DEFAULT ''"},
+		{"デフォルトなしへ戻す", meta.AlterAction{Type: meta.ActionSetDefault, Detail: meta.ActionDetail{ColumnName: "email", DefaultValue: "'x'"}},
+			StatusReversible, "ALTER COLUMN `email` DROP DEFAULT"},
+		{"デフォルトなしの DROP DEFAULT", meta.AlterAction{Type: meta.ActionDropDefault, Detail: meta.ActionDetail{ColumnName: "email"}},
+			StatusNone, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := Build(usersOp(tt.action), tm)
+			if plan.Steps[0].Status != tt.status || !strings.Contains(plan.SQL, tt.wantSQL) {
+				t.Errorf("got %s %s", plan.Steps[0].Status, plan.SQL)
+			}
+		})
+	}
+}
+
+func TestBuildIndexesAndKeys(t *testing.T) {
+	plan := Build(usersOp(
+		meta.AlterAction{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "uk_email"}},
+		meta.AlterAction{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexColumns: []string{"score"}}},
+		meta.AlterAction{Type: meta.ActionRenameIndex, Detail: meta.ActionDetail{OldIndexName: "idx_status_score", IndexName: "idx_ss"}},
+		meta.AlterAction{Type: meta.ActionDropForeignKey, Detail: meta.ActionDetail{ConstraintName: "fk_users_team"}},
+	), usersMeta())
+	for _, want := range []string{
+		"ADD CONSTRAINT `fk_users_team` FOREIGN KEY (`team_id`) REFERENCES `teams` (`id`) ON DELETE CASCADE,",
+		"RENAME INDEX `idx_ss` TO `idx_status_score`",
+		"DROP INDEX `score`",
+		"ADD UNIQUE INDEX `uk_email` (`email`);",
+	} {
+		if !strings.Contains(plan.SQL, want) {
+			t.Errorf("%s を含むこと: got %s", want, plan.SQL)
+		}
+	}
+	if plan.Steps[2].Reason == "" {
+		t.Errorf("名前のないインデックスは推定した名前を理由に記録すること")
+	}
+}
+
+func TestBuildRenameTable(t *testing.T) {
+	plan := Build(usersOp(
+		meta.AlterAction{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "nickname", ColumnType: "VARCHAR(50)"}},
+		meta.AlterAction{Type: meta.ActionRenameTable, Detail: meta.ActionDetail{ColumnName: "members"}},
+	), usersMeta())
+	if plan.SQL != "ALTER TABLE `mydb`.`members` RENAME TO `users`, DROP COLUMN `nickname`;" {
+		t.Errorf("変更後のテーブル名に対して元に戻すこと: got %s", plan.SQL)
+	}
+}
+
+func TestBuildIrreversibleAndManual(t *testing.T) {
+	plan := Build(usersOp(
+		meta.AlterAction{Type: meta.ActionDropPartition},
+		meta.AlterAction{Type: meta.ActionChangeRowFormat, Detail: meta.ActionDetail{RowFormat: "COMPRESSED"}},
+		meta.AlterAction{Type: meta.ActionForceRebuild},
+	), usersMeta())
+	statuses := []Status{plan.Steps[0].Status, plan.Steps[1].Status, plan.Steps[2].Status}
+	if statuses[0] != StatusNone || statuses[1] != StatusManual || statuses[2] != StatusIrreversible {
+		t.Errorf("FORCE は不要、ROW_FORMAT は手動、DROP PARTITION は元に戻せないこと: got %v", statuses)
+	}
+	if !plan.Irreversible || plan.SQL != "" {
+		t.Errorf("元に戻す句がない場合は SQL を空にすること: got %q", plan.SQL)
+	}
+}
+
+func TestBuildExpressionIndex(t *testing.T) {
+	tm := usersMeta()
+	tm.Indexes = append(tm.Indexes, meta.IndexMeta{Name: "idx_lower_email", Columns: []string{"email"}, HasExpression: true})
+	plan := Build(usersOp(meta.AlterAction{Type: meta.ActionDropIndex, Detail: meta.ActionDetail{IndexName: "idx_lower_email"}}), tm)
+	if plan.Steps[0].Status != StatusManual || plan.SQL != "" {
+		t.Errorf("式を含むインデックスの DROP INDEX は手動で戻すこと: got %+v %s", plan.Steps[0], plan.SQL)
+	}
+	plan = Build(usersOp(meta.AlterAction{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "email"}}), tm)
+	if plan.Steps[0].Status != StatusManual {
+		t.Errorf("式を含むインデックスのカラムの DROP COLUMN は手動で戻すこと: got %+v", plan.Steps[0])
+	}
+}
+
+func TestBuildWithoutMeta(t *testing.T) {
+	plan := Build(usersOp(meta.AlterAction{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}}), nil)
+	if plan.Steps[0].Status != StatusManual || !strings.Contains(plan.Steps[0].Reason, "no table metadata") {
+		t.Errorf("メタデータがない場合は手動にすること: got %+v", plan.Steps[0])
+	}
+}
+
+func TestPlanPredict(t *testing.T) {
+	before := usersMeta()
+	after := usersMeta()
+	after.Columns = append(after.Columns[:2], after.Columns[3])
+
+	plan := Build(usersOp(meta.AlterAction{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "status"}}), before)
+	plan.Predict(predictor.New(predictor.WithMySQLVersion("8.0.35")), after)
+	if len(plan.Predictions) != 3 || plan.Verdict == nil {
+		t.Fatalf("元に戻す文の全アクションを予測すること: got %d", len(plan.Predictions))
+	}
+	if plan.Predictions[0].ActionType != meta.ActionAddColumn || plan.Predictions[0].Algorithm != meta.AlgorithmInstant {
+		t.Errorf("AFTER 指定の ADD COLUMN は INSTANT と予測されること: got %s %s", plan.Predictions[0].ActionType, plan.Predictions[0].Algorithm)
+	}
+	if plan.Verdict.Algorithm != meta.AlgorithmInplace {
+		t.Errorf("インデックスの作り直しを含むため文全体は INPLACE になること: got %s", plan.Verdict.Algorithm)
+	}
+}
@@ -0,0 +1,400 @@
+package simulator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// actionAppliers はテーブル単体で完結するアクションの適用関数。
+// FKの追加・削除とテーブル名変更は他テーブルにも影響するため Simulator.Apply で扱う。
+var actionAppliers = map[meta.AlterActionType]func(*meta.TableMeta, meta.AlterAction){
+	meta.ActionAddColumn:          applyAddColumn,
+	meta.ActionDropColumn:         applyDropColumn,
+	meta.ActionModifyColumn:       applyModifyColumn,
+	meta.ActionChangeColumn:       applyModifyColumn,
+	meta.ActionRenameColumn:       applyRenameColumn,
+	meta.ActionSetDefault:         applySetDefault,
+	meta.ActionDropDefault:        applyDropDefault,
+	meta.ActionAddIndex:           applyAddIndex,
+	meta.ActionAddUniqueIndex:     applyAddIndex,
+	meta.ActionAddFulltextIndex:   applyAddIndex,
+	meta.ActionAddSpatialIndex:    applyAddIndex,
+	meta.ActionDropIndex:          applyDropIndex,
+	meta.ActionRenameIndex:        applyRenameIndex,
+	meta.ActionAddPrimaryKey:      applyAddPrimaryKey,
+	meta.ActionDropPrimaryKey:     applyDropPrimaryKey,
+	meta.ActionChangeEngine:       applyChangeEngine,
+	meta.ActionConvertCharset:     applyConvertCharset,
+	meta.ActionPartitionBy:        applyPartitionBy,
+	meta.ActionRemovePartitioning: applyRemovePartitioning,
+}
+
+func applyAddColumn(tm *meta.TableMeta, a meta.AlterAction) {
+	col := columnFromDetail(a.Detail)
+	tm.Columns = insertColumn(tm.Columns, col, a.Detail.Position)
+}
+
+func applyDropColumn(tm *meta.TableMeta, a meta.AlterAction) {
+	name := a.Detail.ColumnName
+	i := columnIndex(tm, name)
+	if i < 0 {
+		return
+	}
+	tm.Columns = append(tm.Columns[:i], tm.Columns[i+1:]...)
+
+	// 削除カラムを含むインデックスからカラムを除き、空になったインデックスは削除する（式を含むインデックスは残す）
+	indexes := tm.Indexes[:0]
+	for _, idx := range tm.Indexes {
+		idx.Columns = removeString(idx.Columns, name)
+		if len(idx.Columns) > 0 || idx.HasExpression {
+			indexes = append(indexes, idx)
+		}
+	}
+	tm.Indexes = indexes
+}
+
+// applyModifyColumn は MODIFY/CHANGE COLUMN を適用する。
+// MySQLと同様にカラム定義全体を置き換えるが、文字セット・照合順序は引き継ぐ。
+func applyModifyColumn(tm *meta.TableMeta, a meta.AlterAction) {
+	oldName := a.Detail.OldColumnName
+	if oldName == "" {
+		oldName = a.Detail.ColumnName
+	}
+	i := columnIndex(tm, oldName)
+	if i < 0 {
+		return
+	}
+	old := tm.Columns[i]
+	col := columnFromDetail(a.Detail)
+	col.ColumnKey = old.ColumnKey
+	col.CharacterSet = old.CharacterSet
+	col.Collation = old.Collation
+	if !strings.EqualFold(old.Name, col.Name) {
+		renameColumnReferences(tm, old.Name, col.Name)
+	}
+
+	if a.Detail.Position == "" {
+		tm.Columns[i] = col
+		return
+	}
+	tm.Columns = append(tm.Columns[:i], tm.Columns[i+1:]...)
+	tm.Columns = insertColumn(tm.Columns, col, a.Detail.Position)
+}
+
+func applyRenameColumn(tm *meta.TableMeta, a meta.AlterAction) {
+	i := columnIndex(tm, a.Detail.OldColumnName)
+	if i < 0 {
+		return
+	}
+	tm.Columns[i].Name = a.Detail.ColumnName
+	renameColumnReferences(tm, a.Detail.OldColumnName, a.Detail.ColumnName)
+}
+
+func applySetDefault(tm *meta.TableMeta, a meta.AlterAction) {
+	if i := columnIndex(tm, a.Detail.ColumnName); i >= 0 {
+		tm.Columns[i].DefaultValue, tm.Columns[i].HasDefault = columnDefault(a.Detail.DefaultValue)
+	}
+}
+
+func applyDropDefault(tm *meta.TableMeta, a meta.AlterAction) {
+	if i := columnIndex(tm, a.Detail.ColumnName); i >= 0 {
+		tm.Columns[i].DefaultValue = ""
+		tm.Columns[i].HasDefault = false
+	}
+}
+
+func applyAddIndex(tm *meta.TableMeta, a meta.AlterAction) {
+	idx := meta.IndexMeta{
+		Name:      a.Detail.IndexName,
+		Columns:   append([]string(nil), a.Detail.IndexColumns...),
+		IsUnique:  a.Type == meta.ActionAddUniqueIndex,
+		IndexType: "BTREE",
+	}
+	switch a.Type {
+	case meta.ActionAddFulltextIndex:
+		idx.IndexType = "FULLTEXT"
+	case meta.ActionAddSpatialIndex:
+		idx.IndexType = "SPATIAL"
+	}
+	addIndex(tm, idx)
+}
+
+func applyDropIndex(tm *meta.TableMeta, a meta.AlterAction) {
+	tm.Indexes = removeIndex(tm.Indexes, func(idx meta.IndexMeta) bool {
+		return strings.EqualFold(idx.Name, a.Detail.IndexName)
+	})
+}
+
+func applyRenameIndex(tm *meta.TableMeta, a meta.AlterAction) {
+	for i := range tm.Indexes {
+		if strings.EqualFold(tm.Indexes[i].Name, a.Detail.OldIndexName) {
+			tm.Indexes[i].Name = a.Detail.IndexName
+		}
+	}
+}
+
+func applyAddPrimaryKey(tm *meta.TableMeta, a meta.AlterAction) {
+	applyDropPrimaryKey(tm, a)
+	tm.Indexes = append([]meta.IndexMeta{{
+		Name:      "PRIMARY",
+		Columns:   append([]string(nil), a.Detail.IndexColumns...),
+		IsUnique:  true,
+		IsPrimary: true,
+		IndexType: "BTREE",
+	}}, tm.Indexes...)
+	// PRIMARY KEY のカラムは暗黙的に NOT NULL となる
+	for _, name := range a.Detail.IndexColumns {
+		if i := columnIndex(tm, name); i >= 0 {
+			tm.Columns[i].IsNullable = false
+			tm.Columns[i].ColumnKey = "PRI"
+		}
+	}
+}
+
+func applyDropPrimaryKey(tm *meta.TableMeta, _ meta.AlterAction) {
+	for _, idx := range tm.Indexes {
+		if !idx.IsPrimary {
+			continue
+		}
+		for _, name := range idx.Columns {
+			if i := columnIndex(tm, name); i >= 0 && tm.Columns[i].ColumnKey == "PRI" {
+				tm.Columns[i].ColumnKey = ""
+			}
+		}
+	}
+	tm.Indexes = removeIndex(tm.Indexes, func(idx meta.IndexMeta) bool { return idx.IsPrimary })
+}
+
+func applyChangeEngine(tm *meta.TableMeta, a meta.AlterAction) {
+	if a.Detail.Engine != "" {
+		tm.Engine = a.Detail.Engine
+	}
+}
+
+func applyConvertCharset(tm *meta.TableMeta, a meta.AlterAction) {
+	charset := strings.ToLower(a.Detail.Charset)
+	for i := range tm.Columns {
+		if tm.Columns[i].CharacterSet != "" {
+			tm.Columns[i].CharacterSet = charset
+			tm.Columns[i].Collation = ""
+		}
+	}
+}
+
+func applyPartitionBy(tm *meta.TableMeta, a meta.AlterAction) {
+	tm.IsPartitioned = true
+	tm.PartitionType = a.Detail.PartitionType
+}
+
+func applyRemovePartitioning(tm *meta.TableMeta, _ meta.AlterAction) {
+	tm.IsPartitioned = false
+	tm.PartitionType = ""
+}
+
+// addForeignKey はFKを追加し、追加したFKを返す。
+// 参照元カラムを先頭に持つインデックスがない場合はMySQLと同様にインデックスを暗黙作成する。
+func addForeignKey(tm *meta.TableMeta, a meta.AlterAction) meta.ForeignKeyMeta {
+	name := a.Detail.ConstraintName
+	if name == "" {
+		name = fmt.Sprintf("%s_ibfk_%d", tm.Table, len(tm.ForeignKeys)+1)
+	}
+	fk := meta.ForeignKeyMeta{
+		ConstraintName:    name,
+		SourceSchema:      tm.Schema,
+		SourceTable:       tm.Table,
+		SourceColumns:     append([]string(nil), a.Detail.IndexColumns...),
+		ReferencedSchema:  tm.Schema,
+		ReferencedTable:   a.Detail.RefTable,
+		ReferencedColumns: append([]string(nil), a.Detail.RefColumns...),
+	}
+	tm.ForeignKeys = append(tm.ForeignKeys, fk)
+
+	if !hasIndexPrefix(tm, fk.SourceColumns) {
+		addIndex(tm, meta.IndexMeta{Name: name, Columns: append([]string(nil), fk.SourceColumns...), IndexType: "BTREE"})
+	}
+	return fk
+}
+
+// dropForeignKey はFKを削除し、削除したFKを返す。
+func dropForeignKey(tm *meta.TableMeta, name string) (meta.ForeignKeyMeta, bool) {
+	for i, fk := range tm.ForeignKeys {
+		if strings.EqualFold(fk.ConstraintName, name) {
+			tm.ForeignKeys = append(tm.ForeignKeys[:i], tm.ForeignKeys[i+1:]...)
+			return fk, true
+		}
+	}
+	return meta.ForeignKeyMeta{}, false
+}
+
+func removeForeignKey(fks []meta.ForeignKeyMeta, schema, table, name string) []meta.ForeignKeyMeta {
+	out := fks[:0]
+	for _, fk := range fks {
+		if strings.EqualFold(fk.SourceSchema, schema) && strings.EqualFold(fk.SourceTable, table) &&
+			strings.EqualFold(fk.ConstraintName, name) {
+			continue
+		}
+		out = append(out, fk)
+	}
+	return out
+}
+
+// columnFromDetail はADD/MODIFY/CHANGE COLUMN の定義からカラムメタデータを作成する。
+func columnFromDetail(d meta.ActionDetail) meta.ColumnMeta {
+	colType := strings.ToLower(d.ColumnType)
+	col := meta.ColumnMeta{
+		Name:       d.ColumnName,
+		ColumnType: colType,
+		DataType:   colType,
+		IsNullable: d.IsNullable == nil || *d.IsNullable,
+	}
+	col.DefaultValue, col.HasDefault = columnDefault(d.DefaultValue)
+	if i := strings.IndexAny(colType, "( "); i >= 0 {
+		col.DataType = colType[:i]
+	}
+
+	var extras []string
+	if d.IsAutoIncrement {
+		extras = append(extras, "auto_increment")
+	}
+	if d.GeneratedType != "" {
+		extras = append(extras, d.GeneratedType+" GENERATED")
+	}
+	col.Extra = strings.Join(extras, " ")
+	return col
+}
+
+// columnDefault はALTER文の DEFAULT 式を information_schema.COLUMNS.COLUMN_DEFAULT に近い形式に変換する。
+// 文字列リテラルは文字セット指定と引用符を外し、関数呼び出しは括弧を省く。
+// DEFAULT がない場合と DEFAULT NULL は information_schema と同様にデフォルトなし（false）とする。
+func columnDefault(expr string) (string, bool) {
+	s := strings.TrimSpace(expr)
+	if s == "" || strings.EqualFold(s, "NULL") {
+		return "", false
+	}
+	if i := strings.IndexByte(s, '\''); strings.HasPrefix(s, "_") && i > 0 && !strings.ContainsAny(s[:i], " (") {
+		s = s[i:]
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.NewReplacer("''", "'", `\'`, "'", `\\`, `\`).Replace(s[1 : len(s)-1]), true
+	}
+	return strings.TrimSuffix(s, "()"), true
+}
+
+// insertColumn は position（"", "FIRST", "AFTER <col>"）に従ってカラムを挿入する。
+func insertColumn(cols []meta.ColumnMeta, col meta.ColumnMeta, position string) []meta.ColumnMeta {
+	at := len(cols)
+	switch {
+	case position == "FIRST":
+		at = 0
+	case strings.HasPrefix(position, "AFTER "):
+		after := strings.TrimPrefix(position, "AFTER ")
+		for i := range cols {
+			if strings.EqualFold(cols[i].Name, after) {
+				at = i + 1
+				break
+			}
+		}
+	}
+	cols = append(cols, meta.ColumnMeta{})
+	copy(cols[at+1:], cols[at:])
+	cols[at] = col
+	return cols
+}
+
+// renameColumnReferences はインデックスとFKに含まれるカラム名を変更する。
+func renameColumnReferences(tm *meta.TableMeta, oldName, newName string) {
+	for i := range tm.Indexes {
+		renameString(tm.Indexes[i].Columns, oldName, newName)
+	}
+	for i := range tm.ForeignKeys {
+		renameString(tm.ForeignKeys[i].SourceColumns, oldName, newName)
+	}
+	for i := range tm.ReferencedBy {
+		renameString(tm.ReferencedBy[i].ReferencedColumns, oldName, newName)
+	}
+}
+
+func renumberColumns(tm *meta.TableMeta) {
+	for i := range tm.Columns {
+		tm.Columns[i].OrdinalPos = i + 1
+	}
+}
+
+// addIndex はインデックスを追加する。名前がない場合はMySQLと同様に先頭カラム名から命名する。
+func addIndex(tm *meta.TableMeta, idx meta.IndexMeta) {
+	if idx.Name == "" && len(idx.Columns) > 0 {
+		idx.Name = idx.Columns[0]
+		for n := 2; indexExists(tm, idx.Name); n++ {
+			idx.Name = fmt.Sprintf("%s_%d", idx.Columns[0], n)
+		}
+	}
+	tm.Indexes = append(tm.Indexes, idx)
+}
+
+func indexExists(tm *meta.TableMeta, name string) bool {
+	for _, idx := range tm.Indexes {
+		if strings.EqualFold(idx.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasIndexPrefix は cols を先頭カラムに持つインデックスが存在するかを判定する。
+func hasIndexPrefix(tm *meta.TableMeta, cols []string) bool {
+	for _, idx := range tm.Indexes {
+		if len(idx.Columns) < len(cols) {
+			continue
+		}
+		match := true
+		for i, c := range cols {
+			if !strings.EqualFold(idx.Columns[i], c) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func removeIndex(indexes []meta.IndexMeta, drop func(meta.IndexMeta) bool) []meta.IndexMeta {
+	out := indexes[:0]
+	for _, idx := range indexes {
+		if !drop(idx) {
+			out = append(out, idx)
+		}
+	}
+	return out
+}
+
+func columnIndex(tm *meta.TableMeta, name string) int {
+	for i := range tm.Columns {
+		if strings.EqualFold(tm.Columns[i].Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func removeString(list []string, s string) []string {
+	out := list[:0]
+	for _, v := range list {
+		if !strings.EqualFold(v, s) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func renameString(list []string, oldName, newName string) {
+	for i := range list {
+		if strings.EqualFold(list[i], oldName) {
+			list[i] = newName
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package simulator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+)
+
+// Simulator はALTER操作を順に適用したスキーマをメモリ上で保持する。
+// meta.Collector を実装し、適用済みのテーブルは変更後の定義を返す。
+// 未変更のテーブルは元のコレクターに問い合わせる。
+type Simulator struct {
+	base    meta.Collector
+	tables  map[string]*meta.TableMeta
+	removed map[string]string // RENAME TABLE 前の名前 → 変更後の名前
+}
+
+// New は base を元にした Simulator を作成する。
+func New(base meta.Collector) *Simulator {
+	return &Simulator{
+		base:    base,
+		tables:  make(map[string]*meta.TableMeta),
+		removed: make(map[string]string),
+	}
+}
+
+// GetMySQLVersion はMySQLバージョンを返す。
+func (s *Simulator) GetMySQLVersion() string {
+	return s.base.GetMySQLVersion()
+}
+
+// GetTableMeta は変更適用後のテーブルメタデータを返す。
+// schema が空の場合は適用済みテーブルをテーブル名のみで検索する。
+func (s *Simulator) GetTableMeta(schema, table string) (*meta.TableMeta, error) {
+	if tm := s.lookup(schema, table); tm != nil {
+		return cloneTable(tm), nil
+	}
+	if renamed, ok := s.renamedTo(schema, table); ok {
+		return nil, fmt.Errorf("table %s was renamed to %s by an earlier statement", qualify(schema, table), renamed)
+	}
+	return s.base.GetTableMeta(schema, table)
+}
+
+func (s *Simulator) lookup(schema, table string) *meta.TableMeta {
+	if schema != "" {
+		if tm := s.tables[tableKey(schema, table)]; tm != nil {
+			return tm
+		}
+		// スキーマ未記載のメタデータから作られたテーブルにも対応する
+		return s.tables[tableKey("", table)]
+	}
+	var found *meta.TableMeta
+	for _, tm := range s.tables {
+		if strings.EqualFold(tm.Table, table) {
+			if found != nil {
+				// 複数スキーマに同名テーブルがある場合は元のコレクターに委ねる
+				return nil
+			}
+			found = tm
+		}
+	}
+	return found
+}
+
+func (s *Simulator) renamedTo(schema, table string) (string, bool) {
+	suffix := "." + strings.ToLower(table)
+	for key, renamed := range s.removed {
+		if key == tableKey(schema, table) || (schema == "" && strings.HasSuffix(key, suffix)) {
+			return renamed, true
+		}
+	}
+	return "", false
+}
+
+// Apply は tm に op の全アクションを適用した結果を以降の問い合わせに反映する。
+// tm は op 実行前のテーブル定義で、nil の場合（メタデータ取得不可）は何もしない。
+// algorithm と rebuild は op 全体として予測された実行方法で、INSTANT の行バージョン数の更新に使う。
+func (s *Simulator) Apply(tm *meta.TableMeta, op meta.AlterOperation, algorithm meta.Algorithm, rebuild bool) {
+	if tm == nil {
+		return
+	}
+	evolved := cloneTable(tm)
+	fillForeignKeyDefaults(evolved)
+	s.tables[tableKey(evolved.Schema, evolved.Table)] = evolved
+
+	for _, action := range op.Actions {
+		switch action.Type {
+		case meta.ActionAddForeignKey:
+			fk := addForeignKey(evolved, action)
+			s.updateTable(fk.ReferencedSchema, fk.ReferencedTable, func(parent *meta.TableMeta) {
+				parent.ReferencedBy = append(parent.ReferencedBy, fk)
+			})
+		case meta.ActionDropForeignKey:
+			fk, ok := dropForeignKey(evolved, action.Detail.ConstraintName)
+			if ok {
+				s.updateTable(fk.ReferencedSchema, fk.ReferencedTable, func(parent *meta.TableMeta) {
+					parent.ReferencedBy = removeForeignKey(parent.ReferencedBy, fk.SourceSchema, fk.SourceTable, fk.ConstraintName)
+				})
+			}
+		case meta.ActionRenameTable:
+			s.renameTable(evolved, action.Detail.ColumnName)
+		default:
+			if apply, ok := actionAppliers[action.Type]; ok {
+				apply(evolved, action)
+			}
+		}
+	}
+	renumberColumns(evolved)
+	updateRowVersions(evolved, op, algorithm, rebuild)
+}
+
+// updateRowVersions は INSTANT ADD/DROP COLUMN による行バージョン数の増加を反映する。
+// 1つのALTER文は含まれるカラム数に関わらず1バージョンを消費し、INPLACE/COPY の再構築で0に戻る。
+func updateRowVersions(tm *meta.TableMeta, op meta.AlterOperation, algorithm meta.Algorithm, rebuild bool) {
+	if algorithm != meta.AlgorithmInstant {
+		if rebuild {
+			tm.TotalRowVersions = 0
+		}
+		return
+	}
+	for _, action := range op.Actions {
+		if (action.Type == meta.ActionAddColumn && action.Detail.GeneratedType != "VIRTUAL") ||
+			action.Type == meta.ActionDropColumn {
+			tm.TotalRowVersions++
+			return
+		}
+	}
+}
+
+// updateTable は指定テーブルの定義を変更し、適用済みテーブルとして保持する。
+// メタデータが取得できないテーブルは無視する。
+func (s *Simulator) updateTable(schema, table string, fn func(tm *meta.TableMeta)) {
+	tm := s.lookup(schema, table)
+	if tm == nil {
+		base, err := s.base.GetTableMeta(schema, table)
+		if err != nil || base == nil {
+			return
+		}
+		tm = cloneTable(base)
+		s.tables[tableKey(tm.Schema, tm.Table)] = tm
+	}
+	fn(tm)
+}
+
+// renameTable はテーブル名を変更し、FKで関連するテーブルの参照も更新する。
+func (s *Simulator) renameTable(tm *meta.TableMeta, newName string) {
+	if newName == "" || strings.EqualFold(newName, tm.Table) {
+		return
+	}
+	oldName := tm.Table
+	oldKey := tableKey(tm.Schema, oldName)
+	tm.Table = newName
+	delete(s.tables, oldKey)
+	s.tables[tableKey(tm.Schema, newName)] = tm
+	s.removed[oldKey] = qualify(tm.Schema, newName)
+
+	for i := range tm.ForeignKeys {
+		tm.ForeignKeys[i].SourceTable = newName
+		fk := tm.ForeignKeys[i]
+		s.updateTable(fk.ReferencedSchema, fk.ReferencedTable, func(parent *meta.TableMeta) {
+			for j := range parent.ReferencedBy {
+				ref := &parent.ReferencedBy[j]
+				if strings.EqualFold(ref.SourceTable, oldName) && strings.EqualFold(ref.ConstraintName, fk.ConstraintName) {
+					ref.SourceTable = newName
+				}
+			}
+		})
+	}
+	for i := range tm.ReferencedBy {
+		tm.ReferencedBy[i].ReferencedTable = newName
+		fk := tm.ReferencedBy[i]
+		s.updateTable(fk.SourceSchema, fk.SourceTable, func(child *meta.TableMeta) {
+			for j := range child.ForeignKeys {
+				ref := &child.ForeignKeys[j]
+				if strings.EqualFold(ref.ReferencedTable, oldName) && strings.EqualFold(ref.ConstraintName, fk.ConstraintName) {
+					ref.ReferencedTable = newName
+				}
+			}
+		})
+	}
+}
+
+// fillForeignKeyDefaults はFKの省略されたスキーマ・テーブル名を補完する。
+// メタデータファイルでは参照元・参照先のスキーマが省略されることがある。
+func fillForeignKeyDefaults(tm *meta.TableMeta) {
+	for i := range tm.ForeignKeys {
+		fk := &tm.ForeignKeys[i]
+		if fk.SourceSchema == "" {
+			fk.SourceSchema = tm.Schema
+		}
+		if fk.SourceTable == "" {
+			fk.SourceTable = tm.Table
+		}
+		if fk.ReferencedSchema == "" {
+			fk.ReferencedSchema = fk.SourceSchema
+		}
+	}
+}
+
+// cloneTable はテーブルメタデータのディープコピーを返す。
+func cloneTable(tm *meta.TableMeta) *meta.TableMeta {
+	c := *tm
+	c.Columns = append([]meta.ColumnMeta(nil), tm.Columns...)
+	c.Indexes = make([]meta.IndexMeta, len(tm.Indexes))
+	for i, idx := range tm.Indexes {
+		idx.Columns = append([]string(nil), idx.Columns...)
+		c.Indexes[i] = idx
+	}
+	c.ForeignKeys = cloneForeignKeys(tm.ForeignKeys)
+	c.ReferencedBy = cloneForeignKeys(tm.ReferencedBy)
+	return &c
+}
+
+func cloneForeignKeys(fks []meta.ForeignKeyMeta) []meta.ForeignKeyMeta {
+	if fks == nil {
+		return nil
+	}
+	out := make([]meta.ForeignKeyMeta, len(fks))
+	for i, fk := range fks {
+		fk.SourceColumns = append([]string(nil), fk.SourceColumns...)
+		fk.ReferencedColumns = append([]string(nil), fk.ReferencedColumns...)
+		out[i] = fk
+	}
+	return out
+}
+
+func tableKey(schema, table string) string {
+	return strings.ToLower(schema) + "." + strings.ToLower(table)
+}
+
+func qualify(schema, table string) string {
+	if schema == "" {
+		return table
+	}
+	return schema + "." + table
+}
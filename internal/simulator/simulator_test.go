@@ -0,0 +1,316 @@
+package simulator
+
+import (
+	"testing"
+
+	"github.com/Glider2355/ddl-lock-analyzer/internal/meta"
+	"github.com/Glider2355/ddl-lock-analyzer/internal/rollback"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func newTestSimulator() *Simulator {
+	return New(meta.NewFileCollector([]meta.TableMeta{
+		{
+			Schema: "mydb",
+			Table:  "users",
+			Engine: "InnoDB",
+			Columns: []meta.ColumnMeta{
+				{Name: "id", OrdinalPos: 1, DataType: "bigint", ColumnType: "bigint", ColumnKey: "PRI"},
+				{Name: "name", OrdinalPos: 2, DataType: "varchar", ColumnType: "varchar(100)", IsNullable: true, CharacterSet: "utf8mb4"},
+			},
+			Indexes: []meta.IndexMeta{
+				{Name: "PRIMARY", Columns: []string{"id"}, IsUnique: true, IsPrimary: true, IndexType: "BTREE"},
+				{Name: "idx_name", Columns: []string{"name"}, IndexType: "BTREE"},
+			},
+		},
+		{
+			Schema: "mydb",
+			Table:  "orders",
+			Columns: []meta.ColumnMeta{
+				{Name: "id", OrdinalPos: 1, ColumnType: "bigint"},
+				{Name: "user_id", OrdinalPos: 2, ColumnType: "bigint"},
+			},
+			ForeignKeys: []meta.ForeignKeyMeta{
+				{ConstraintName: "fk_orders_user", SourceColumns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+			},
+		},
+	}, "8.0.32"))
+}
+
+// apply は現在のメタデータを取得して op を適用する。
+func apply(t *testing.T, s *Simulator, op meta.AlterOperation) {
+	t.Helper()
+	tm, err := s.GetTableMeta("mydb", op.Table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Apply(tm, op, meta.AlgorithmInstant, false)
+}
+
+func getTable(t *testing.T, s *Simulator, table string) *meta.TableMeta {
+	t.Helper()
+	tm, err := s.GetTableMeta("mydb", table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestSimulatorAddThenModifyColumn(t *testing.T) {
+	// 前の文で追加したカラムが後続の文から参照できることを検証
+	s := newTestSimulator()
+	apply(t, s, meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{{
+		Type:   meta.ActionAddColumn,
+		Detail: meta.ActionDetail{ColumnName: "nickname", ColumnType: "VARCHAR(20)", IsNullable: boolPtr(true), Position: "AFTER id"},
+	}}})
+
+	tm := getTable(t, s, "users")
+	if len(tm.Columns) != 3 {
+		t.Fatalf("カラム数が3であること: got %d", len(tm.Columns))
+	}
+	col := tm.Columns[1]
+	if col.Name != "nickname" || col.OrdinalPos != 2 || col.ColumnType != "varchar(20)" || col.DataType != "varchar" {
+		t.Errorf("nicknameがidの直後に追加されること: got %+v", col)
+	}
+	if tm.Columns[2].OrdinalPos != 3 {
+		t.Errorf("後続カラムの位置が繰り下がること: got %d", tm.Columns[2].OrdinalPos)
+	}
+
+	apply(t, s, meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{{
+		Type:   meta.ActionModifyColumn,
+		Detail: meta.ActionDetail{ColumnName: "nickname", ColumnType: "VARCHAR(40)", IsNullable: boolPtr(false)},
+	}}})
+	col = getTable(t, s, "users").Columns[1]
+	if col.ColumnType != "varchar(40)" || col.IsNullable {
+		t.Errorf("MODIFY後の定義が反映されること: got %+v", col)
+	}
+}
+
+func TestSimulatorDoesNotMutateReturnedMeta(t *testing.T) {
+	// 適用前に取得したメタデータが変更されないことを検証
+	s := newTestSimulator()
+	before := getTable(t, s, "users")
+	s.Apply(before, meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{{
+		Type:   meta.ActionDropColumn,
+		Detail: meta.ActionDetail{ColumnName: "name"},
+	}}}, meta.AlgorithmInstant, false)
+	if len(before.Columns) != 2 || len(before.Indexes) != 2 {
+		t.Errorf("適用前のメタデータは変更されないこと: got %d columns, %d indexes", len(before.Columns), len(before.Indexes))
+	}
+	after := getTable(t, s, "users")
+	if len(after.Columns) != 1 || len(after.Indexes) != 1 {
+		t.Errorf("カラムとそれだけを含むインデックスが削除されること: got %d columns, %d indexes", len(after.Columns), len(after.Indexes))
+	}
+}
+
+func TestSimulatorColumnRename(t *testing.T) {
+	// カラム名変更がインデックスにも反映されることを検証
+	tests := []struct {
+		name   string
+		action meta.AlterAction
+	}{
+		{"RENAME COLUMN", meta.AlterAction{Type: meta.ActionRenameColumn, Detail: meta.ActionDetail{ColumnName: "full_name", OldColumnName: "name"}}},
+		{"CHANGE COLUMN", meta.AlterAction{Type: meta.ActionChangeColumn, Detail: meta.ActionDetail{ColumnName: "full_name", OldColumnName: "name", ColumnType: "VARCHAR(100)"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSimulator()
+			apply(t, s, meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{tt.action}})
+			tm := getTable(t, s, "users")
+			if tm.Columns[1].Name != "full_name" {
+				t.Errorf("カラム名がfull_nameになること: got %s", tm.Columns[1].Name)
+			}
+			if tm.Columns[1].CharacterSet != "utf8mb4" {
+				t.Errorf("文字セットが引き継がれること: got %q", tm.Columns[1].CharacterSet)
+			}
+			if tm.Indexes[1].Columns[0] != "full_name" {
+				t.Errorf("インデックスのカラム名も変更されること: got %v", tm.Indexes[1].Columns)
+			}
+		})
+	}
+}
+
+func TestSimulatorIndexes(t *testing.T) {
+	// インデックスと主キーの追加・削除・名前変更を検証
+	s := newTestSimulator()
+	apply(t, s, meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{
+		{Type: meta.ActionAddFulltextIndex, Detail: meta.ActionDetail{IndexName: "ft_name", IndexColumns: []string{"name"}}},
+		{Type: meta.ActionRenameIndex, Detail: meta.ActionDetail{OldIndexName: "idx_name", IndexName: "idx_name2"}},
+		{Type: meta.ActionDropPrimaryKey},
+		{Type: meta.ActionAddPrimaryKey, Detail: meta.ActionDetail{IndexColumns: []string{"id", "name"}}},
+	}})
+	tm := getTable(t, s, "users")
+
+	names := map[string]meta.IndexMeta{}
+	for _, idx := range tm.Indexes {
+		names[idx.Name] = idx
+	}
+	if idx, ok := names["ft_name"]; !ok || idx.IndexType != "FULLTEXT" {
+		t.Errorf("FULLTEXTインデックスが追加されること: got %+v", tm.Indexes)
+	}
+	if _, ok := names["idx_name2"]; !ok {
+		t.Errorf("インデックス名が変更されること: got %+v", tm.Indexes)
+	}
+	if pk := names["PRIMARY"]; len(pk.Columns) != 2 {
+		t.Errorf("主キーが置き換わること: got %+v", pk)
+	}
+	if tm.Columns[1].IsNullable {
+		t.Error("主キーに含まれるカラムはNOT NULLになること")
+	}
+}
+
+func TestSimulatorForeignKeys(t *testing.T) {
+	// FKの削除・追加が参照先テーブルのReferencedByにも反映されることを検証
+	s := newTestSimulator()
+	if got := len(getTable(t, s, "users").ReferencedBy); got != 1 {
+		t.Fatalf("初期状態でusersが1件参照されていること: got %d", got)
+	}
+
+	apply(t, s, meta.AlterOperation{Table: "orders", Actions: []meta.AlterAction{{
+		Type:   meta.ActionDropForeignKey,
+		Detail: meta.ActionDetail{ConstraintName: "fk_orders_user"},
+	}}})
+	if got := len(getTable(t, s, "users").ReferencedBy); got != 0 {
+		t.Errorf("FK削除後はusersが参照されないこと: got %d", got)
+	}
+
+	apply(t, s, meta.AlterOperation{Table: "orders", Actions: []meta.AlterAction{{
+		Type:   meta.ActionAddForeignKey,
+		Detail: meta.ActionDetail{ConstraintName: "fk_new", IndexColumns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+	}}})
+	users := getTable(t, s, "users")
+	if len(users.ReferencedBy) != 1 || users.ReferencedBy[0].ConstraintName != "fk_new" {
+		t.Errorf("FK追加後はusersがfk_newで参照されること: got %+v", users.ReferencedBy)
+	}
+	orders := getTable(t, s, "orders")
+	if len(orders.Indexes) != 1 || orders.Indexes[0].Name != "fk_new" {
+		t.Errorf("FK用インデックスが暗黙作成されること: got %+v", orders.Indexes)
+	}
+}
+
+func TestSimulatorRenameTable(t *testing.T) {
+	// テーブル名変更後は新しい名前で参照でき、FK関連テーブルも更新されることを検証
+	s := newTestSimulator()
+	apply(t, s, meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{{
+		Type:   meta.ActionRenameTable,
+		Detail: meta.ActionDetail{ColumnName: "members"},
+	}}})
+
+	if _, err := s.GetTableMeta("mydb", "users"); err == nil {
+		t.Error("変更前の名前ではエラーになること")
+	}
+	members := getTable(t, s, "members")
+	if len(members.ReferencedBy) != 1 || members.ReferencedBy[0].ReferencedTable != "members" {
+		t.Errorf("ReferencedByの参照先が新しい名前になること: got %+v", members.ReferencedBy)
+	}
+	orders := getTable(t, s, "orders")
+	if orders.ForeignKeys[0].ReferencedTable != "members" {
+		t.Errorf("子テーブルのFK参照先が新しい名前になること: got %s", orders.ForeignKeys[0].ReferencedTable)
+	}
+}
+
+func TestSimulatorTableOptions(t *testing.T) {
+	// エンジン変更とパーティション化を検証
+	s := newTestSimulator()
+	apply(t, s, meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{
+		{Type: meta.ActionChangeEngine, Detail: meta.ActionDetail{Engine: "MyISAM"}},
+		{Type: meta.ActionPartitionBy, Detail: meta.ActionDetail{PartitionType: "HASH"}},
+	}})
+	tm := getTable(t, s, "users")
+	if tm.Engine != "MyISAM" {
+		t.Errorf("エンジンがMyISAMになること: got %s", tm.Engine)
+	}
+	if !tm.IsPartitioned || tm.PartitionType != "HASH" {
+		t.Errorf("HASHパーティションになること: got %v %s", tm.IsPartitioned, tm.PartitionType)
+	}
+
+	apply(t, s, meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{{Type: meta.ActionRemovePartitioning}}})
+	if tm := getTable(t, s, "users"); tm.IsPartitioned {
+		t.Error("REMOVE PARTITIONING後はパーティションなしになること")
+	}
+}
+
+func TestSimulatorRowVersions(t *testing.T) {
+	// INSTANT ADD/DROP COLUMN は文単位で行バージョンを消費し、再構築で0に戻ることを検証
+	s := newTestSimulator()
+	addTwo := meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{
+		{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "a", ColumnType: "int", IsNullable: boolPtr(true)}},
+		{Type: meta.ActionAddColumn, Detail: meta.ActionDetail{ColumnName: "b", ColumnType: "int", IsNullable: boolPtr(true)}},
+	}}
+	addIndex := meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{
+		{Type: meta.ActionAddIndex, Detail: meta.ActionDetail{IndexName: "idx_a", IndexColumns: []string{"a"}}},
+	}}
+	dropColumn := meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{
+		{Type: meta.ActionDropColumn, Detail: meta.ActionDetail{ColumnName: "b"}},
+	}}
+
+	steps := []struct {
+		op        meta.AlterOperation
+		algorithm meta.Algorithm
+		rebuild   bool
+		want      int
+	}{
+		{addTwo, meta.AlgorithmInstant, false, 1},
+		{addIndex, meta.AlgorithmInplace, false, 1},
+		{dropColumn, meta.AlgorithmInstant, true, 2},
+		{meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{{Type: meta.ActionForceRebuild}}}, meta.AlgorithmInplace, true, 0},
+	}
+	for i, step := range steps {
+		tm := getTable(t, s, "users")
+		s.Apply(tm, step.op, step.algorithm, step.rebuild)
+		if got := getTable(t, s, "users").TotalRowVersions; got != step.want {
+			t.Errorf("step %d: 行バージョン数が %d であること: got %d", i+1, step.want, got)
+		}
+	}
+}
+
+func TestSimulatorSetDefault(t *testing.T) {
+	// SET DEFAULT が後続の文と元に戻す DDL に反映されることを検証
+	s := newTestSimulator()
+	apply(t, s, meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{{
+		Type:   meta.ActionSetDefault,
+		Detail: meta.ActionDetail{ColumnName: "name", DefaultValue: "_UTF8MB4'it''s'"},
+	}}})
+	tm := getTable(t, s, "users")
+	if got := tm.Columns[1].DefaultValue; got != "it's" {
+		t.Fatalf("デフォルト値が information_schema と同じ形式で設定されること: got %q", got)
+	}
+
+	plan := rollback.Build(meta.AlterOperation{Schema: "mydb", Table: "users", Actions: []meta.AlterAction{{
+		Type:   meta.ActionSetDefault,
+		Detail: meta.ActionDetail{ColumnName: "name", DefaultValue: "'guest'"},
+	}}}, tm)
+	if want := "ALTER TABLE `mydb`.`users` ALTER COLUMN `name` SET DEFAULT 'it''s';"; plan.SQL != want {
+		t.Errorf("前の文で設定したデフォルト値に戻すこと:\ngot  %s\nwant %s", plan.SQL, want)
+	}
+
+	apply(t, s, meta.AlterOperation{Table: "users", Actions: []meta.AlterAction{{
+		Type:   meta.ActionDropDefault,
+		Detail: meta.ActionDetail{ColumnName: "name"},
+	}}})
+	if got := getTable(t, s, "users").Columns[1].DefaultValue; got != "" {
+		t.Errorf("DROP DEFAULT でデフォルト値がなくなること: got %q", got)
+	}
+}
+
+func TestColumnDefault(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    string
+		wantHas bool
+	}{
+		{"'active'", "active", true},
+		{"_UTF8MB4'a''b'", "a'b", true},
+		{"''", "", true},
+		{"0", "0", true},
+		{"CURRENT_TIMESTAMP()", "CURRENT_TIMESTAMP", true},
+		{"NULL", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got, has := columnDefault(tt.expr); got != tt.want || has != tt.wantHas {
+			t.Errorf("columnDefault(%q) = %q, %v, want %q, %v", tt.expr, got, has, tt.want, tt.wantHas)
+		}
+	}
+}